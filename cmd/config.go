@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/config"
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
+)
+
+func loadConfig(deps Deps) (config.Config, error) {
+	if deps.ConfigPath == nil {
+		return config.Config{}, nil
+	}
+	path, err := deps.ConfigPath()
+	if err != nil {
+		return config.Config{}, err
+	}
+	return config.Load(path)
+}
+
+func keyMapFromConfig(k config.Keys) tui.KeyMap {
+	return tui.KeyMap{
+		Left:      k.Left,
+		Right:     k.Right,
+		Retry:     k.Retry,
+		Quit:      k.Quit,
+		SpeedUp:   k.SpeedUp,
+		SpeedDown: k.SpeedDown,
+		Theme:     k.Theme,
+		Launch:    k.Launch,
+		Inspect:   k.Inspect,
+		Up:        k.Up,
+		Down:      k.Down,
+		Boss:      k.Boss,
+	}
+}
+
+// loadThemes returns the built-in themes followed by user themes from file, or from
+// themes.yaml next to the config file when file is empty. A user theme named like a
+// built-in one replaces it.
+func loadThemes(deps Deps, file string) ([]tui.Theme, error) {
+	themes := tui.BuiltinThemes()
+	if file != "" {
+		// Only the default themes.yaml is optional.
+		if _, err := os.Stat(file); err != nil {
+			return nil, fmt.Errorf("theme file: %w", err)
+		}
+	} else {
+		if deps.ConfigPath == nil {
+			return themes, nil
+		}
+		path, err := deps.ConfigPath()
+		if err != nil {
+			return nil, err
+		}
+		file = config.ThemesPath(path)
+	}
+	user, err := tui.LoadThemes(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range user {
+		if i, ok := tui.FindTheme(themes, t.Name); ok {
+			themes[i] = t
+		} else {
+			themes = append(themes, t)
+		}
+	}
+	return themes, nil
+}
+
+// effectiveConfig fills unset fields with the built-in defaults so `config show`
+// prints what a game would actually use (before flags).
+func effectiveConfig(c config.Config) config.Config {
+	if c.Speed <= 0 {
+		c.Speed = 1.0
+	}
+	if c.SpeedRamp == "" {
+		c.SpeedRamp = game.DefaultSpeedRamp.String()
+	}
+	if c.Host == "" {
+		c.Host = github.DefaultHost
+	}
+	// Lives and the HP scale default to the difficulty preset.
+	preset, err := game.ParseDifficulty(c.Difficulty)
+	if err != nil {
+		preset = game.DefaultDifficulty
+	}
+	if c.Difficulty == "" {
+		c.Difficulty = preset.Name
+	}
+	if c.Lives <= 0 {
+		c.Lives = preset.Lives
+	}
+	if c.HPScale == "" {
+		c.HPScale = preset.HPScale
+	}
+	if c.Compress == "" {
+		c.Compress = mapping.CompressMax.String()
+	}
+	if c.Layout == "" {
+		c.Layout = mapping.LayoutWeeks.String()
+	}
+	if c.Theme == "" {
+		c.Theme = tui.BuiltinThemes()[0].Name
+	}
+	if c.Glyphs == "" {
+		c.Glyphs = tui.GlyphsOff.String()
+	}
+	if c.SubCell == "" {
+		c.SubCell = tui.SubCellOff.String()
+	}
+	if c.Sound == "" {
+		c.Sound = sound.ModeOff.String()
+	}
+	def := tui.DefaultKeyMap()
+	fill := func(keys *[]string, fallback []string) {
+		if len(*keys) == 0 {
+			*keys = fallback
+		}
+	}
+	fill(&c.Keys.Left, def.Left)
+	fill(&c.Keys.Right, def.Right)
+	fill(&c.Keys.Retry, def.Retry)
+	fill(&c.Keys.Quit, def.Quit)
+	fill(&c.Keys.SpeedUp, def.SpeedUp)
+	fill(&c.Keys.SpeedDown, def.SpeedDown)
+	fill(&c.Keys.Theme, def.Theme)
+	fill(&c.Keys.Launch, def.Launch)
+	fill(&c.Keys.Inspect, def.Inspect)
+	fill(&c.Keys.Up, def.Up)
+	fill(&c.Keys.Down, def.Down)
+	fill(&c.Keys.Boss, def.Boss)
+	return c
+}
+
+func newConfigCmd(deps Deps) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect or scaffold the config file",
+	}
+
+	configPath := func() (string, error) {
+		if deps.ConfigPath == nil {
+			return "", fmt.Errorf("config file is not available")
+		}
+		return deps.ConfigPath()
+	}
+
+	c.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Print the config file location",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configPath()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		},
+	})
+
+	c.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the effective config (file values over built-in defaults)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(deps)
+			if err != nil {
+				return err
+			}
+			out, err := effectiveConfig(cfg).Marshal()
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	})
+
+	var force bool
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Write a commented config file scaffold",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configPath()
+			if err != nil {
+				return err
+			}
+			if err := config.Init(path, force); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", path)
+			return nil
+		},
+	}
+	initCmd.Flags().BoolVar(&force, "force", false, "overwrite an existing config file")
+	c.AddCommand(initCmd)
+
+	return c
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
+	"github.com/fchimpan/gh-kusa-breaker/internal/config"
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
+)
+
+// netFlags are the flags shared by host and join: whose calendar to play and
+// how to draw it. Game settings come from the host. watch only has the
+// drawing ones.
+type netFlags struct {
+	user  string
+	host  string
+	theme string
+	ascii bool
+}
+
+func (f *netFlags) register(c *cobra.Command) {
+	c.Flags().StringVarP(&f.user, "user", "u", "", "GitHub username whose calendar is your board (default: authenticated user)")
+	c.Flags().StringVar(&f.host, "host", "", "GitHub host, e.g. a GitHub Enterprise Server hostname (default: github.com)")
+	c.Flags().StringVar(&f.theme, "theme", "classic", "color theme (t cycles in game)")
+	c.Flags().BoolVar(&f.ascii, "ascii", false, "plain ASCII rendering without colors")
+}
+
+// apply fills unset flags from cfg and returns the display options.
+func (f *netFlags) apply(cmd *cobra.Command, deps Deps, cfg config.Config) (tui.Options, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("user") && cfg.User != "" {
+		f.user = cfg.User
+	}
+	if !flags.Changed("host") && cfg.Host != "" {
+		f.host = cfg.Host
+	}
+	if !flags.Changed("theme") && cfg.Theme != "" {
+		f.theme = cfg.Theme
+	}
+	if !flags.Changed("ascii") && cfg.ASCII {
+		f.ascii = true
+	}
+	themes, err := loadThemes(deps, cfg.ThemeFile)
+	if err != nil {
+		return tui.Options{}, err
+	}
+	if _, ok := tui.FindTheme(themes, f.theme); !ok {
+		return tui.Options{}, fmt.Errorf("unknown --theme %q (expected one of: %s)", f.theme, strings.Join(tui.ThemeNames(themes), ", "))
+	}
+	return tui.Options{
+		Keys:   keyMapFromConfig(cfg.Keys),
+		Themes: themes,
+		Theme:  f.theme,
+		ASCII:  f.ascii || !tui.ColorSupported(),
+	}, nil
+}
+
+func newHostCmd(deps Deps) *cobra.Command {
+	var nf netFlags
+	var listen string
+	var speed float64
+	var difficulty string
+	var lives int
+	var speedRamp string
+	var timeLimit time.Duration
+
+	c := &cobra.Command{
+		Use:   "host",
+		Short: "Host a head-to-head match over the network",
+		Long: "Host a head-to-head match over the network. The other player runs\n" +
+			"`kusa-breaker join HOST:PORT`; the host's settings apply to both boards.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(deps)
+			if err != nil {
+				return err
+			}
+			opts, err := nf.apply(cmd, deps, cfg)
+			if err != nil {
+				return err
+			}
+			flags := cmd.Flags()
+			if !flags.Changed("difficulty") && cfg.Difficulty != "" {
+				difficulty = cfg.Difficulty
+			}
+			if !flags.Changed("speed") && cfg.Speed > 0 {
+				speed = cfg.Speed
+			}
+			if !flags.Changed("speed-ramp") && cfg.SpeedRamp != "" {
+				speedRamp = cfg.SpeedRamp
+			}
+			if !flags.Changed("lives") && cfg.Lives > 0 {
+				lives = cfg.Lives
+			}
+			preset, err := game.ParseDifficulty(difficulty)
+			if err != nil {
+				return fmt.Errorf("invalid --difficulty: %w", err)
+			}
+			ramp, err := game.ParseSpeedRamp(speedRamp)
+			if err != nil {
+				return fmt.Errorf("invalid --speed-ramp: %w", err)
+			}
+			if speed <= 0 {
+				return fmt.Errorf("--speed must be > 0")
+			}
+			if lives < 0 {
+				return fmt.Errorf("--lives must be >= 0 (0 uses the --difficulty default)")
+			}
+			if timeLimit <= 0 {
+				return fmt.Errorf("--time-limit must be > 0")
+			}
+			if deps.Listen == nil {
+				return fmt.Errorf("deps.Listen is nil")
+			}
+
+			settings := netplay.Settings{
+				Seed:       uint64(deps.Now().UnixNano()),
+				Difficulty: preset.Name,
+				Lives:      lives,
+				SpeedRamp:  ramp.String(),
+				HPScale:    cfg.HPScale,
+				Speed:      speed,
+				Steps:      tui.NetSteps(timeLimit, speed),
+			}
+			ctx := github.WithHost(cmd.Context(), nf.host)
+			return runNet(ctx, deps, nf.user, opts, func(me netplay.Player) (*netplay.Match, error) {
+				ln, err := deps.Listen("tcp", listen)
+				if err != nil {
+					return nil, err
+				}
+				defer ln.Close()
+				fmt.Fprintf(deps.Stderr, "waiting for a player on %s (they run: kusa-breaker join HOST:PORT)...\n", ln.Addr())
+				return netplay.Host(ctx, ln, me, settings)
+			})
+		},
+	}
+
+	nf.register(c)
+	c.Flags().StringVar(&listen, "listen", ":7777", "address to accept the other player on")
+	c.Flags().Float64VarP(&speed, "speed", "s", 1.0, "game speed multiplier for both players")
+	c.Flags().StringVar(&difficulty, "difficulty", "normal", "preset for both boards: "+strings.Join(game.DifficultyNames(), ", "))
+	c.Flags().IntVar(&lives, "lives", 0, "number of balls per board (default: from --difficulty)")
+	c.Flags().StringVar(&speedRamp, "speed-ramp", game.DefaultSpeedRamp.String(), "speed the ball up during rallies (see kusa-breaker --help)")
+	c.Flags().DurationVar(&timeLimit, "time-limit", 3*time.Minute, "length of the match; the higher score wins if nobody clears")
+	return c
+}
+
+func newJoinCmd(deps Deps) *cobra.Command {
+	var nf netFlags
+
+	c := &cobra.Command{
+		Use:   "join HOST:PORT",
+		Short: "Join a head-to-head match hosted with `kusa-breaker host`",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(deps)
+			if err != nil {
+				return err
+			}
+			opts, err := nf.apply(cmd, deps, cfg)
+			if err != nil {
+				return err
+			}
+			ctx := github.WithHost(cmd.Context(), nf.host)
+			return runNet(ctx, deps, nf.user, opts, func(me netplay.Player) (*netplay.Match, error) {
+				fmt.Fprintf(deps.Stderr, "joining %s...\n", args[0])
+				return netplay.Join(ctx, args[0], me)
+			})
+		},
+	}
+
+	nf.register(c)
+	return c
+}
+
+// runNet fetches user's calendar, connects with connect and plays the match.
+func runNet(ctx context.Context, deps Deps, user string, opts tui.Options, connect func(me netplay.Player) (*netplay.Match, error)) error {
+	if err := checkFetchDeps(deps); err != nil {
+		return err
+	}
+	if deps.RunNetTUI == nil {
+		return fmt.Errorf("deps.RunNetTUI is nil")
+	}
+
+	login, cal, err := fetch(ctx, deps, user, defaultWeeks, nil, nil)
+	if err != nil {
+		if github.IsAuthError(err) {
+			fmt.Fprintln(deps.Stderr, "hint: set GITHUB_TOKEN environment variable or run `gh auth login`")
+		}
+		return err
+	}
+	match, err := connect(netplay.Player{Login: login, Calendar: cal})
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer match.Close()
+	return deps.RunNetTUI(match, opts)
+}
+
+func newWatchCmd(deps Deps) *cobra.Command {
+	var nf netFlags
+
+	c := &cobra.Command{
+		Use:   "watch HOST:PORT",
+		Short: "Watch a game streamed with `kusa-breaker --broadcast` (read-only)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if deps.RunWatchTUI == nil {
+				return fmt.Errorf("deps.RunWatchTUI is nil")
+			}
+			cfg, err := loadConfig(deps)
+			if err != nil {
+				return err
+			}
+			opts, err := nf.apply(cmd, deps, cfg)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+			stream, err := broadcast.Dial(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to connect: %w", err)
+			}
+			defer stream.Close()
+			return deps.RunWatchTUI(stream, opts)
+		},
+	}
+
+	c.Flags().StringVar(&nf.theme, "theme", "classic", "color theme (t cycles in game)")
+	c.Flags().BoolVar(&nf.ascii, "ascii", false, "plain ASCII rendering without colors")
+	return c
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
+)
+
+// netDeps returns deps whose fetches return a one-week calendar for the user
+// ("me" when unset) and whose RunNetTUI sends the match to got.
+func netDeps(t *testing.T, got chan<- *netplay.Match) Deps {
+	cal := github.Calendar{Weeks: []github.Week{{ContributionDays: []github.Day{{Date: "2025-01-05", ContributionCount: 3}}}}}
+	return Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			return "me", cal, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			return user, cal, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchUserCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		RunNetTUI: func(match *netplay.Match, opts tui.Options) error {
+			got <- match
+			return nil
+		},
+		Now:    func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+}
+
+func TestHostAndJoin_Loopback(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(chan *netplay.Match, 2)
+
+	hostDeps := netDeps(t, got)
+	var hostStderr bytes.Buffer
+	hostDeps.Stderr = &hostStderr
+	hostDeps.Listen = func(network, address string) (net.Listener, error) {
+		if address != ":9000" {
+			t.Errorf("unexpected listen address %q", address)
+		}
+		return ln, nil
+	}
+	hostErr := make(chan error, 1)
+	go func() {
+		cmd := NewRootCmd(hostDeps)
+		cmd.SetArgs([]string{"host", "--listen", ":9000", "--user", "alice", "--difficulty", "hard", "--time-limit", "90s", "--speed", "2"})
+		hostErr <- cmd.Execute()
+	}()
+
+	cmd := NewRootCmd(netDeps(t, got))
+	cmd.SetArgs([]string{"join", ln.Addr().String()})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if err := <-hostErr; err != nil {
+		t.Fatalf("host: %v", err)
+	}
+	if !strings.Contains(hostStderr.String(), "waiting for a player on "+ln.Addr().String()) {
+		t.Fatalf("host should print where it listens, got %q", hostStderr.String())
+	}
+
+	for range 2 {
+		m := <-got
+		if m.Players[0].Login != "alice" || m.Players[1].Login != "me" {
+			t.Fatalf("unexpected players %q, %q", m.Players[0].Login, m.Players[1].Login)
+		}
+		if m.Settings.Difficulty != "hard" || m.Settings.Steps != tui.NetSteps(90*time.Second, 2) || m.Settings.Seed == 0 {
+			t.Fatalf("both ends should use the host's settings, got %+v", m.Settings)
+		}
+	}
+}
+
+func TestHostAndJoin_Errors(t *testing.T) {
+	t.Parallel()
+
+	deps := netDeps(t, make(chan *netplay.Match, 1))
+	deps.Listen = func(network, address string) (net.Listener, error) {
+		t.Fatalf("Listen should not be called on invalid flags")
+		return nil, nil
+	}
+	for _, args := range [][]string{
+		{"host", "--difficulty", "nightmare"},
+		{"host", "--time-limit", "0s"},
+		{"host", "--speed-ramp", "fast"},
+		{"join"},
+		{"join", "127.0.0.1:1", "--theme", "nope"},
+	} {
+		cmd := NewRootCmd(deps)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+
+	// Nobody listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"join", addr})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Fatalf("expected a connection error, got %v", err)
+	}
+}
+
+func TestBroadcastAndWatch_Loopback(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	watched := make(chan string, 1)
+	watchDeps := netDeps(t, nil)
+	watchDeps.RunWatchTUI = func(stream *broadcast.Stream, opts tui.Options) error {
+		for {
+			f, err := stream.Next()
+			if err != nil {
+				return err
+			}
+			if f.Key {
+				watched <- f.Login
+				return nil
+			}
+		}
+	}
+
+	deps := netDeps(t, nil)
+	var stderr bytes.Buffer
+	deps.Stderr = &stderr
+	deps.Listen = func(network, address string) (net.Listener, error) {
+		if address != ":9001" {
+			t.Errorf("unexpected listen address %q", address)
+		}
+		return ln, nil
+	}
+	deps.RunTUI = func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+		if opts.Broadcast == nil {
+			t.Fatalf("--broadcast should reach the game")
+		}
+		watchErr := make(chan error, 1)
+		go func() {
+			cmd := NewRootCmd(watchDeps)
+			cmd.SetArgs([]string{"watch", ln.Addr().String(), "--ascii"})
+			watchErr <- cmd.Execute()
+		}()
+		grid := mapping.BuildBrickGrid(cal, 10, mapping.GridOptions{MaxRows: game.MaxBrickRows(12)})
+		st := game.NewState(grid, 20, 12, seed)
+		for {
+			select {
+			case err := <-watchErr:
+				return err
+			case <-time.After(time.Millisecond):
+				opts.Broadcast.Publish(login, &st, opts.Speed, 1)
+			}
+		}
+	}
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"--broadcast", ":9001"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if login := <-watched; login != "me" {
+		t.Fatalf("the watcher should see the player's game, got %q", login)
+	}
+	if !strings.Contains(stderr.String(), "broadcasting on "+ln.Addr().String()) {
+		t.Fatalf("the player should be told where watchers connect, got %q", stderr.String())
+	}
+
+	// --broadcast is for single-player games; watch needs an address.
+	for _, args := range [][]string{
+		{"--versus", "alice,bob", "--broadcast", ":9001"},
+		{"watch"},
+	} {
+		cmd := NewRootCmd(deps)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+}
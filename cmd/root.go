@@ -5,12 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
+	"github.com/fchimpan/gh-kusa-breaker/internal/config"
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
 )
 
 type Deps struct {
@@ -18,10 +27,17 @@ type Deps struct {
 	FetchUserCalendar      func(ctx context.Context, user string, weeks int) (string, github.Calendar, error)
 	FetchCalendarRange     func(ctx context.Context, from, to time.Time) (string, github.Calendar, error)
 	FetchUserCalendarRange func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error)
-	RunTUI                 func(login string, cal github.Calendar, seed uint64, speed float64) error
-	Now                    func() time.Time
-	Stdout                 io.Writer
-	Stderr                 io.Writer
+	RunTUI                 func(login string, cal github.Calendar, seed uint64, opts tui.Options) error
+	RunVersusTUI           func(players [2]tui.Player, seed uint64, opts tui.Options) error
+	RunNetTUI              func(match *netplay.Match, opts tui.Options) error
+	RunWatchTUI            func(stream *broadcast.Stream, opts tui.Options) error
+	// Listen opens the listeners of `host` and --broadcast.
+	Listen func(network, address string) (net.Listener, error)
+	// ConfigPath locates the config file. nil disables config loading.
+	ConfigPath func() (string, error)
+	Now        func() time.Time
+	Stdout     io.Writer
+	Stderr     io.Writer
 }
 
 func DefaultDeps() Deps {
@@ -31,6 +47,11 @@ func DefaultDeps() Deps {
 		FetchCalendarRange:     github.FetchViewerContributionCalendarRange,
 		FetchUserCalendarRange: github.FetchUserContributionCalendarRange,
 		RunTUI:                 defaultRunTUI,
+		RunVersusTUI:           defaultRunVersusTUI,
+		RunNetTUI:              defaultRunNetTUI,
+		RunWatchTUI:            defaultRunWatchTUI,
+		Listen:                 net.Listen,
+		ConfigPath:             config.DefaultPath,
 		Now:                    time.Now,
 		Stdout:                 os.Stdout,
 		Stderr:                 os.Stderr,
@@ -38,20 +59,181 @@ func DefaultDeps() Deps {
 }
 
 func NewRootCmd(deps Deps) *cobra.Command {
-	const defaultWeeks = 52
 	var speed float64
 	var user string
 	var fromStr string
 	var toStr string
+	var host string
+	var lives int
+	var difficulty string
+	var speedRamp string
+	var versus string
+	var timeLimit time.Duration
+	var hpScale string
+	var compress string
+	var layout string
+	var brickWidth int
+	var brickHeight int
+	var gutters bool
+	var theme string
+	var themeFile string
+	var glyphs string
+	var ascii bool
+	var mouse bool
+	var subcell string
+	var noEffects bool
+	var soundMode string
+	var soundFile string
+	var broadcastAddr string
 
 	c := &cobra.Command{
 		Use:          "kusa-breaker",
 		Short:        "Play breakout using your GitHub contribution heatmap as bricks",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(deps)
+			if err != nil {
+				return err
+			}
+			// Flags override config values.
+			flags := cmd.Flags()
+			if !flags.Changed("difficulty") && cfg.Difficulty != "" {
+				difficulty = cfg.Difficulty
+			}
+			preset, err := game.ParseDifficulty(difficulty)
+			if err != nil {
+				return fmt.Errorf("invalid --difficulty: %w", err)
+			}
+			if !flags.Changed("speed") && cfg.Speed > 0 {
+				speed = cfg.Speed
+			}
+			if !flags.Changed("speed-ramp") && cfg.SpeedRamp != "" {
+				speedRamp = cfg.SpeedRamp
+			}
+			if !flags.Changed("user") && cfg.User != "" {
+				user = cfg.User
+			}
+			if !flags.Changed("host") && cfg.Host != "" {
+				host = cfg.Host
+			}
+			// The preset picks lives and the HP scale unless they are set.
+			if !flags.Changed("lives") {
+				lives = preset.Lives
+				if cfg.Lives > 0 {
+					lives = cfg.Lives
+				}
+			}
+			if !flags.Changed("hp-scale") {
+				hpScale = preset.HPScale
+				if cfg.HPScale != "" {
+					hpScale = cfg.HPScale
+				}
+			}
+			if !flags.Changed("compress") && cfg.Compress != "" {
+				compress = cfg.Compress
+			}
+			if !flags.Changed("layout") && cfg.Layout != "" {
+				layout = cfg.Layout
+			}
+			if !flags.Changed("theme") && cfg.Theme != "" {
+				theme = cfg.Theme
+			}
+			if !flags.Changed("theme-file") && cfg.ThemeFile != "" {
+				themeFile = cfg.ThemeFile
+			}
+			if !flags.Changed("glyphs") && cfg.Glyphs != "" {
+				glyphs = cfg.Glyphs
+			}
+			if !flags.Changed("ascii") && cfg.ASCII {
+				ascii = true
+			}
+			if !flags.Changed("mouse") && cfg.Mouse {
+				mouse = true
+			}
+			if !flags.Changed("subcell") && cfg.SubCell != "" {
+				subcell = cfg.SubCell
+			}
+			if !flags.Changed("no-effects") && cfg.NoEffects {
+				noEffects = true
+			}
+			if !flags.Changed("sound") && cfg.Sound != "" {
+				soundMode = cfg.Sound
+			}
+			if !flags.Changed("sound-file") && cfg.SoundFile != "" {
+				soundFile = cfg.SoundFile
+			}
+
 			if speed <= 0 {
 				return fmt.Errorf("--speed must be > 0")
 			}
+			if lives <= 0 {
+				return fmt.Errorf("--lives must be > 0")
+			}
+			ramp, err := game.ParseSpeedRamp(speedRamp)
+			if err != nil {
+				return fmt.Errorf("invalid --speed-ramp: %w", err)
+			}
+			scale, err := mapping.ParseHPScale(hpScale)
+			if err != nil {
+				return fmt.Errorf("invalid --hp-scale: %w", err)
+			}
+			compression, err := mapping.ParseCompression(compress)
+			if err != nil {
+				return fmt.Errorf("invalid --compress: %w", err)
+			}
+			boardLayout, err := mapping.ParseLayout(layout)
+			if err != nil {
+				return fmt.Errorf("invalid --layout: %w", err)
+			}
+			if brickWidth < 0 || brickWidth > 3 {
+				return fmt.Errorf("--brick-width must be between 0 (auto) and 3")
+			}
+			if brickHeight < 1 || brickHeight > 3 {
+				return fmt.Errorf("--brick-height must be between 1 and 3")
+			}
+			if compression == mapping.CompressScroll && boardLayout != mapping.LayoutWeeks && boardLayout != mapping.LayoutMirrored {
+				return fmt.Errorf("--compress scroll requires --layout weeks or mirrored")
+			}
+			themes, err := loadThemes(deps, themeFile)
+			if err != nil {
+				return err
+			}
+			if _, ok := tui.FindTheme(themes, theme); !ok {
+				return fmt.Errorf("unknown --theme %q (expected one of: %s)", theme, strings.Join(tui.ThemeNames(themes), ", "))
+			}
+			hpGlyphs, err := tui.ParseGlyphs(glyphs)
+			if err != nil {
+				return fmt.Errorf("invalid --glyphs: %w", err)
+			}
+			subCell, err := tui.ParseSubCell(subcell)
+			if err != nil {
+				return fmt.Errorf("invalid --subcell: %w", err)
+			}
+			audio, err := sound.ParseMode(soundMode)
+			if err != nil {
+				return fmt.Errorf("invalid --sound: %w", err)
+			}
+			if soundFile != "" && audio != sound.ModeSynth {
+				return fmt.Errorf("--sound-file requires --sound synth")
+			}
+			var players [2]string
+			if versus != "" {
+				if players, err = parseVersus(versus); err != nil {
+					return err
+				}
+				if flags.Changed("user") {
+					return fmt.Errorf("--versus cannot be combined with --user")
+				}
+				if mouse {
+					return fmt.Errorf("--versus cannot be combined with --mouse (both players use the keyboard)")
+				}
+				if broadcastAddr != "" {
+					return fmt.Errorf("--versus cannot be combined with --broadcast")
+				}
+			}
+			if timeLimit <= 0 {
+				return fmt.Errorf("--time-limit must be > 0")
+			}
 
 			var fromPtr *time.Time
 			var toPtr *time.Time
@@ -82,8 +264,67 @@ func NewRootCmd(deps Deps) *cobra.Command {
 				}
 			}
 
+			opts := tui.Options{
+				Speed:      speed,
+				Lives:      lives,
+				Keys:       keyMapFromConfig(cfg.Keys),
+				Difficulty: preset,
+				SpeedRamp:  ramp,
+
+				HPScale:  scale,
+				Compress: compression,
+				Layout:   boardLayout,
+
+				BrickWidth:   brickWidth,
+				BrickHeight:  brickHeight,
+				MonthGutters: gutters,
+
+				Themes: themes,
+				Theme:  theme,
+				Glyphs: hpGlyphs,
+				ASCII:  ascii || !tui.ColorSupported(),
+				Mouse:  mouse,
+
+				SubCell:   subCell,
+				NoEffects: noEffects,
+
+				TimeLimit: timeLimit,
+			}
+			player, err := sound.Open(audio, soundFile)
+			if err != nil {
+				return err
+			}
+			if player != nil {
+				opts.Sound = player
+				defer func() {
+					if err := player.Close(); err != nil {
+						fmt.Fprintf(deps.Stderr, "warning: sound: %v\n", err)
+					}
+				}()
+			}
+
+			if broadcastAddr != "" {
+				if deps.Listen == nil {
+					return fmt.Errorf("deps.Listen is nil")
+				}
+				ln, err := deps.Listen("tcp", broadcastAddr)
+				if err != nil {
+					return fmt.Errorf("--broadcast: %w", err)
+				}
+				srv := broadcast.NewServer(ln)
+				defer srv.Close()
+				opts.Broadcast = srv
+				fmt.Fprintf(deps.Stderr, "broadcasting on %s (watchers run: kusa-breaker watch HOST:PORT)\n", srv.Addr())
+			}
+
+			ctx := github.WithHost(cmd.Context(), host)
 			seed := uint64(deps.Now().UnixNano())
-			if err := run(cmd.Context(), deps, user, defaultWeeks, fromPtr, toPtr, seed, speed); err != nil {
+			if versus != "" {
+				err = runVersus(ctx, deps, players, defaultWeeks, fromPtr, toPtr, seed, opts)
+			} else {
+				err = run(ctx, deps, user, defaultWeeks, fromPtr, toPtr, seed, opts)
+			}
+			if err != nil {
 				var unf *github.UserNotFoundError
 				if errors.As(err, &unf) {
 					// Don't print auth hints for this case; make it explicit.
@@ -99,16 +340,62 @@ func NewRootCmd(deps Deps) *cobra.Command {
 	}
 
 	c.Flags().Float64VarP(&speed, "speed", "s", 1.0, "game speed multiplier (1.0 is normal)")
+	c.Flags().StringVar(&speedRamp, "speed-ramp", game.DefaultSpeedRamp.String(), "speed the ball up during rallies: off, or hits=N (every N paddle hits), breach (on reaching the top brick row), step=F, max=F")
 	c.Flags().StringVarP(&user, "user", "u", "", "GitHub username to use (default: authenticated user)")
 	c.Flags().StringVarP(&fromStr, "from", "f", "", "start date (YYYY-MM-DD). if set, enables date range mode")
 	c.Flags().StringVarP(&toStr, "to", "t", "", "end date (YYYY-MM-DD). if set, enables date range mode")
+	c.Flags().StringVar(&host, "host", "", "GitHub host, e.g. a GitHub Enterprise Server hostname (default: github.com)")
+	c.Flags().IntVar(&lives, "lives", 0, "number of balls per game (default: from --difficulty)")
+	c.Flags().StringVar(&difficulty, "difficulty", "normal", "preset for paddle width, ball speed and ramp, lives and HP scale: "+strings.Join(game.DifficultyNames(), ", "))
+	c.Flags().StringVar(&compress, "compress", "max", "how weeks are merged on narrow terminals: "+strings.Join(mapping.CompressionNames, ", ")+" (scroll pans a full-size board)")
+	c.Flags().StringVar(&layout, "layout", "weeks", "board layout: "+strings.Join(mapping.LayoutNames, ", "))
+	c.Flags().IntVar(&brickWidth, "brick-width", 0, "brick width in columns: 1-3, or 0 to pick automatically")
+	c.Flags().IntVar(&brickHeight, "brick-height", 1, "brick height in rows (1-3)")
+	c.Flags().BoolVar(&gutters, "gutters", false, "leave an empty column between months")
+	c.Flags().StringVar(&theme, "theme", "classic", "color theme: "+strings.Join(tui.ThemeNames(tui.BuiltinThemes()), ", ")+", or one from --theme-file (t cycles in game)")
+	c.Flags().StringVar(&themeFile, "theme-file", "", "YAML file with extra themes (default: themes.yaml next to the config file)")
+	c.Flags().StringVar(&glyphs, "glyphs", "off", "also draw brick HP as a character: off, shade (░▒▓█), or digits")
+	c.Flags().BoolVar(&ascii, "ascii", false, "plain ASCII rendering without colors (default when the terminal has no color support)")
+	c.Flags().BoolVar(&mouse, "mouse", false, "steer the paddle with the mouse (click to launch, wheel changes speed)")
+	c.Flags().StringVar(&subcell, "subcell", "off", "draw the ball and paddle at sub-cell resolution: "+strings.Join(tui.SubCellNames, ", "))
+	c.Flags().BoolVar(&noEffects, "no-effects", false, "turn off brick fragments, the ball trail and paddle flashes")
+	c.Flags().StringVar(&soundMode, "sound", "off", "audio feedback: off, bell (terminal bell), or synth (chiptune blips)")
+	c.Flags().StringVar(&soundFile, "sound-file", "", "write --sound synth audio to this WAV file instead of playing it")
+	c.Flags().StringVar(&versus, "versus", "", "two-player game at one keyboard, e.g. alice,bob (a/d steer the left board, arrow keys the right; paddles keep moving until s/down)")
+	c.Flags().StringVar(&broadcastAddr, "broadcast", "", "stream the game to read-only watchers on this address, e.g. :7778 (see kusa-breaker watch)")
+	c.Flags().DurationVar(&timeLimit, "time-limit", 3*time.Minute, "length of a --versus match; the higher score wins if nobody clears")
+	c.Flags().StringVar(&hpScale, "hp-scale", "", "how contribution counts map to brick HP: "+strings.Join(mapping.HPScaleNames, ", ")+" (default: from --difficulty)")
+
+	c.AddCommand(newConfigCmd(deps))
+	c.AddCommand(newHostCmd(deps))
+	c.AddCommand(newJoinCmd(deps))
+	c.AddCommand(newWatchCmd(deps))
 
 	c.SetOut(deps.Stdout)
 	c.SetErr(deps.Stderr)
 	return c
 }
 
-const dateLayout = "2006-01-02"
+const (
+	dateLayout   = "2006-01-02"
+	defaultWeeks = 52
+)
+
+// parseVersus splits the --versus value into the two players' logins.
+func parseVersus(s string) ([2]string, error) {
+	var players [2]string
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return players, fmt.Errorf("--versus expects two users, e.g. --versus alice,bob")
+	}
+	for i, p := range parts {
+		players[i] = strings.TrimSpace(p)
+		if players[i] == "" {
+			return players, fmt.Errorf("--versus expects two users, e.g. --versus alice,bob")
+		}
+	}
+	return players, nil
+}
 
 func parseDateStartUTC(s string) (time.Time, error) {
 	t, err := time.Parse(dateLayout, s)
@@ -3,11 +3,15 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
 )
 
 func TestRootCmd_DoesNotPrintAuthHintOnRangeValidationError(t *testing.T) {
@@ -32,7 +36,7 @@ func TestRootCmd_DoesNotPrintAuthHintOnRangeValidationError(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			t.Fatalf("RunTUI should not be called on fetch error")
 			return nil
 		},
@@ -73,7 +77,7 @@ func TestRootCmd_PrintsAuthHintOnAuthError(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			t.Fatalf("RunTUI should not be called on fetch error")
 			return nil
 		},
@@ -113,7 +117,7 @@ func TestRootCmd_DoesNotPrintAuthHintOnUserNotFound(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			t.Fatalf("RunTUI should not be called on fetch error")
 			return nil
 		},
@@ -137,6 +141,320 @@ func TestRootCmd_DoesNotPrintAuthHintOnUserNotFound(t *testing.T) {
 	}
 }
 
+func TestRootCmd_ConfigDefaultsAndFlagOverride(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "speed: 1.6\nuser: from-config\nlives: 3\nkeys:\n  left: [j]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var gotUser string
+	var gotOpts tui.Options
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendar should not be called when config sets a user")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			gotUser = user
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchUserCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+			gotOpts = opts
+			return nil
+		},
+		ConfigPath: func() (string, error) { return path, nil },
+		Now:        func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout:     &bytes.Buffer{},
+		Stderr:     &bytes.Buffer{},
+	}
+
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"--speed", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if gotUser != "from-config" {
+		t.Fatalf("expected user from config, got %q", gotUser)
+	}
+	if gotOpts.Speed != 2 {
+		t.Fatalf("expected --speed to override config, got %v", gotOpts.Speed)
+	}
+	if gotOpts.Lives != 3 {
+		t.Fatalf("expected lives from config, got %d", gotOpts.Lives)
+	}
+	if len(gotOpts.Keys.Left) != 1 || gotOpts.Keys.Left[0] != "j" {
+		t.Fatalf("expected key bindings from config, got %+v", gotOpts.Keys)
+	}
+}
+
+func TestRootCmd_UserThemes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	themes := "themes:\n  - name: sakura\n    bricks: [\"#ffd6e7\", \"#ff9ec4\", \"#f06595\", \"#a61e4d\"]\n"
+	if err := os.WriteFile(filepath.Join(dir, "themes.yaml"), []byte(themes), 0o644); err != nil {
+		t.Fatalf("write themes: %v", err)
+	}
+
+	var gotOpts tui.Options
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+			gotOpts = opts
+			return nil
+		},
+		ConfigPath: func() (string, error) { return filepath.Join(dir, "config.yaml"), nil },
+		Now:        func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout:     &bytes.Buffer{},
+		Stderr:     &bytes.Buffer{},
+	}
+
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"--theme", "sakura", "--glyphs", "digits"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotOpts.Theme != "sakura" {
+		t.Fatalf("expected theme sakura, got %q", gotOpts.Theme)
+	}
+	if gotOpts.Glyphs != tui.GlyphsDigits {
+		t.Fatalf("expected digit glyphs, got %v", gotOpts.Glyphs)
+	}
+	if _, ok := tui.FindTheme(gotOpts.Themes, "halloween"); !ok {
+		t.Fatalf("expected built-in themes to stay available, got %v", tui.ThemeNames(gotOpts.Themes))
+	}
+	if _, ok := tui.FindTheme(gotOpts.Themes, "sakura"); !ok {
+		t.Fatalf("expected user theme to be loaded, got %v", tui.ThemeNames(gotOpts.Themes))
+	}
+
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--theme", "nope"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown --theme") {
+		t.Fatalf("expected unknown theme error, got %v", err)
+	}
+
+	// An empty default themes.yaml is fine...
+	if err := os.WriteFile(filepath.Join(dir, "themes.yaml"), []byte("# nothing here\n"), 0o644); err != nil {
+		t.Fatalf("write themes: %v", err)
+	}
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected an empty themes file to be ignored, got %v", err)
+	}
+
+	// ...but a --theme-file that does not exist is an error.
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--theme-file", filepath.Join(dir, "missing.yaml")})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Fatalf("expected a missing theme file error, got %v", err)
+	}
+}
+
+func TestRootCmd_SoundFile(t *testing.T) {
+	t.Parallel()
+
+	wav := filepath.Join(t.TempDir(), "game.wav")
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+			if opts.Sound == nil {
+				t.Fatalf("expected a sound player")
+			}
+			opts.Sound.Advance(0.1)
+			return nil
+		},
+		Now:    func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"--sound", "synth", "--sound-file", wav})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	data, err := os.ReadFile(wav)
+	if err != nil {
+		t.Fatalf("expected the WAV file to be written: %v", err)
+	}
+	if len(data) <= 44 || string(data[:4]) != "RIFF" {
+		t.Fatalf("expected a WAV file with samples, got %d bytes", len(data))
+	}
+
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--sound", "bell", "--sound-file", wav})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--sound-file requires --sound synth") {
+		t.Fatalf("expected --sound-file error, got %v", err)
+	}
+}
+
+func TestRootCmd_DifficultyAndSpeedRamp(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("difficulty: hard\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	var gotOpts tui.Options
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+			gotOpts = opts
+			return nil
+		},
+		ConfigPath: func() (string, error) { return filepath.Join(dir, "config.yaml"), nil },
+		Now:        func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout:     &bytes.Buffer{},
+		Stderr:     &bytes.Buffer{},
+	}
+
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotOpts.Difficulty.Name != "hard" || gotOpts.Lives != 1 || gotOpts.HPScale.Name() != "quartile" {
+		t.Fatalf("expected the hard preset from config, got %q, lives %d, %s", gotOpts.Difficulty.Name, gotOpts.Lives, gotOpts.HPScale.Name())
+	}
+	if gotOpts.SpeedRamp != game.DefaultSpeedRamp {
+		t.Fatalf("expected the default speed ramp, got %+v", gotOpts.SpeedRamp)
+	}
+
+	// Explicit lives and HP scale win over the preset.
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--difficulty", "easy", "--hp-scale", "log"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotOpts.Difficulty.Name != "easy" || gotOpts.Lives != 3 || gotOpts.HPScale.Name() != "log" {
+		t.Fatalf("expected easy with a log scale, got %q, lives %d, %s", gotOpts.Difficulty.Name, gotOpts.Lives, gotOpts.HPScale.Name())
+	}
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--difficulty", "easy", "--lives", "2"})
+	if err := cmd.Execute(); err != nil || gotOpts.Lives != 2 {
+		t.Fatalf("expected --lives to win, got %d, %v", gotOpts.Lives, err)
+	}
+
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--speed-ramp", "off"})
+	if err := cmd.Execute(); err != nil || gotOpts.SpeedRamp != (game.SpeedRamp{}) {
+		t.Fatalf("expected no speed ramp, got %+v, %v", gotOpts.SpeedRamp, err)
+	}
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--speed-ramp", "hits=0.5"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --speed-ramp") {
+		t.Fatalf("expected speed ramp error, got %v", err)
+	}
+
+	cmd = NewRootCmd(deps)
+	cmd.SetArgs([]string{"--difficulty", "nightmare"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --difficulty") {
+		t.Fatalf("expected difficulty error, got %v", err)
+	}
+}
+
+func TestRootCmd_Versus(t *testing.T) {
+	t.Parallel()
+
+	var gotPlayers [2]tui.Player
+	var gotOpts tui.Options
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendar should not be called in versus mode")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			return "me", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			return user, github.Calendar{}, nil
+		},
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+			t.Fatalf("RunTUI should not be called in versus mode")
+			return nil
+		},
+		RunVersusTUI: func(players [2]tui.Player, seed uint64, opts tui.Options) error {
+			gotPlayers, gotOpts = players, opts
+			return nil
+		},
+		Now:    func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+
+	cmd := NewRootCmd(deps)
+	cmd.SetArgs([]string{"--versus", "alice, bob", "--time-limit", "90s"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotPlayers[0].Login != "alice" || gotPlayers[1].Login != "bob" || gotOpts.TimeLimit != 90*time.Second {
+		t.Fatalf("unexpected versus game: %+v, time limit %v", gotPlayers, gotOpts.TimeLimit)
+	}
+
+	for _, args := range [][]string{
+		{"--versus", "alice"},
+		{"--versus", "alice,,bob"},
+		{"--versus", "alice,bob", "--user", "carol"},
+		{"--versus", "alice,bob", "--mouse"},
+		{"--versus", "alice,bob", "--time-limit", "0s"},
+	} {
+		cmd := NewRootCmd(deps)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+}
+
 func errFromAfterTo() error {
 	// Match the real internal/github message (see validateRange).
 	return &rangeValidationError{msg: "from must be <= to"}
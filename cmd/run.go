@@ -2,13 +2,31 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
 )
 
-func run(ctx context.Context, deps Deps, user string, weeks int, from, to *time.Time, seed uint64, speed float64) error {
+func run(ctx context.Context, deps Deps, user string, weeks int, from, to *time.Time, seed uint64, opts tui.Options) error {
+	if err := checkFetchDeps(deps); err != nil {
+		return err
+	}
+	if deps.RunTUI == nil {
+		return fmt.Errorf("deps.RunTUI is nil")
+	}
+
+	login, cal, err := fetch(ctx, deps, user, weeks, from, to)
+	if err != nil {
+		return err
+	}
+	return deps.RunTUI(login, cal, seed, opts)
+}
+
+func checkFetchDeps(deps Deps) error {
 	if deps.FetchCalendar == nil {
 		return fmt.Errorf("deps.FetchCalendar is nil")
 	}
@@ -21,10 +39,39 @@ func run(ctx context.Context, deps Deps, user string, weeks int, from, to *time.
 	if deps.FetchUserCalendarRange == nil {
 		return fmt.Errorf("deps.FetchUserCalendarRange is nil")
 	}
-	if deps.RunTUI == nil {
-		return fmt.Errorf("deps.RunTUI is nil")
+	return nil
+}
+
+// runVersus fetches both players' calendars concurrently and starts a versus
+// game.
+func runVersus(ctx context.Context, deps Deps, users [2]string, weeks int, from, to *time.Time, seed uint64, opts tui.Options) error {
+	if err := checkFetchDeps(deps); err != nil {
+		return err
+	}
+	if deps.RunVersusTUI == nil {
+		return fmt.Errorf("deps.RunVersusTUI is nil")
 	}
 
+	var players [2]tui.Player
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			players[i].Login, players[i].Calendar, errs[i] = fetch(ctx, deps, user, weeks, from, to)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs[0], errs[1]); err != nil {
+		return err
+	}
+	return deps.RunVersusTUI(players, seed, opts)
+}
+
+// fetch fetches the calendar of user (the authenticated user when empty), for
+// the date range when from and to are set.
+func fetch(ctx context.Context, deps Deps, user string, weeks int, from, to *time.Time) (string, github.Calendar, error) {
 	var (
 		login string
 		cal   github.Calendar
@@ -45,7 +92,7 @@ func run(ctx context.Context, deps Deps, user string, weeks int, from, to *time.
 		}
 	}
 	if err != nil {
-		return fmt.Errorf("failed to fetch GitHub contributions: %w", err)
+		return "", github.Calendar{}, fmt.Errorf("failed to fetch GitHub contributions: %w", err)
 	}
-	return deps.RunTUI(login, cal, seed, speed)
+	return login, cal, nil
 }
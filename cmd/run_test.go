@@ -3,10 +3,12 @@ package cmd
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
 )
 
 func TestRun_Success(t *testing.T) {
@@ -35,7 +37,7 @@ func TestRun_Success(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			calledTUI = true
 			if login != "octocat" {
 				t.Fatalf("login mismatch: got %q", login)
@@ -43,14 +45,14 @@ func TestRun_Success(t *testing.T) {
 			if seed != 123 {
 				t.Fatalf("seed mismatch: got %d", seed)
 			}
-			if speed != 1.0 {
-				t.Fatalf("speed mismatch: got %v", speed)
+			if opts.Speed != 1.0 {
+				t.Fatalf("speed mismatch: got %v", opts.Speed)
 			}
 			return nil
 		},
 	}
 
-	if err := run(context.Background(), deps, "", 52, nil, nil, 123, 1.0); err != nil {
+	if err := run(context.Background(), deps, "", 52, nil, nil, 123, tui.Options{Speed: 1.0}); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
 	if !calledFetch {
@@ -90,7 +92,7 @@ func TestRun_Success_User(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			calledTUI = true
 			if login != "someone" {
 				t.Fatalf("login mismatch: got %q", login)
@@ -99,7 +101,7 @@ func TestRun_Success_User(t *testing.T) {
 		},
 	}
 
-	if err := run(context.Background(), deps, "someone", 10, nil, nil, 1, 1.0); err != nil {
+	if err := run(context.Background(), deps, "someone", 10, nil, nil, 1, tui.Options{Speed: 1.0}); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
 	if !calledFetchUser {
@@ -130,13 +132,13 @@ func TestRun_FetchError(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			t.Fatalf("RunTUI should not be called on fetch error")
 			return nil
 		},
 	}
 
-	err := run(context.Background(), deps, "", 52, nil, nil, 1, 1.0)
+	err := run(context.Background(), deps, "", 52, nil, nil, 1, tui.Options{Speed: 1.0})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -148,7 +150,7 @@ func TestRun_FetchError(t *testing.T) {
 func TestRun_MissingDeps(t *testing.T) {
 	t.Parallel()
 
-	if err := run(context.Background(), Deps{}, "", 52, nil, nil, 1, 1.0); err == nil {
+	if err := run(context.Background(), Deps{}, "", 52, nil, nil, 1, tui.Options{Speed: 1.0}); err == nil {
 		t.Fatalf("expected error for missing deps")
 	}
 }
@@ -182,13 +184,13 @@ func TestRun_Success_Range(t *testing.T) {
 			t.Fatalf("FetchUserCalendarRange should not be called in this test")
 			return "", github.Calendar{}, nil
 		},
-		RunTUI: func(login string, cal github.Calendar, seed uint64, speed float64) error {
+		RunTUI: func(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
 			calledTUI = true
 			return nil
 		},
 	}
 
-	if err := run(context.Background(), deps, "", 52, &from, &to, 1, 1.0); err != nil {
+	if err := run(context.Background(), deps, "", 52, &from, &to, 1, tui.Options{Speed: 1.0}); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
 	if !calledRange {
@@ -198,3 +200,83 @@ func TestRun_Success_Range(t *testing.T) {
 		t.Fatalf("RunTUI not called")
 	}
 }
+
+func TestRunVersus_FetchesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	// Each fetch waits for the other to start, so sequential fetches would hang.
+	var started sync.WaitGroup
+	started.Add(2)
+	var gotPlayers [2]tui.Player
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendar should not be called in versus mode")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			started.Done()
+			started.Wait()
+			return user, github.Calendar{Weeks: make([]github.Week, len(user))}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			t.Fatalf("FetchUserCalendarRange should not be called in this test")
+			return "", github.Calendar{}, nil
+		},
+		RunVersusTUI: func(players [2]tui.Player, seed uint64, opts tui.Options) error {
+			gotPlayers = players
+			return nil
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runVersus(context.Background(), deps, [2]string{"alice", "bobby"}, 52, nil, nil, 1, tui.Options{})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("calendars were not fetched concurrently")
+	}
+	if gotPlayers[0].Login != "alice" || gotPlayers[1].Login != "bobby" || len(gotPlayers[1].Calendar.Weeks) != 5 {
+		t.Fatalf("unexpected players %+v", gotPlayers)
+	}
+}
+
+func TestRunVersus_FetchError(t *testing.T) {
+	t.Parallel()
+
+	deps := Deps{
+		FetchCalendar: func(ctx context.Context, weeks int) (string, github.Calendar, error) {
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendar: func(ctx context.Context, user string, weeks int) (string, github.Calendar, error) {
+			if user == "ghost" {
+				return "", github.Calendar{}, &github.UserNotFoundError{Login: user}
+			}
+			return user, github.Calendar{}, nil
+		},
+		FetchCalendarRange: func(ctx context.Context, from, to time.Time) (string, github.Calendar, error) {
+			return "", github.Calendar{}, nil
+		},
+		FetchUserCalendarRange: func(ctx context.Context, user string, from, to time.Time) (string, github.Calendar, error) {
+			return "", github.Calendar{}, nil
+		},
+		RunVersusTUI: func(players [2]tui.Player, seed uint64, opts tui.Options) error {
+			t.Fatalf("RunVersusTUI should not be called after a fetch error")
+			return nil
+		},
+	}
+
+	err := runVersus(context.Background(), deps, [2]string{"alice", "ghost"}, 52, nil, nil, 1, tui.Options{})
+	var unf *github.UserNotFoundError
+	if !errors.As(err, &unf) || unf.Login != "ghost" {
+		t.Fatalf("expected user-not-found for ghost, got %v", err)
+	}
+}
@@ -1,17 +1,42 @@
 package cmd
 
 import (
+	"os"
+
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
 	"github.com/fchimpan/gh-kusa-breaker/internal/tui"
 )
 
-func defaultRunTUI(login string, cal github.Calendar, seed uint64, speed float64) error {
-	p := tea.NewProgram(
-		tui.NewModel(login, cal, seed, speed),
-		tea.WithAltScreen(),
-	)
+func defaultRunTUI(login string, cal github.Calendar, seed uint64, opts tui.Options) error {
+	progOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if opts.Mouse {
+		progOpts = append(progOpts, tea.WithMouseAllMotion())
+	}
+	return runProgram(tui.NewModel(login, cal, seed, opts), progOpts...)
+}
+
+func defaultRunVersusTUI(players [2]tui.Player, seed uint64, opts tui.Options) error {
+	return runProgram(tui.NewVersusModel(players, seed, opts), tea.WithAltScreen())
+}
+
+func defaultRunNetTUI(match *netplay.Match, opts tui.Options) error {
+	return runProgram(tui.NewNetModel(match, opts), tea.WithAltScreen())
+}
+
+func defaultRunWatchTUI(stream *broadcast.Stream, opts tui.Options) error {
+	return runProgram(tui.NewWatchModel(stream, opts), tea.WithAltScreen())
+}
+
+// runProgram runs model on the terminal, reading key releases where the
+// terminal reports them (see tui.KittyKeyboard).
+func runProgram(model tea.Model, opts ...tea.ProgramOption) error {
+	kb := tui.NewKittyKeyboard(os.Stdout)
+	p := tea.NewProgram(model, append(opts, tea.WithFilter(kb.Filter))...)
 	_, err := p.Run()
+	kb.Stop()
 	return err
 }
@@ -0,0 +1,241 @@
+// Package broadcast streams a game to read-only watchers over TCP.
+//
+// The player's Server sends a keyframe (the whole board) to each watcher when
+// it connects, then small diff frames: the bricks whose HP changed, and the
+// ball, paddle and HUD values. Each watcher has its own bounded queue. A
+// watcher that falls behind skips frames and resumes with a fresh keyframe,
+// so it never stalls the player or the other watchers.
+package broadcast
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+const (
+	// queueLen is how many frames may wait for a watcher (about a quarter
+	// second of play) before it skips to a keyframe.
+	queueLen = 16
+	// writeTimeout drops a watcher whose connection stops taking data.
+	writeTimeout = 5 * time.Second
+)
+
+// Server accepts watchers and streams Publish'ed states to them.
+type Server struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	enc      encoder
+	watchers map[*watcher]struct{}
+	closed   bool
+
+	wg sync.WaitGroup
+}
+
+// watcher is one connected watcher.
+type watcher struct {
+	conn  net.Conn
+	queue chan []byte
+	// needKey is set until the watcher has a keyframe to apply diffs to.
+	needKey bool
+}
+
+// NewServer streams to the watchers that connect to ln, until Close.
+func NewServer(ln net.Listener) *Server {
+	s := &Server{ln: ln, watchers: make(map[*watcher]struct{})}
+	s.wg.Add(1)
+	go s.accept()
+	return s
+}
+
+// Addr is the address watchers connect to.
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.add(conn)
+	}
+}
+
+// add starts streaming to conn.
+func (s *Server) add(conn net.Conn) {
+	w := &watcher{conn: conn, queue: make(chan []byte, queueLen), needKey: true}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		conn.Close()
+		return
+	}
+	s.watchers[w] = struct{}{}
+	s.wg.Add(1)
+	go s.write(w)
+}
+
+// write sends w's queue until it is closed or the connection fails.
+func (s *Server) write(w *watcher) {
+	defer s.wg.Done()
+	defer w.conn.Close()
+	bw := bufio.NewWriter(w.conn)
+	_ = w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := bw.WriteString(magic)
+	if err == nil {
+		err = bw.WriteByte(version)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		s.remove(w)
+	}
+	for frame := range w.queue {
+		if err != nil {
+			continue // drain until remove closes the queue
+		}
+		_ = w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err = writeFrame(bw, frame); err == nil && len(w.queue) == 0 {
+			err = bw.Flush()
+		}
+		if err != nil {
+			s.remove(w)
+		}
+	}
+}
+
+// remove stops streaming to w.
+func (s *Server) remove(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.watchers[w]; ok {
+		delete(s.watchers, w)
+		close(w.queue)
+	}
+}
+
+// Watchers returns the number of connected watchers.
+func (s *Server) Watchers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.watchers)
+}
+
+// Publish sends the current state of login's game to every watcher. speed is
+// the player's game speed multiplier and lives the balls the game started
+// with, for the HUD. It never blocks on a watcher, and costs next to nothing
+// while nobody watches.
+func (s *Server) Publish(login string, st *game.State, speed float64, lives int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if len(s.watchers) == 0 {
+		// Skip the encoding; whoever connects next starts from a keyframe.
+		s.enc.invalidate()
+		return
+	}
+	wantKey := false
+	for w := range s.watchers {
+		wantKey = wantKey || w.needKey
+	}
+	diff, key, relayout := s.enc.frames(login, st, speed, lives, wantKey)
+	for w := range s.watchers {
+		switch {
+		case w.needKey || relayout:
+			w.needKey = !send(w, key)
+		case diff != nil:
+			// Once a diff is lost, later ones no longer apply.
+			w.needKey = !send(w, diff)
+		}
+	}
+}
+
+// send queues frame for w unless its queue is full.
+func send(w *watcher, frame []byte) bool {
+	select {
+	case w.queue <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting watchers and disconnects them.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for w := range s.watchers {
+		delete(s.watchers, w)
+		close(w.queue)
+		w.conn.Close()
+	}
+	s.mu.Unlock()
+	err := s.ln.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Stream is a watcher's connection to a Server.
+type Stream struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Dial connects to the Server at addr.
+func Dial(ctx context.Context, addr string) (*Stream, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	st, err := newStream(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return st, nil
+}
+
+func newStream(conn net.Conn) (*Stream, error) {
+	r := bufio.NewReader(conn)
+	var hdr [len(magic) + 1]byte
+	_ = conn.SetReadDeadline(time.Now().Add(writeTimeout))
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("reading stream header: %w", err)
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	if string(hdr[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a kusa-breaker broadcast")
+	}
+	if v := hdr[len(magic)]; v != version {
+		return nil, fmt.Errorf("broadcast has protocol version %d, want %d", v, version)
+	}
+	return &Stream{conn: conn, r: r}, nil
+}
+
+// Next waits for the next frame. It returns io.EOF once the player has quit.
+func (s *Stream) Next() (*Frame, error) {
+	b, err := readFrame(s.r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFrame(b)
+}
+
+// Close disconnects from the server.
+func (s *Stream) Close() error {
+	return s.conn.Close()
+}
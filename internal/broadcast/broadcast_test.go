@@ -0,0 +1,171 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// follow applies st's frames to a state and sends a copy of its score and
+// brick HPs whenever wantScore is reached.
+func follow(st *Stream, wantScore int) <-chan *game.State {
+	done := make(chan *game.State, 1)
+	go func() {
+		var s game.State
+		for {
+			f, err := st.Next()
+			if err != nil {
+				close(done)
+				return
+			}
+			if err := f.Apply(&s); err != nil {
+				close(done)
+				return
+			}
+			if s.Score == wantScore {
+				done <- &s
+				return
+			}
+		}
+	}()
+	return done
+}
+
+func TestServer_FastAndSlowWatchers(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ln)
+	defer srv.Close()
+
+	fast, err := Dial(context.Background(), srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fast.Close()
+	// A slow watcher: net.Pipe takes no data until its other end reads.
+	slowConn, slowServer := net.Pipe()
+	defer slowConn.Close()
+	srv.add(slowServer)
+	for srv.Watchers() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	s := testState()
+	const final = 987654
+	fastDone := follow(fast, final)
+
+	// Publishing never waits for the slow watcher.
+	start := time.Now()
+	for range 2000 {
+		s.Step(1.0/120, game.Input{Target: true, TargetX: s.BallX})
+		srv.Publish("alice", &s, 1, 1)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("publishing took %v; a slow watcher should not stall the player", d)
+	}
+	s.Score = final
+	publishUntil(t, srv, &s, fastDone)
+
+	// The slow watcher skipped most frames, then catches up from a keyframe.
+	slow, err := newStream(slowConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Score = final + 1
+	publishUntil(t, srv, &s, follow(slow, final+1))
+}
+
+// publishUntil publishes s until a watcher following it is done, and checks
+// that it ended up showing s.
+func publishUntil(t *testing.T, srv *Server, s *game.State, done <-chan *game.State) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		srv.Publish("alice", s, 1, 1)
+		select {
+		case got := <-done:
+			if got == nil {
+				t.Fatalf("the stream ended early")
+			}
+			if d := sameView(s, got); d != "" {
+				t.Fatalf("watcher: %s differs", d)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the watcher never caught up")
+		}
+	}
+}
+
+func TestServer_CloseEndsStreams(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ln)
+
+	// Without watchers Publish encodes nothing.
+	s := testState()
+	srv.Publish("alice", &s, 1, 1)
+	if srv.enc.sig != (layoutSig{}) || len(srv.enc.buf) != 0 {
+		t.Fatalf("publishing to nobody should not encode a frame")
+	}
+
+	st, err := Dial(context.Background(), srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	for srv.Watchers() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	s.Step(1.0/120, game.Input{})
+	srv.Publish("alice", &s, 1, 1)
+	f, err := st.Next()
+	if err != nil || !f.Key {
+		t.Fatalf("expected a keyframe first, got %+v, %v", f, err)
+	}
+	var got game.State
+	if err := f.Apply(&got); err != nil {
+		t.Fatal(err)
+	}
+	if d := sameView(&s, &got); d != "" {
+		t.Fatalf("keyframe: %s differs", d)
+	}
+	if err := srv.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF after Close, got %v", err)
+	}
+
+	// Not a broadcast.
+	plain, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	go func() {
+		conn, err := plain.Accept()
+		if err == nil {
+			conn.Write([]byte("HTTP/1.1 400\r\n"))
+			conn.Close()
+		}
+	}()
+	if _, err := Dial(context.Background(), plain.Addr().String()); err == nil {
+		t.Fatalf("expected an error for a non-broadcast server")
+	}
+}
@@ -0,0 +1,478 @@
+package broadcast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// Wire format. A stream starts with magic and a version byte, then carries
+// frames, each a uvarint length followed by that many bytes:
+//
+//	frame    = kind (frameKey | frameDiff), [layout] or [hp changes], moving
+//	layout   = login, difficulty, starting lives, field and brick geometry,
+//	           ColX, every brick
+//	hp       = count, then (brick index, HP) per brick changed since the
+//	           previous frame
+//	moving   = flags, view, paddle, ball, speed, pace, score, lives, blocks,
+//	           drops, projectiles, [boss]
+//
+// Integers are varints and positions float32, so a frame where only the ball
+// and paddle moved takes about 50 bytes.
+const (
+	magic   = "KUSA-BC"
+	version = 2
+
+	frameKey  = 'K'
+	frameDiff = 'D'
+
+	// maxFrameSize bounds what a reader accepts; keyframes of a year-long
+	// calendar take a few KB.
+	maxFrameSize = 1 << 20
+)
+
+const (
+	flagServing = 1 << iota
+	flagCleared
+	flagGameOver
+	flagBoss
+)
+
+// encoder turns game states into frames. Diff frames are relative to the state
+// of the previous call.
+type encoder struct {
+	hp   []int
+	sig  layoutSig
+	last []byte // the last diff frame, to skip repeats
+	buf  []byte
+}
+
+// layoutSig changes whenever a diff could not describe the new state: a new
+// field size, board or stage.
+type layoutSig struct {
+	width, height, viewW, bricks, rows, cols int
+	boss                                     bool
+	login                                    string
+	lives                                    int
+}
+
+func signature(login string, lives int, s *game.State) layoutSig {
+	return layoutSig{s.Width, s.Height, s.ViewW, len(s.Bricks), s.BrickRows, s.BrickCols, s.Boss != nil, login, lives}
+}
+
+// frames returns the diff frame of s since the previous call (nil when nothing
+// changed), and its keyframe when wantKey is set; lives is the balls the game
+// started with. When relayout is set the layout changed and only the keyframe,
+// always returned then, describes s. The frames are freshly allocated.
+func (e *encoder) frames(login string, s *game.State, speed float64, lives int, wantKey bool) (diff, key []byte, relayout bool) {
+	if sig := signature(login, lives, s); sig != e.sig {
+		e.sig = sig
+		e.hp = e.hp[:0]
+		for _, br := range s.Bricks {
+			e.hp = append(e.hp, br.HP)
+		}
+		e.last = e.last[:0]
+		relayout = true
+	}
+
+	if !relayout {
+		b := append(e.buf[:0], frameDiff)
+		n := 0
+		for i, br := range s.Bricks {
+			if br.HP != e.hp[i] {
+				n++
+			}
+		}
+		b = binary.AppendUvarint(b, uint64(n))
+		for i, br := range s.Bricks {
+			if br.HP != e.hp[i] {
+				b = binary.AppendUvarint(b, uint64(i))
+				b = binary.AppendUvarint(b, uint64(max(br.HP, 0)))
+				e.hp[i] = br.HP
+			}
+		}
+		b = appendMoving(b, s, speed)
+		e.buf = b
+		if !bytes.Equal(b, e.last) {
+			e.last = append(e.last[:0], b...)
+			diff = bytes.Clone(b)
+		}
+	}
+	if wantKey || relayout {
+		b := append(e.buf[:0], frameKey)
+		b = appendLayout(b, login, lives, s)
+		b = appendMoving(b, s, speed)
+		e.buf = b
+		key = bytes.Clone(b)
+	}
+	return diff, key, relayout
+}
+
+// invalidate forgets the previous state, so that the next call to frames
+// starts over with a keyframe.
+func (e *encoder) invalidate() {
+	e.sig = layoutSig{}
+}
+
+func appendLayout(b []byte, login string, lives int, s *game.State) []byte {
+	b = appendString(b, login)
+	b = appendString(b, s.Difficulty.Name)
+	b = binary.AppendVarint(b, int64(lives))
+	for _, v := range []int{s.Width, s.Height, s.ViewW, s.TopOffset, s.TopWallY, s.BrickW, s.BrickH, s.BrickRows, s.BrickCols} {
+		b = binary.AppendVarint(b, int64(v))
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.ColX)))
+	for _, x := range s.ColX {
+		b = binary.AppendVarint(b, int64(x))
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.Bricks)))
+	for _, br := range s.Bricks {
+		for _, v := range []int{br.X, br.Y, br.W, br.H, br.Row, br.Col, br.HP, br.MaxHP, br.Count} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+		b = appendBool(b, br.Streak)
+	}
+	return b
+}
+
+func appendMoving(b []byte, s *game.State, speed float64) []byte {
+	var flags byte
+	if s.Serving {
+		flags |= flagServing
+	}
+	if s.Cleared {
+		flags |= flagCleared
+	}
+	if s.GameOver {
+		flags |= flagGameOver
+	}
+	if s.Boss != nil {
+		flags |= flagBoss
+	}
+	b = append(b, flags)
+	for _, f := range []float64{s.ViewX, s.PaddleX, s.PaddleW, s.PaddleY, s.BallX, s.BallY, speed, s.Pace()} {
+		b = appendFloat(b, f)
+	}
+	for _, v := range []int{s.Score, s.Lives, s.BricksRemaining, s.BricksTotal} {
+		b = binary.AppendVarint(b, int64(v))
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.Drops)))
+	for _, d := range s.Drops {
+		b = appendFloat(b, d.X)
+		b = appendFloat(b, d.Y)
+		b = append(b, byte(d.Power))
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.Projectiles)))
+	for _, p := range s.Projectiles {
+		b = appendFloat(b, p.X)
+		b = appendFloat(b, p.Y)
+	}
+	if boss := s.Boss; boss != nil {
+		b = appendFloat(b, boss.X)
+		b = appendFloat(b, boss.Y)
+		for _, v := range []int{boss.W, boss.H, boss.HP, boss.MaxHP} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+	}
+	return b
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f)))
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+// writeFrame writes one length-prefixed frame.
+func writeFrame(w io.Writer, frame []byte) error {
+	var n [binary.MaxVarintLen64]byte
+	if _, err := w.Write(n[:binary.PutUvarint(n[:], uint64(len(frame)))]); err != nil {
+		return err
+	}
+	_, err := w.Write(frame)
+	return err
+}
+
+// readFrame reads one length-prefixed frame.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 || n > maxFrameSize {
+		return nil, fmt.Errorf("bad frame size %d", n)
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// Frame is a decoded frame; Apply brings a state up to date with it.
+type Frame struct {
+	// Key is set on keyframes, which replace the whole state.
+	Key bool
+	// Login is the player and Lives the balls their game started with, on
+	// keyframes.
+	Login string
+	Lives int
+	// Speed is the player's game speed multiplier and Pace the ball's speed-up
+	// (see game.State.Pace).
+	Speed, Pace float64
+
+	layout layout
+	hp     []hpChange
+	moving game.State
+}
+
+type layout struct {
+	difficulty                                                            string
+	width, height, viewW, topOffset, topWallY, brickW, brickH, rows, cols int
+	colX                                                                  []int
+	bricks                                                                []game.Brick
+}
+
+// valid reports whether the view, the grid columns and every brick lie inside
+// the field, as the renderers assume.
+func (l *layout) valid() bool {
+	if l.width <= 0 || l.height <= 0 || l.viewW <= 0 || l.viewW > l.width ||
+		l.rows < 0 || l.cols < 0 || len(l.colX) != l.cols {
+		return false
+	}
+	for _, x := range l.colX {
+		if x < 0 || x >= l.width {
+			return false
+		}
+	}
+	for _, br := range l.bricks {
+		if br.W <= 0 || br.H <= 0 || br.X < 0 || br.Y < 0 || br.X+br.W > l.width || br.Y+br.H > l.height ||
+			br.Row < 0 || br.Row >= l.rows || br.Col < 0 || br.Col >= l.cols {
+			return false
+		}
+	}
+	return true
+}
+
+// bossFits reports whether b lies inside a width x height field, as the
+// renderers assume.
+func bossFits(b *game.Boss, width, height int) bool {
+	return b.W > 0 && b.H > 0 &&
+		b.X >= 0 && b.X+float64(b.W) <= float64(width) &&
+		b.Y >= 0 && b.Y+float64(b.H) <= float64(height)
+}
+
+type hpChange struct{ brick, hp int }
+
+// errFrame is returned for frames that do not decode.
+var errFrame = errors.New("malformed frame")
+
+// decodeFrame parses a frame from the wire.
+func decodeFrame(b []byte) (*Frame, error) {
+	d := decoder{b: b}
+	f := &Frame{}
+	switch d.byte() {
+	case frameKey:
+		f.Key = true
+		f.Login = d.string()
+		l := &f.layout
+		l.difficulty = d.string()
+		f.Lives = d.int()
+		for _, v := range []*int{&l.width, &l.height, &l.viewW, &l.topOffset, &l.topWallY, &l.brickW, &l.brickH, &l.rows, &l.cols} {
+			*v = d.int()
+		}
+		l.colX = make([]int, d.count())
+		for i := range l.colX {
+			l.colX[i] = d.int()
+		}
+		l.bricks = make([]game.Brick, d.count())
+		for i := range l.bricks {
+			br := &l.bricks[i]
+			for _, v := range []*int{&br.X, &br.Y, &br.W, &br.H, &br.Row, &br.Col, &br.HP, &br.MaxHP, &br.Count} {
+				*v = d.int()
+			}
+			br.Streak = d.byte() != 0
+		}
+		if l.width <= 0 || l.height <= 0 || l.width*l.height > maxFrameSize {
+			return nil, errFrame
+		}
+	case frameDiff:
+		f.hp = make([]hpChange, d.count())
+		for i := range f.hp {
+			f.hp[i] = hpChange{brick: d.uint(), hp: d.uint()}
+		}
+	default:
+		return nil, errFrame
+	}
+
+	s := &f.moving
+	flags := d.byte()
+	s.Serving = flags&flagServing != 0
+	s.Cleared = flags&flagCleared != 0
+	s.GameOver = flags&flagGameOver != 0
+	for _, v := range []*float64{&s.ViewX, &s.PaddleX, &s.PaddleW, &s.PaddleY, &s.BallX, &s.BallY, &f.Speed, &f.Pace} {
+		*v = d.float()
+	}
+	for _, v := range []*int{&s.Score, &s.Lives, &s.BricksRemaining, &s.BricksTotal} {
+		*v = d.int()
+	}
+	if n := d.count(); n > 0 {
+		s.Drops = make([]game.Drop, n)
+		for i := range s.Drops {
+			s.Drops[i] = game.Drop{X: d.float(), Y: d.float(), Power: game.PowerUp(d.byte())}
+		}
+	}
+	if n := d.count(); n > 0 {
+		s.Projectiles = make([]game.Projectile, n)
+		for i := range s.Projectiles {
+			s.Projectiles[i] = game.Projectile{X: d.float(), Y: d.float()}
+		}
+	}
+	if flags&flagBoss != 0 {
+		s.Boss = &game.Boss{X: d.float(), Y: d.float(), W: d.int(), H: d.int(), HP: d.int(), MaxHP: d.int()}
+	}
+	if d.err != nil || len(d.b) != 0 {
+		return nil, errFrame
+	}
+	return f, nil
+}
+
+// Apply brings s up to date with f. A diff frame needs s to hold the state of
+// the previous frame. Frames that do not fit the field return an error and
+// leave s as it was.
+func (f *Frame) Apply(s *game.State) error {
+	width, height, viewW := s.Width, s.Height, s.ViewW
+	if f.Key {
+		if !f.layout.valid() {
+			return errFrame
+		}
+		width, height, viewW = f.layout.width, f.layout.height, f.layout.viewW
+	} else if s.Width == 0 {
+		return fmt.Errorf("diff frame before the first keyframe")
+	}
+	if x := f.moving.ViewX; !(x >= 0 && x <= float64(width-viewW)) {
+		return errFrame
+	}
+	if b := f.moving.Boss; b != nil && !bossFits(b, width, height) {
+		return errFrame
+	}
+
+	if f.Key {
+		l := &f.layout
+		*s = game.State{
+			Width: l.width, Height: l.height, ViewW: l.viewW,
+			TopOffset: l.topOffset, TopWallY: l.topWallY,
+			BrickW: l.brickW, BrickH: l.brickH, BrickRows: l.rows, BrickCols: l.cols,
+			ColX:       l.colX,
+			Bricks:     l.bricks,
+			Difficulty: game.Difficulty{Name: l.difficulty},
+		}
+		s.IndexBricks()
+	} else {
+		for _, c := range f.hp {
+			if c.brick >= len(s.Bricks) {
+				return errFrame
+			}
+		}
+		for _, c := range f.hp {
+			s.Bricks[c.brick].HP = c.hp
+		}
+	}
+
+	m := &f.moving
+	s.Serving, s.Cleared, s.GameOver = m.Serving, m.Cleared, m.GameOver
+	s.ViewX, s.PaddleX, s.PaddleW, s.PaddleY, s.BallX, s.BallY = m.ViewX, m.PaddleX, m.PaddleW, m.PaddleY, m.BallX, m.BallY
+	s.Score, s.Lives, s.BricksRemaining, s.BricksTotal = m.Score, m.Lives, m.BricksRemaining, m.BricksTotal
+	s.Drops, s.Projectiles, s.Boss = m.Drops, m.Projectiles, m.Boss
+	return nil
+}
+
+// decoder reads the fields of a frame; the first error sticks.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errFrame
+	}
+	d.b = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) < 1 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) uint() int {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 || v > math.MaxInt32 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return int(v)
+}
+
+func (d *decoder) int() int {
+	v, n := binary.Varint(d.b)
+	if n <= 0 || v > math.MaxInt32 || v < math.MinInt32 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return int(v)
+}
+
+// count reads a length, bounded by the bytes left so that a corrupt frame
+// cannot make us allocate much.
+func (d *decoder) count() int {
+	n := d.uint()
+	if n > len(d.b) {
+		d.fail()
+		return 0
+	}
+	return n
+}
+
+func (d *decoder) float() float64 {
+	if len(d.b) < 4 {
+		d.fail()
+		return 0
+	}
+	v := math.Float32frombits(binary.LittleEndian.Uint32(d.b))
+	d.b = d.b[4:]
+	return float64(v)
+}
+
+func (d *decoder) string() string {
+	n := d.count()
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
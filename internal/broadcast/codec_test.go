@@ -0,0 +1,217 @@
+package broadcast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+// testState returns a game on a 20-week calendar.
+func testState() game.State {
+	var cal github.Calendar
+	start := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	for w := range 20 {
+		var week github.Week
+		for d := range 7 {
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Date:              start.AddDate(0, 0, w*7+d).Format("2006-01-02"),
+				Weekday:           d,
+				ContributionCount: (w*3 + d) % 6,
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	grid := mapping.BuildBrickGrid(cal, 30, mapping.GridOptions{MaxRows: game.MaxBrickRows(24)})
+	return game.NewStateWithOptions(grid, 60, 24, 1, game.Options{Lives: 3})
+}
+
+// sameView reports how got differs from what want shows, or "".
+func sameView(want, got *game.State) string {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-3 }
+	switch {
+	case got.Width != want.Width || got.Height != want.Height || got.ViewW != want.ViewW || len(got.Bricks) != len(want.Bricks):
+		return "layout"
+	case !near(got.BallX, want.BallX) || !near(got.BallY, want.BallY):
+		return "ball"
+	case !near(got.PaddleX, want.PaddleX) || !near(got.PaddleW, want.PaddleW):
+		return "paddle"
+	case got.Score != want.Score || got.Lives != want.Lives || got.BricksRemaining != want.BricksRemaining || got.GameOver != want.GameOver:
+		return "hud"
+	}
+	for i := range want.Bricks {
+		w, g := want.Bricks[i], got.Bricks[i]
+		if g.HP != w.HP || g.X != w.X || g.Y != w.Y || g.Streak != w.Streak {
+			return "bricks"
+		}
+		if got.BrickAt(w.X, w.Y) != i {
+			return "brick index"
+		}
+	}
+	return ""
+}
+
+func TestCodec_KeyframeThenDiffs(t *testing.T) {
+	t.Parallel()
+
+	s := testState()
+	var enc encoder
+	var got game.State
+	apply := func(frame []byte) *Frame {
+		t.Helper()
+		f, err := decodeFrame(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Apply(&got); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	_, key, relayout := enc.frames("alice", &s, 1.5, 3, true)
+	if !relayout || key == nil {
+		t.Fatalf("the first frame should be a keyframe")
+	}
+	if f := apply(key); !f.Key || f.Login != "alice" || f.Lives != 3 || f.Speed != 1.5 {
+		t.Fatalf("unexpected keyframe %+v", f)
+	}
+	if d := sameView(&s, &got); d != "" {
+		t.Fatalf("keyframe: %s differs", d)
+	}
+
+	largest := 0
+	for step := range 3000 {
+		s.Step(1.0/120, game.Input{Target: true, TargetX: s.BallX})
+		diff, key, relayout := enc.frames("alice", &s, 1.5, 3, false)
+		if key != nil || relayout {
+			t.Fatalf("step %d: unexpected keyframe", step)
+		}
+		if diff == nil {
+			continue
+		}
+		largest = max(largest, len(diff))
+		apply(diff)
+		if d := sameView(&s, &got); d != "" {
+			t.Fatalf("step %d: %s differs", step, d)
+		}
+	}
+	if s.BricksRemaining == s.BricksTotal {
+		t.Fatalf("the test should break some bricks")
+	}
+	if largest > 80 {
+		t.Fatalf("diff frames should be compact, got %d bytes", largest)
+	}
+
+	// Nothing moves once the game is over: no frames.
+	s.GameOver = true
+	enc.frames("alice", &s, 1.5, 3, false)
+	if diff, _, _ := enc.frames("alice", &s, 1.5, 3, false); diff != nil {
+		t.Fatalf("an unchanged state should not send a frame")
+	}
+
+	// A new board needs a keyframe.
+	s = testState()
+	s.Bricks = s.Bricks[1:]
+	if diff, key, relayout := enc.frames("alice", &s, 1.5, 3, false); diff != nil || key == nil || !relayout {
+		t.Fatalf("a new layout should send a keyframe only")
+	}
+}
+
+func TestCodec_RejectsBadFrames(t *testing.T) {
+	t.Parallel()
+
+	s := testState()
+	var enc encoder
+	_, key, _ := enc.frames("alice", &s, 1, 3, true)
+	for _, frame := range [][]byte{nil, {'X'}, key[:len(key)-1], append(key, 0)} {
+		if _, err := decodeFrame(frame); err == nil {
+			t.Fatalf("%q: expected an error", frame)
+		}
+	}
+
+	s.BallX++
+	diff, _, _ := enc.frames("alice", &s, 1, 3, false)
+	f, err := decodeFrame(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var empty game.State
+	if err := f.Apply(&empty); err == nil {
+		t.Fatalf("a diff without a keyframe should fail")
+	}
+}
+
+func TestCodec_RejectsKeyframesOutsideTheField(t *testing.T) {
+	t.Parallel()
+
+	for name, corrupt := range map[string]func(s *game.State){
+		"view wider than the field": func(s *game.State) { s.ViewW = s.Width + 1 },
+		"empty view":                func(s *game.State) { s.ViewW = 0 },
+		"view scrolled too far":     func(s *game.State) { s.ViewX = float64(s.Width) },
+		"missing column":            func(s *game.State) { s.ColX = s.ColX[1:] },
+		"column outside the field":  func(s *game.State) { s.ColX[0] = -1 },
+		"brick past the right edge": func(s *game.State) { s.Bricks[0].X = s.Width - 1 },
+		"brick past the bottom":     func(s *game.State) { s.Bricks[0].Y = s.Height },
+		"huge brick":                func(s *game.State) { s.Bricks[0].W, s.Bricks[0].H = 1<<30, 1<<30 },
+		"empty brick":               func(s *game.State) { s.Bricks[0].W = 0 },
+		"brick outside the grid":    func(s *game.State) { s.Bricks[0].Col = s.BrickCols },
+		"huge boss":                 func(s *game.State) { s.Boss = &game.Boss{W: 1 << 30, H: 1 << 30, HP: 1, MaxHP: 1} },
+		"empty boss":                func(s *game.State) { s.Boss = &game.Boss{W: 0, H: 2, HP: 1, MaxHP: 1} },
+		"boss past the right edge":  func(s *game.State) { s.Boss = &game.Boss{X: float64(s.Width - 2), W: 3, H: 2, HP: 1, MaxHP: 1} },
+		"boss past the bottom":      func(s *game.State) { s.Boss = &game.Boss{Y: float64(s.Height - 1), W: 3, H: 2, HP: 1, MaxHP: 1} },
+		"boss above the field":      func(s *game.State) { s.Boss = &game.Boss{Y: -1, W: 3, H: 2, HP: 1, MaxHP: 1} },
+	} {
+		s := testState()
+		corrupt(&s)
+		var enc encoder
+		_, key, _ := enc.frames("alice", &s, 1, 3, true)
+		f, err := decodeFrame(key)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		prev := testState()
+		got := prev
+		if err := f.Apply(&got); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		if got.Width != prev.Width || got.ViewW != prev.ViewW || len(got.Bricks) != len(prev.Bricks) {
+			t.Fatalf("%s: a rejected frame should leave the state alone", name)
+		}
+	}
+}
+
+func TestCodec_BossInsideTheField(t *testing.T) {
+	t.Parallel()
+
+	s := testState()
+	var enc encoder
+	_, key, _ := enc.frames("alice", &s, 1, 3, true)
+	var got game.State
+	if f, err := decodeFrame(key); err != nil || f.Apply(&got) != nil {
+		t.Fatalf("keyframe: %v", err)
+	}
+
+	// A boss that fits applies; one that moved out of the field is rejected
+	// and leaves the last one in place.
+	s.Boss = &game.Boss{X: float64(s.Width - 3), Y: float64(s.Height - 2), W: 3, H: 2, HP: 4, MaxHP: 9}
+	_, key, _ = enc.frames("alice", &s, 1, 3, false)
+	if f, err := decodeFrame(key); err != nil || f.Apply(&got) != nil {
+		t.Fatalf("a boss inside the field should apply: %v", err)
+	}
+	if got.Boss == nil || got.Boss.W != 3 || got.Boss.HP != 4 {
+		t.Fatalf("unexpected boss %+v", got.Boss)
+	}
+	s.Boss.H = 1 << 30
+	diff, _, _ := enc.frames("alice", &s, 1, 3, false)
+	f, err := decodeFrame(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Apply(&got); err == nil || got.Boss.H != 2 {
+		t.Fatalf("a diff with a boss outside the field should fail and keep the boss, got %v, %+v", err, got.Boss)
+	}
+}
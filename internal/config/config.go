@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user defaults read from the config file.
+// Zero values mean "not set"; command-line flags always take precedence.
+type Config struct {
+	Speed float64 `yaml:"speed,omitempty"`
+	User  string  `yaml:"user,omitempty"`
+	Host  string  `yaml:"host,omitempty"`
+	Lives int     `yaml:"lives,omitempty"`
+	Keys  Keys    `yaml:"keys,omitempty"`
+
+	Difficulty string `yaml:"difficulty,omitempty"`
+	SpeedRamp  string `yaml:"speed_ramp,omitempty"`
+
+	HPScale  string `yaml:"hp_scale,omitempty"`
+	Compress string `yaml:"compress,omitempty"`
+	Layout   string `yaml:"layout,omitempty"`
+
+	Theme     string `yaml:"theme,omitempty"`
+	ThemeFile string `yaml:"theme_file,omitempty"`
+	Glyphs    string `yaml:"glyphs,omitempty"`
+	ASCII     bool   `yaml:"ascii,omitempty"`
+	Mouse     bool   `yaml:"mouse,omitempty"`
+	SubCell   string `yaml:"subcell,omitempty"`
+	NoEffects bool   `yaml:"no_effects,omitempty"`
+	Sound     string `yaml:"sound,omitempty"`
+	SoundFile string `yaml:"sound_file,omitempty"`
+}
+
+// Keys lists key names (as reported by Bubble Tea, e.g. "left", "a", "ctrl+c")
+// per action. An empty list keeps the built-in bindings for that action.
+type Keys struct {
+	Left      []string `yaml:"left,omitempty"`
+	Right     []string `yaml:"right,omitempty"`
+	Retry     []string `yaml:"retry,omitempty"`
+	Quit      []string `yaml:"quit,omitempty"`
+	SpeedUp   []string `yaml:"speed_up,omitempty"`
+	SpeedDown []string `yaml:"speed_down,omitempty"`
+	Theme     []string `yaml:"theme,omitempty"`
+	Launch    []string `yaml:"launch,omitempty"`
+	Inspect   []string `yaml:"inspect,omitempty"`
+	Up        []string `yaml:"up,omitempty"`
+	Down      []string `yaml:"down,omitempty"`
+	Boss      []string `yaml:"boss,omitempty"`
+}
+
+const (
+	dirName        = "kusa-breaker"
+	fileName       = "config.yaml"
+	themesFileName = "themes.yaml"
+)
+
+// DefaultPath returns $XDG_CONFIG_HOME/kusa-breaker/config.yaml,
+// falling back to ~/.config when XDG_CONFIG_HOME is unset.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve config directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, dirName, fileName), nil
+}
+
+// ThemesPath returns the default user themes file, next to the config file at configPath.
+func ThemesPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), themesFileName)
+}
+
+// Load reads the config file at path. A missing file is not an error and yields
+// an empty Config.
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := Parse(data, &c); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes YAML config data into c, rejecting unknown keys so typos surface early.
+func Parse(data []byte, c *Config) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		// An empty (or comment-only) file decodes as EOF.
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	return c.Validate()
+}
+
+// Validate checks value ranges that the YAML types alone cannot express.
+func (c Config) Validate() error {
+	if c.Speed < 0 {
+		return fmt.Errorf("speed must be > 0")
+	}
+	if c.Lives < 0 {
+		return fmt.Errorf("lives must be > 0")
+	}
+	return nil
+}
+
+// Marshal renders c as YAML.
+func (c Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// Template is the scaffold written by `kusa-breaker config init`.
+const Template = `# kusa-breaker configuration.
+# Command-line flags always override values set here.
+
+# Game speed multiplier (1.0 is normal).
+# speed: 1.0
+
+# GitHub username to play (default: authenticated user).
+# user: octocat
+
+# GitHub host, e.g. a GitHub Enterprise Server hostname (default: github.com).
+# host: github.com
+
+# Difficulty preset: easy, normal, hard, or insane. It sets the paddle width,
+# the ball speed and how fast it ramps up, and the defaults for lives and
+# hp_scale.
+# difficulty: normal
+
+# Speed the ball up during rallies: off, or a comma-separated list of hits=N
+# (every N paddle hits), breach (when the ball first reaches the top brick
+# row), step=F (speed-up per step, default 0.1) and max=F (top speed, default
+# from difficulty). A lost ball starts over. Default: off.
+# speed_ramp: hits=8,breach
+
+# Number of balls per game (default: from difficulty).
+# lives: 1
+
+# How contribution counts map to brick HP: linear, quartile, log, percentile,
+# fixed, or fixed:t1,t2,t3,t4 (see --hp-scale; default: from difficulty).
+# hp_scale: linear
+
+# How weeks are merged when the board is wider than the terminal:
+# max, sum, mean, latest, or scroll (keep every week and pan horizontally).
+# compress: max
+
+# Board layout: weeks, rotated (weeks as rows), mirrored, or months.
+# layout: weeks
+
+# Color theme: classic, dark-dimmed, halloween, winter, high-contrast, or a theme
+# defined in theme_file (default: themes.yaml next to this file).
+# theme: classic
+# theme_file: /path/to/themes.yaml
+
+# Also draw brick HP as a character so it reads without color: off, shade (░▒▓█),
+# or digits. Colorblind-safe themes: deuteranopia, protanopia, tritanopia.
+# glyphs: off
+
+# Plain ASCII rendering without colors (picked automatically when the terminal
+# has no color support).
+# ascii: false
+
+# Steer the paddle with the mouse (click to launch, wheel to change speed).
+# mouse: false
+
+# Draw the ball and paddle at sub-cell resolution: off, half (half blocks), or
+# braille (braille dots).
+# subcell: off
+
+# Turn off brick fragments, the ball trail and paddle flashes.
+# no_effects: false
+
+# Audio feedback: off, bell (terminal bell on brick destroy and game over), or
+# synth (chiptune blips, played with aplay, paplay or SoX, or written to
+# sound_file as WAV).
+# sound: off
+# sound_file: /path/to/kusa-breaker.wav
+
+# Key bindings. Each action takes a list of key names; omitted actions keep the defaults.
+# keys:
+#   left: [left, h, a]
+#   right: [right, l, d]
+#   retry: [r, R]
+#   quit: [q]  # ctrl+c always quits
+#   speed_up: ["+", "="]
+#   speed_down: ["-", "_"]
+#   theme: [t, T]
+#   launch: [" ", enter]
+#   inspect: [i, I]
+#   up: [up, k, w]      # up/down move the inspect cursor
+#   down: [down, j, s]
+#   boss: [b, B]        # boss stage, from the CLEAR screen
+`
+
+// Init writes Template to path, creating parent directories.
+// It refuses to overwrite an existing file unless force is set.
+func Init(path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("config already exists at %s (use --force to overwrite)", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(Template), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParse_TemplateIsValid(t *testing.T) {
+	t.Parallel()
+
+	var c Config
+	if err := Parse([]byte(Template), &c); err != nil {
+		t.Fatalf("template should parse, got %v", err)
+	}
+	if c.Speed != 0 || c.User != "" || len(c.Keys.Left) != 0 {
+		t.Fatalf("commented template should yield empty config, got %+v", c)
+	}
+}
+
+func TestParse_RejectsUnknownKeysAndBadValues(t *testing.T) {
+	t.Parallel()
+
+	var c Config
+	if err := Parse([]byte("sped: 1.5\n"), &c); err == nil {
+		t.Fatalf("expected error for unknown key")
+	}
+	if err := Parse([]byte("lives: -1\n"), &c); err == nil {
+		t.Fatalf("expected error for negative lives")
+	}
+
+	c = Config{}
+	data := []byte("speed: 1.6\nuser: octocat\nkeys:\n  left: [j]\n")
+	if err := Parse(data, &c); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if c.Speed != 1.6 || c.User != "octocat" || len(c.Keys.Left) != 1 || c.Keys.Left[0] != "j" {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+}
+
+func TestLoadAndInit(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kusa-breaker", "config.yaml")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("missing file should not be an error, got %v", err)
+	}
+	if c.Speed != 0 {
+		t.Fatalf("expected empty config, got %+v", c)
+	}
+
+	if err := Init(path, false); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := Init(path, false); err == nil {
+		t.Fatalf("expected error when config already exists")
+	}
+	if err := Init(path, true); err != nil {
+		t.Fatalf("init --force: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("load scaffold: %v", err)
+	}
+}
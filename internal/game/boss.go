@@ -0,0 +1,169 @@
+package game
+
+import (
+	"math"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+// Boss is the enemy of the boss stage: the calendar's busiest week as one big
+// brick that sweeps across the field and drops projectiles.
+type Boss struct {
+	// X, Y is the top-left corner, in field cells.
+	X, Y float64
+	W, H int
+	// VX is the horizontal speed in cells/s; the boss turns at the walls.
+	VX float64
+
+	// HP starts at MaxHP, the week's contributions; each hit takes Damage.
+	HP, MaxHP int
+	Damage    int
+	// First and Last are the week's days (see mapping.WeekTotal).
+	First, Last string
+
+	// fireIn is the time left until the next projectile.
+	fireIn float64
+	rng    uint64
+}
+
+// Projectile is a hazard dropped by the boss. The ball passes through it, but
+// the paddle must dodge it: a hit costs a ball.
+type Projectile struct {
+	X, Y float64
+}
+
+const (
+	// bossHits is about how many hits defeat the boss, whatever its HP.
+	bossHits  = 20
+	bossH     = 3
+	bossSpeed = 8.0
+	// Projectiles fall at projectileSpeed rows/s, one every fireMin..fireMax s.
+	projectileSpeed = 14.0
+	fireMin         = 1.2
+	fireMax         = 3.0
+)
+
+// NewBossState returns the boss stage for week on a width x height field: no
+// bricks, only the boss. Like NewStateWithOptions, opts sets the lives and the
+// rules; the caller may carry the score over with CarryOver.
+func NewBossState(week mapping.WeekTotal, width, height int, seed uint64, opts Options) State {
+	s := newState(mapping.BrickGrid{}, max(width, 10), width, height, seed, opts)
+	w, _ := opts.brickSize()
+	hp := max(week.Count, 1)
+	s.Boss = &Boss{
+		W: min(7*w+2, s.Width/3), H: bossH,
+		Y:  float64(s.TopOffset),
+		VX: bossSpeed,
+		HP: hp, MaxHP: hp,
+		Damage: max((hp+bossHits-1)/bossHits, 1),
+		First:  week.First, Last: week.Last,
+		fireIn: fireMax,
+		rng:    seed | 1,
+	}
+	s.Boss.X = float64(s.Width-s.Boss.W) / 2
+	return s
+}
+
+// CarryOver continues prev's run on s: its score and the balls left.
+func (s *State) CarryOver(prev *State) {
+	s.Score = prev.Score
+	s.Breakdown = prev.Breakdown
+	s.Lives = prev.Lives
+}
+
+// Resume continues prev, a boss fight on a field of another size, on s, a
+// fresh boss stage for the same week: the run, the boss's HP and the ball's
+// speed carry over, and positions are rescaled to the new field.
+func (s *State) Resume(prev *State) {
+	s.CarryOver(prev)
+	s.Stats = prev.Stats
+	s.rules = prev.rules
+	s.Serving, s.Cleared, s.GameOver = prev.Serving, prev.Cleared, prev.GameOver
+	s.BallVX, s.BallVY = prev.BallVX, prev.BallVY
+	s.inPlay, s.rampSteps, s.rallyHits = prev.inPlay, prev.rampSteps, prev.rallyHits
+
+	// Columns scale with the width; rows keep their place between the top
+	// of the field and the paddle.
+	sx := float64(s.Width) / float64(max(prev.Width, 1))
+	sy := (s.PaddleY - float64(s.TopOffset)) / math.Max(prev.PaddleY-float64(prev.TopOffset), 1)
+	y := func(v float64) float64 { return float64(s.TopOffset) + (v-float64(prev.TopOffset))*sy }
+	s.PaddleX = math.Max(0, math.Min((prev.PaddleX+prev.PaddleW/2)*sx-s.PaddleW/2, float64(s.Width)-s.PaddleW))
+	s.BallX = math.Max(0, math.Min(prev.BallX*sx, float64(s.Width-1)))
+	s.BallY = math.Max(float64(s.TopWallY), y(prev.BallY))
+	s.Projectiles = s.Projectiles[:0]
+	for _, p := range prev.Projectiles {
+		s.Projectiles = append(s.Projectiles, Projectile{X: p.X * sx, Y: y(p.Y)})
+	}
+	if b, nb := prev.Boss, s.Boss; b != nil && nb != nil {
+		nb.HP, nb.VX, nb.fireIn, nb.rng = b.HP, b.VX, b.fireIn, b.rng
+		nb.X = math.Max(0, math.Min(b.X*sx, float64(s.Width-nb.W)))
+	}
+	s.followBall()
+}
+
+// stepBoss moves the boss and its projectiles and handles the ball hitting it.
+func (s *State) stepBoss(dt, prevX, prevY float64) {
+	b := s.Boss
+	if b.HP > 0 {
+		b.X += b.VX * dt
+		if b.X < 0 {
+			b.X, b.VX = 0, math.Abs(b.VX)
+		} else if b.X+float64(b.W) > float64(s.Width) {
+			b.X, b.VX = float64(s.Width-b.W), -math.Abs(b.VX)
+		}
+
+		b.fireIn -= dt
+		if b.fireIn <= 0 {
+			b.fireIn = fireMin + (fireMax-fireMin)*b.rand()
+			s.Projectiles = append(s.Projectiles, Projectile{X: b.X + float64(b.W)/2, Y: b.Y + float64(b.H)})
+		}
+	}
+
+	paddleTop := s.PaddleY - 0.5
+	kept := s.Projectiles[:0]
+	hit := false
+	for _, p := range s.Projectiles {
+		p.Y += projectileSpeed * dt
+		switch {
+		case p.Y >= paddleTop && p.Y <= s.PaddleY+0.5 && p.X >= s.PaddleX && p.X <= s.PaddleX+s.PaddleW:
+			hit = true
+		case p.Y <= float64(s.Height):
+			kept = append(kept, p)
+		}
+	}
+	s.Projectiles = kept
+	if hit {
+		s.emit(s.ballEvent(EventHazardHit))
+		s.loseBall()
+		return
+	}
+
+	left, top := b.X, b.Y
+	right, bottom := left+float64(b.W), top+float64(b.H)
+	if b.HP <= 0 || s.BallX < left || s.BallX >= right || s.BallY < top || s.BallY >= bottom {
+		return
+	}
+	b.HP = max(b.HP-b.Damage, 0)
+	e := s.ballEvent(EventBossHit)
+	e.HPLeft, e.Count = b.HP, b.MaxHP
+	s.emit(e)
+	s.bounceOff(prevX, prevY, left, right, top, bottom)
+	// The boss may be faster than the ball: make sure it leaves sideways.
+	if s.BallX < left || s.BallX >= right {
+		s.BallVX = math.Copysign(math.Max(math.Abs(s.BallVX), bossSpeed+4), s.BallVX)
+	}
+	if b.HP == 0 {
+		s.Projectiles = s.Projectiles[:0]
+		s.emit(s.ballEvent(EventBossDefeated))
+		s.Cleared = true
+		s.emit(s.ballEvent(EventCleared))
+	}
+}
+
+// rand returns a pseudo-random number in [0, 1) (xorshift64).
+func (b *Boss) rand() float64 {
+	b.rng ^= b.rng << 13
+	b.rng ^= b.rng >> 7
+	b.rng ^= b.rng << 17
+	return float64(b.rng>>11) / (1 << 53)
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+func bossState() State {
+	week := mapping.WeekTotal{First: "2025-03-09", Last: "2025-03-15", Count: 95}
+	return NewBossState(week, 80, 30, 1, Options{Lives: 3})
+}
+
+// hitBoss sends the ball up into the boss from below.
+func hitBoss(t *testing.T, s *State) Event {
+	t.Helper()
+	b := s.Boss
+	s.BallX, s.BallY = b.X+float64(b.W)/2, b.Y+float64(b.H)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	return stepUntil(t, s, EventBossHit, 20)
+}
+
+func TestNewBossState(t *testing.T) {
+	t.Parallel()
+
+	s := bossState()
+	b := s.Boss
+	if len(s.Bricks) != 0 || s.Width != 80 || b == nil {
+		t.Fatalf("the boss stage should be an 80-wide field with only the boss")
+	}
+	if b.HP != 95 || b.MaxHP != 95 || b.Damage != 5 || b.First != "2025-03-09" {
+		t.Fatalf("unexpected boss %+v", b)
+	}
+	if b.W != 16 || b.X != 32 || b.Y != float64(s.TopOffset) {
+		t.Fatalf("boss should be centered below the ceiling, got %+v", b)
+	}
+}
+
+func TestBoss_MovesAndTurns(t *testing.T) {
+	t.Parallel()
+
+	s := bossState()
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 1, 20, 0, -0.1 // out of the way
+	x := s.Boss.X
+	s.Step(stepDT, Input{})
+	if s.Boss.X <= x {
+		t.Fatalf("the boss should move right first")
+	}
+	s.Boss.X = float64(s.Width - s.Boss.W)
+	s.Step(stepDT, Input{})
+	if s.Boss.VX >= 0 || s.Boss.X+float64(s.Boss.W) > float64(s.Width) {
+		t.Fatalf("the boss should turn at the right wall, got %+v", s.Boss)
+	}
+}
+
+func TestBoss_HitsAndDefeat(t *testing.T) {
+	t.Parallel()
+
+	s := bossState()
+	e := hitBoss(t, &s)
+	if e.HPLeft != 90 || s.Boss.HP != 90 || s.BallVY <= 0 {
+		t.Fatalf("a hit should take 5 HP and bounce the ball, got %+v", e)
+	}
+	if s.Breakdown[ScoreBricks] != 50 {
+		t.Fatalf("a boss hit is worth 10 points per HP, got %v", s.Breakdown)
+	}
+
+	s.Boss.HP = 3
+	hitBoss(t, &s)
+	kinds := map[EventKind]bool{}
+	for _, e := range s.Events() {
+		kinds[e.Kind] = true
+	}
+	if !kinds[EventBossDefeated] || !kinds[EventCleared] || !s.Cleared || s.Boss.HP != 0 {
+		t.Fatalf("the last hit should defeat the boss and clear the stage, got %+v", s.Events())
+	}
+}
+
+func TestBoss_ProjectileCostsABall(t *testing.T) {
+	t.Parallel()
+
+	s := bossState()
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 1, 20, 0, -0.1
+	s.Boss.fireIn = stepDT / 2
+	s.Step(stepDT, Input{})
+	if len(s.Projectiles) != 1 {
+		t.Fatalf("the boss should fire, got %+v", s.Projectiles)
+	}
+	if s.Boss.fireIn < fireMin || s.Boss.fireIn > fireMax {
+		t.Fatalf("next shot out of range: %.2f", s.Boss.fireIn)
+	}
+
+	// Park the paddle under the projectile.
+	p := s.Projectiles[0]
+	s.PaddleX = p.X - s.PaddleW/2
+	stepUntil(t, &s, EventHazardHit, 240)
+	if s.Lives != 2 || len(s.Projectiles) != 0 {
+		t.Fatalf("a hit should cost a ball and clear the projectiles, lives %d", s.Lives)
+	}
+	lost := false
+	for _, e := range s.Events() {
+		lost = lost || e.Kind == EventBallLost
+	}
+	if !lost {
+		t.Fatalf("EventBallLost should follow EventHazardHit")
+	}
+}
+
+func TestCarryOver(t *testing.T) {
+	t.Parallel()
+
+	board := NewState(testGrid(1, 20, 1), 80, 30, 1)
+	board.Award(ScoreBricks, 300)
+	board.Lives = 2
+	s := bossState()
+	s.CarryOver(&board)
+	if s.Score != 300 || s.Breakdown[ScoreBricks] != 300 || s.Lives != 2 {
+		t.Fatalf("score and lives should carry over, got %d %v %d", s.Score, s.Breakdown, s.Lives)
+	}
+}
+
+func TestResume(t *testing.T) {
+	t.Parallel()
+
+	fight := bossState()
+	hitBoss(t, &fight)
+	fight.Lives = 1
+	fight.Boss.X = 60
+	fight.BallX = 70
+	fight.Projectiles = append(fight.Projectiles, Projectile{X: 40, Y: 20})
+
+	week := mapping.WeekTotal{First: "2025-03-09", Last: "2025-03-15", Count: 95}
+	s := NewBossState(week, 40, 30, 1, Options{Lives: 3})
+	s.Resume(&fight)
+	if s.Boss.HP != fight.Boss.HP || s.Lives != 1 || s.Score != fight.Score || s.Stats != fight.Stats {
+		t.Fatalf("the fight should carry over, got boss HP %d/%d, %d lives, score %d", s.Boss.HP, fight.Boss.HP, s.Lives, s.Score)
+	}
+	if s.Boss.X+float64(s.Boss.W) > float64(s.Width) || s.BallX != 35 || s.Projectiles[0].X != 20 {
+		t.Fatalf("positions should scale to the narrower field: boss x %.1f, ball x %.1f, %+v", s.Boss.X, s.BallX, s.Projectiles)
+	}
+}
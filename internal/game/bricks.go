@@ -0,0 +1,160 @@
+package game
+
+import "github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+
+// Brick is a rectangle of field cells built from one grid cell.
+type Brick struct {
+	X, Y int // top-left field cell
+	W, H int
+
+	Row, Col int // position in the source mapping.BrickGrid
+
+	HP    int // hits left (0 = destroyed)
+	MaxHP int // initial HP (for scoring)
+
+	// Count is the contribution count of the cell, and First..Last the days it
+	// covers (see mapping.BrickCell).
+	Count       int
+	First, Last string
+	// Streak bricks cover a day of a contribution streak; they break into a
+	// power-up (see PowerUp).
+	Streak bool
+}
+
+// Options tunes NewStateWithOptions. The zero value matches NewState.
+type Options struct {
+	// BrickW is the width of each brick in columns (1..3). 0 means 2.
+	BrickW int
+	// BrickH is the height of each brick in rows. 0 means 1.
+	BrickH int
+	// MonthGutters inserts an empty column before grid columns that start a new
+	// month (see mapping.BrickGrid.MonthStarts).
+	MonthGutters bool
+	// Lives is the number of balls per game. 0 means Difficulty.Lives.
+	Lives int
+	// Difficulty sets the paddle width, the ball speed and how it ramps up.
+	// The zero value means DefaultDifficulty.
+	Difficulty Difficulty
+	// SpeedRamp speeds the ball up during rallies. The zero value never does.
+	SpeedRamp SpeedRamp
+	// ManualServe keeps each new ball on the paddle until Input.Launch.
+	ManualServe bool
+	// Rules returns the scoring rules for a new game. nil means
+	// NewStandardRules.
+	Rules func() Rules
+}
+
+const (
+	defaultBrickW = 2
+	maxBrickW     = 3
+)
+
+func (o Options) brickSize() (w, h int) {
+	w, h = o.BrickW, o.BrickH
+	if w <= 0 {
+		w = defaultBrickW
+	}
+	w = min(w, maxBrickW)
+	if h <= 0 {
+		h = 1
+	}
+	return w, h
+}
+
+func (o Options) difficulty() Difficulty {
+	if o.Difficulty.Name == "" {
+		return DefaultDifficulty
+	}
+	return o.Difficulty
+}
+
+// BoardWidth returns the field width NewStateWithOptions uses for grid.
+func BoardWidth(grid mapping.BrickGrid, opts Options) int {
+	w, _ := opts.brickSize()
+	width := grid.Cols * w
+	if opts.MonthGutters {
+		width += gutterCount(grid)
+	}
+	return width
+}
+
+func gutterCount(grid mapping.BrickGrid) int {
+	n := 0
+	for c := 1; c < len(grid.MonthStarts) && c < grid.Cols; c++ {
+		if grid.MonthStarts[c] {
+			n++
+		}
+	}
+	return n
+}
+
+// layoutBricks places non-empty grid cells on the field starting at row top.
+// It returns the bricks and the x offset of each grid column.
+func layoutBricks(grid mapping.BrickGrid, top int, opts Options) ([]Brick, []int) {
+	bw, bh := opts.brickSize()
+
+	colX := make([]int, grid.Cols)
+	x := 0
+	for c := 0; c < grid.Cols; c++ {
+		if opts.MonthGutters && c > 0 && c < len(grid.MonthStarts) && grid.MonthStarts[c] {
+			x++
+		}
+		colX[c] = x
+		x += bw
+	}
+
+	var bricks []Brick
+	for r := 0; r < grid.Rows; r++ {
+		for c := 0; c < grid.Cols; c++ {
+			cell := grid.Cells[r][c]
+			if cell.HP <= 0 {
+				continue
+			}
+			bricks = append(bricks, Brick{
+				X: colX[c], Y: top + r*bh, W: bw, H: bh,
+				Row: r, Col: c,
+				HP: cell.HP, MaxHP: cell.HP,
+				Count: cell.Count, First: cell.First, Last: cell.Last,
+				Streak: cell.Streak,
+			})
+		}
+	}
+	return bricks, colX
+}
+
+// indexBricks builds the cell -> brick lookup used by collisions and renderers.
+func (s *State) indexBricks() {
+	s.brickAt = make([]int32, s.Width*s.Height)
+	for i := range s.brickAt {
+		s.brickAt[i] = -1
+	}
+	for i, b := range s.Bricks {
+		for y := b.Y; y < b.Y+b.H; y++ {
+			for x := b.X; x < b.X+b.W; x++ {
+				if x >= 0 && x < s.Width && y >= 0 && y < s.Height {
+					s.brickAt[y*s.Width+x] = int32(i)
+				}
+			}
+		}
+	}
+}
+
+// IndexBricks rebuilds the lookup of BrickAt after Bricks or the field size
+// were replaced from outside the engine, e.g. by a decoded broadcast frame.
+func (s *State) IndexBricks() {
+	s.indexBricks()
+}
+
+// BrickAt returns the index into Bricks covering field cell (x, y), or -1.
+// Destroyed bricks are still returned; check HP.
+func (s *State) BrickAt(x, y int) int {
+	if x < 0 || y < 0 || x >= s.Width || y >= s.Height || len(s.brickAt) == 0 {
+		return -1
+	}
+	return int(s.brickAt[y*s.Width+x])
+}
+
+// BrickBand returns the field rows [top, bottom) that may contain bricks.
+func (s *State) BrickBand() (top, bottom int) {
+	return s.TopOffset, s.TopOffset + s.BrickRows*s.BrickH
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Difficulty is a preset of how hard a game is. The presets live in
+// difficulties; pick one with ParseDifficulty.
+type Difficulty struct {
+	Name string
+
+	// The paddle is 1/PaddleDiv of the visible field wide, but at least
+	// PaddleMin cells.
+	PaddleDiv float64
+	PaddleMin float64
+	// BallVY is the vertical ball speed of a fresh serve, and BallVX the
+	// largest horizontal one, in cells/s.
+	BallVY float64
+	BallVX float64
+	// Ramp speeds the ball up by this fraction per minute a ball stays in play.
+	// MaxPace caps it and the SpeedRamp steps, as a multiple of the serve speed;
+	// with no Ramp and the default (off) SpeedRamp, it never applies.
+	Ramp    float64
+	MaxPace float64
+
+	// Lives is the default number of balls per game (Options.Lives wins).
+	Lives int
+	// HPScale names the default mapping.HPScale (see mapping.ParseHPScale).
+	HPScale string
+}
+
+// difficulties is the preset table, easiest first. "normal" plays like the
+// game did before presets existed.
+var difficulties = []Difficulty{
+	{Name: "easy", PaddleDiv: 4, PaddleMin: 8, BallVY: 14, BallVX: 8, Ramp: 0, MaxPace: 1.25, Lives: 3, HPScale: "linear"},
+	{Name: "normal", PaddleDiv: 5, PaddleMin: 6, BallVY: 18, BallVX: 10, Ramp: 0, MaxPace: 1.5, Lives: 1, HPScale: "linear"},
+	{Name: "hard", PaddleDiv: 6, PaddleMin: 5, BallVY: 21, BallVX: 12, Ramp: 0.15, MaxPace: 1.75, Lives: 1, HPScale: "quartile"},
+	{Name: "insane", PaddleDiv: 8, PaddleMin: 4, BallVY: 24, BallVX: 14, Ramp: 0.3, MaxPace: 2, Lives: 1, HPScale: "log"},
+}
+
+// DefaultDifficulty is the "normal" preset.
+var DefaultDifficulty = difficulties[1]
+
+// DifficultyNames lists the values accepted by ParseDifficulty, easiest first.
+func DifficultyNames() []string {
+	names := make([]string, len(difficulties))
+	for i, d := range difficulties {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// ParseDifficulty resolves a preset by name. An empty name selects
+// DefaultDifficulty.
+func ParseDifficulty(name string) (Difficulty, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultDifficulty, nil
+	}
+	for _, d := range difficulties {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty %q (expected one of: %s)", name, strings.Join(DifficultyNames(), ", "))
+}
+
+// paddleW returns the paddle width for a view viewW columns wide.
+func (d Difficulty) paddleW(viewW int) float64 {
+	return math.Max(d.PaddleMin, float64(viewW)/d.PaddleDiv)
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	t.Parallel()
+
+	if got := DifficultyNames(); !slices.Equal(got, []string{"easy", "normal", "hard", "insane"}) {
+		t.Fatalf("unexpected names %v", got)
+	}
+	for _, name := range DifficultyNames() {
+		d, err := ParseDifficulty(" " + name + " ")
+		if err != nil || d.Name != name {
+			t.Fatalf("%s: got %+v, %v", name, d, err)
+		}
+	}
+	if d, err := ParseDifficulty(""); err != nil || d != DefaultDifficulty || d.Name != "normal" {
+		t.Fatalf("empty name should select normal, got %+v, %v", d, err)
+	}
+	if _, err := ParseDifficulty("nightmare"); err == nil {
+		t.Fatalf("expected an error for an unknown difficulty")
+	}
+}
+
+func TestDifficulty_Presets(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(1, 20, 1)
+	prevW, prevVY := math.Inf(1), 0.0
+	for _, name := range DifficultyNames() {
+		d, _ := ParseDifficulty(name)
+		s := NewStateWithOptions(grid, 60, 30, 1, Options{Difficulty: d})
+		if s.Difficulty.Name != name || s.Lives != d.Lives {
+			t.Fatalf("%s: difficulty %q, lives %d", name, s.Difficulty.Name, s.Lives)
+		}
+		if s.PaddleW >= prevW || -s.BallVY <= prevVY {
+			t.Fatalf("%s: paddle %.2f, ball %.2f should be harder than the previous preset", name, s.PaddleW, -s.BallVY)
+		}
+		prevW, prevVY = s.PaddleW, -s.BallVY
+	}
+
+	// The zero value plays as normal, and Options.Lives wins over the preset.
+	s := NewState(grid, 60, 30, 1)
+	if s.Difficulty != DefaultDifficulty || s.PaddleW != 8 || s.BallVY != -18 {
+		t.Fatalf("zero options should play normal, got %+v", s.Difficulty)
+	}
+	easy, _ := ParseDifficulty("easy")
+	if s := NewStateWithOptions(grid, 60, 30, 1, Options{Difficulty: easy, Lives: 5}); s.Lives != 5 {
+		t.Fatalf("Options.Lives should win, got %d", s.Lives)
+	}
+}
+
+func TestDifficulty_PaceRamps(t *testing.T) {
+	t.Parallel()
+
+	hard, _ := ParseDifficulty("hard")
+	s := NewStateWithOptions(testGrid(1, 20, 1), 40, 30, 1, Options{Difficulty: hard, Lives: 2})
+	s.Bricks = s.Bricks[:0] // keep the ball from bouncing off bricks
+	s.indexBricks()
+	if s.Pace() != 1 {
+		t.Fatalf("a fresh ball should start at its serve speed, got %.2f", s.Pace())
+	}
+	s.inPlay = 60
+	if want := 1 + hard.Ramp; math.Abs(s.Pace()-want) > 1e-9 {
+		t.Fatalf("pace after a minute = %.3f, want %.3f", s.Pace(), want)
+	}
+	s.inPlay = 3600
+	if s.Pace() != hard.MaxPace {
+		t.Fatalf("pace should stop at %.2f, got %.2f", hard.MaxPace, s.Pace())
+	}
+
+	// The ball moves faster at a higher pace, and a new ball starts over.
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 20, 15, 0, -10
+	s.Step(stepDT, Input{})
+	if got, want := 15-s.BallY, 10*stepDT*hard.MaxPace; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("ball moved %.4f, want %.4f", got, want)
+	}
+	s.BallY, s.BallVY = float64(s.Height)+1, 10
+	stepUntil(t, &s, EventBallLost, 1)
+	if s.Pace() != 1 {
+		t.Fatalf("pace should reset with a new ball, got %.2f", s.Pace())
+	}
+
+	n := NewState(testGrid(1, 20, 1), 40, 30, 1)
+	if n.inPlay = 3600; n.Pace() != 1 {
+		t.Fatalf("normal should not ramp over time, got %.2f", n.Pace())
+	}
+}
@@ -8,79 +8,167 @@ import (
 )
 
 type Input struct {
-	Move int // -1 left, 0 none, +1 right
+	Move int // held direction: -1 left, 0 none, +1 right
+
+	// Target steers the paddle center toward the absolute field column TargetX
+	// (e.g. the mouse position) instead of using Move.
+	Target  bool
+	TargetX float64
+
+	// Launch serves a ball that is waiting on the paddle (see Options.ManualServe).
+	Launch bool
 }
 
 type State struct {
 	Width  int
 	Height int
 
+	// ViewW is the visible width of the field. It is smaller than Width only when the
+	// board is wider than the terminal (1:1 weeks); the view then pans to follow the
+	// ball, starting at column ViewX.
+	ViewW int
+	ViewX float64
+
 	TopOffset int
 	TopWallY  int
+
+	// Brick geometry. Bricks are BrickW x BrickH rectangles laid out from a
+	// BrickRows x BrickCols grid; ColX gives the x of each grid column (gutters
+	// between months shift later columns right).
 	BrickW    int
+	BrickH    int
+	BrickRows int
+	BrickCols int
+	ColX      []int
 
-	Bricks   [][]int // [row][col] HP
-	BrickMax [][]int // [row][col] initial HP (for scoring)
+	Bricks []Brick
 
 	PaddleX float64
 	PaddleW float64
 	PaddleY float64
+	// basePaddleW is PaddleW without power-ups.
+	basePaddleW float64
+
+	// Paddle kinematics under Input.Move, in cells/s and cells/s². Holding a
+	// direction accelerates up to PaddleMaxSpeed; releasing (or reversing) brakes
+	// at PaddleDecel.
+	PaddleVX       float64
+	PaddleAccel    float64
+	PaddleDecel    float64
+	PaddleMaxSpeed float64
 
 	BallX  float64
 	BallY  float64
 	BallVX float64
 	BallVY float64
 
+	// Lives is the number of balls left, including the one in play.
+	Lives int
+	// Serving is set while a ball rests on the paddle waiting for Input.Launch.
+	Serving bool
+	// Difficulty is the preset the game is played at (see Options.Difficulty).
+	Difficulty Difficulty
+
+	// Score is the total of Breakdown.
 	Score           int
+	Breakdown       ScoreBreakdown
 	BricksRemaining int
 	BricksTotal     int
 	Cleared         bool
 	GameOver        bool
+
+	Stats Stats
+
+	// Drops are the power-ups falling from destroyed streak bricks. WideLeft and
+	// SlowLeft are the seconds left of PowerWide and PowerSlow (0 = inactive).
+	Drops    []Drop
+	WideLeft float64
+	SlowLeft float64
+
+	// Boss is set on the boss stage (see NewBossState), with the Projectiles it
+	// dropped.
+	Boss        *Boss
+	Projectiles []Projectile
+
+	seed      uint64
+	serves    uint64
+	speedRamp SpeedRamp
+	// The current ball's time in play, SpeedRamp steps and paddle hits, and
+	// whether it reached the deepest brick row (see Pace).
+	inPlay      float64
+	rampSteps   int
+	rallyHits   int
+	breached    bool
+	manualServe bool
+	rules       Rules
+	brickAt     []int32 // [y*Width+x] index into Bricks, -1 if none
+	events      []Event // reused by every Step
+}
+
+const (
+	defaultTopOffset = 7
+	minTopOffset     = 1
+	// minPlayRows keeps room between the lowest brick row and the paddle.
+	minPlayRows = 6
+
+	defaultPaddleMaxSpeed = 70.0
+	defaultPaddleAccel    = 420.0
+	defaultPaddleDecel    = 700.0
+	// targetSpeed caps how fast the paddle chases Input.TargetX, so pointing
+	// devices can't teleport it across the field.
+	targetSpeed = 240.0
+)
+
+// MaxBrickRows returns how many brick rows fit in a field of the given height
+// while leaving room to play.
+func MaxBrickRows(height int) int {
+	return max(height-minTopOffset-minPlayRows, 1)
 }
 
 func NewState(grid mapping.BrickGrid, width, height int, seed uint64) State {
+	return NewStateWithOptions(grid, width, height, seed, Options{})
+}
+
+func NewStateWithOptions(grid mapping.BrickGrid, width, height int, seed uint64, opts Options) State {
+	return newState(grid, BoardWidth(grid, opts), width, height, seed, opts)
+}
+
+// newState lays out grid on a field fieldW columns wide, viewed in a terminal
+// area of width x height.
+func newState(grid mapping.BrickGrid, fieldW, width, height int, seed uint64, opts Options) State {
 	if width < 10 {
 		width = 10
 	}
 	if height < 10 {
 		height = 10
 	}
+	brickW, brickH := opts.brickSize()
+	diff := opts.difficulty()
+	lives := opts.Lives
+	if lives <= 0 {
+		lives = max(diff.Lives, 1)
+	}
 
-	// Place bricks lower to shorten travel distance and speed up gameplay.
-	topOffset := 7
+	// Place bricks lower to shorten travel distance and speed up gameplay,
+	// but move them up when a tall layout needs the room.
+	topOffset := min(defaultTopOffset, max(height-minPlayRows-grid.Rows*brickH, minTopOffset))
 	topWallY := 0
 	if topOffset > 0 {
 		topWallY = topOffset - 1
 	}
-	brickW := 2
-	fieldW := grid.Cols * brickW
-	if fieldW > width {
-		// Engine assumes bricks already compressed to fit; still guard.
-		fieldW = width
-	}
-
-	bricks := make([][]int, grid.Rows)
-	brickMax := make([][]int, grid.Rows)
-	remain := 0
-	for r := 0; r < grid.Rows; r++ {
-		bricks[r] = make([]int, grid.Cols)
-		brickMax[r] = make([]int, grid.Cols)
-		for c := 0; c < grid.Cols; c++ {
-			bricks[r][c] = grid.Cells[r][c].HP
-			brickMax[r][c] = bricks[r][c]
-			if bricks[r][c] > 0 {
-				remain++
-			}
-		}
-	}
+	// A board wider than the terminal scrolls horizontally.
+	viewW := min(fieldW, width)
+
+	bricks, colX := layoutBricks(grid, topOffset, opts)
+	remain := len(bricks)
 
-	paddleW := math.Max(6, float64(fieldW)/5.0)
+	paddleW := math.Min(diff.paddleW(viewW), float64(fieldW))
 	paddleY := float64(height - 2)
 	paddleX := float64(fieldW)/2.0 - paddleW/2.0
 
 	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
-	vx := (rng.Float64()*2 - 1) * 10
-	vy := -18.0
+	vx := (rng.Float64()*2 - 1) * diff.BallVX
+	vy := -diff.BallVY
 	if vx == 0 {
 		vx = 6
 	}
@@ -88,41 +176,148 @@ func NewState(grid mapping.BrickGrid, width, height int, seed uint64) State {
 	s := State{
 		Width:  fieldW,
 		Height: height,
+		ViewW:  viewW,
 
 		TopOffset: topOffset,
 		TopWallY:  topWallY,
 		BrickW:    brickW,
+		BrickH:    brickH,
+		BrickRows: grid.Rows,
+		BrickCols: grid.Cols,
+		ColX:      colX,
 		Bricks:    bricks,
-		BrickMax:  brickMax,
 
 		PaddleX: paddleX,
 		PaddleW: paddleW,
 		PaddleY: paddleY,
 
+		basePaddleW: paddleW,
+
+		PaddleAccel:    defaultPaddleAccel,
+		PaddleDecel:    defaultPaddleDecel,
+		PaddleMaxSpeed: defaultPaddleMaxSpeed,
+
 		BallX:  float64(fieldW) / 2.0,
 		BallY:  paddleY - 1,
 		BallVX: vx,
 		BallVY: vy,
 
+		Lives:      lives,
+		Serving:    opts.ManualServe,
+		Difficulty: diff,
+
 		BricksRemaining: remain,
 		BricksTotal:     remain,
+		Stats:           newStats(bricks),
+
+		seed:        seed,
+		manualServe: opts.ManualServe,
+		speedRamp:   opts.SpeedRamp,
+		rules:       newRules(opts.Rules),
 	}
+	s.indexBricks()
+	s.followBall()
 
 	return s
 }
 
+// Refit continues prev, a game in progress, on s, a fresh state for the same
+// grid and options on a terminal area of another size: the bricks left, the
+// run and the ball carry over. The field is as wide as before, so only rows
+// move; rows below the bricks are rescaled between the bricks and the paddle.
+// It reports false, leaving s as it was, when the bricks are laid out
+// differently and the game cannot continue.
+func (s *State) Refit(prev *State) bool {
+	if s.Width != prev.Width || s.BrickW != prev.BrickW || s.BrickH != prev.BrickH || len(s.Bricks) != len(prev.Bricks) {
+		return false
+	}
+	for i, b := range s.Bricks {
+		p := prev.Bricks[i]
+		if b.X != p.X || b.W != p.W || b.Row != p.Row || b.Col != p.Col {
+			return false
+		}
+	}
+
+	_, prevBottom := prev.BrickBand()
+	_, bottom := s.BrickBand()
+	dy := float64(s.TopOffset - prev.TopOffset)
+	sy := (s.PaddleY - float64(bottom)) / math.Max(prev.PaddleY-float64(prevBottom), 1)
+	y := func(v float64) float64 {
+		if v < float64(prevBottom) {
+			return v + dy
+		}
+		return float64(bottom) + (v-float64(prevBottom))*sy
+	}
+
+	next := *prev
+	next.Height, next.ViewW = s.Height, s.ViewW
+	next.TopOffset, next.TopWallY, next.PaddleY = s.TopOffset, s.TopWallY, s.PaddleY
+	next.Bricks = s.Bricks
+	for i := range next.Bricks {
+		next.Bricks[i].HP = prev.Bricks[i].HP
+	}
+	next.BallY = math.Max(float64(next.TopWallY), y(prev.BallY))
+	if next.Serving {
+		next.BallY = next.PaddleY - 1
+	}
+	next.Drops = make([]Drop, len(prev.Drops))
+	for i, d := range prev.Drops {
+		next.Drops[i] = Drop{X: d.X, Y: y(d.Y), Power: d.Power}
+	}
+	next.Projectiles = make([]Projectile, len(prev.Projectiles))
+	for i, p := range prev.Projectiles {
+		next.Projectiles[i] = Projectile{X: p.X, Y: y(p.Y)}
+	}
+	if prev.Boss != nil {
+		b := *prev.Boss
+		b.Y += dy
+		next.Boss = &b
+	}
+	next.events = nil
+	*s = next
+	s.indexBricks()
+	s.followBall()
+	return true
+}
+
+// followBall pans the view so the ball stays away from the view edges. The
+// paddle always stays in view, even when that leaves the ball outside.
+func (s *State) followBall() {
+	if s.ViewW >= s.Width {
+		s.ViewX = 0
+		return
+	}
+	viewW := float64(s.ViewW)
+	margin := viewW / 3.0
+	if s.BallX < s.ViewX+margin {
+		s.ViewX = s.BallX - margin
+	} else if s.BallX > s.ViewX+viewW-margin {
+		s.ViewX = s.BallX - viewW + margin
+	}
+	s.ViewX = math.Max(s.ViewX, s.PaddleX+s.PaddleW-viewW)
+	s.ViewX = math.Min(s.ViewX, s.PaddleX)
+	s.ViewX = math.Max(0, math.Min(s.ViewX, float64(s.Width-s.ViewW)))
+}
+
+// ViewLeft returns the first visible field column.
+func (s *State) ViewLeft() int {
+	return int(s.ViewX)
+}
+
 func (s *State) ResetBall(seed uint64) {
 	rng := rand.New(rand.NewPCG(seed, seed^0x517cc1b727220a95))
 	s.BallX = float64(s.Width) / 2.0
 	s.BallY = s.PaddleY - 1
-	s.BallVX = (rng.Float64()*2 - 1) * 10
+	s.BallVX = (rng.Float64()*2 - 1) * s.Difficulty.BallVX
 	if s.BallVX == 0 {
 		s.BallVX = 6
 	}
-	s.BallVY = -18.0
+	s.BallVY = -s.Difficulty.BallVY
+	s.resetRamp()
 }
 
 func (s *State) Step(dt float64, in Input) {
+	s.events = s.events[:0]
 	if s.Cleared || s.GameOver {
 		return
 	}
@@ -131,40 +326,80 @@ func (s *State) Step(dt float64, in Input) {
 	prevY := s.BallY
 
 	// Paddle move.
-	paddleSpeed := 70.0
-	s.PaddleX += float64(in.Move) * paddleSpeed * dt
+	if in.Target {
+		d := in.TargetX - (s.PaddleX + s.PaddleW/2.0)
+		maxD := targetSpeed * dt
+		s.PaddleX += math.Max(-maxD, math.Min(d, maxD))
+		s.PaddleVX = 0
+	} else {
+		s.accelPaddle(dt, in.Move)
+		s.PaddleX += s.PaddleVX * dt
+	}
 	if s.PaddleX < 0 {
 		s.PaddleX = 0
+		s.PaddleVX = 0
 	}
 	if s.PaddleX+s.PaddleW > float64(s.Width) {
 		s.PaddleX = float64(s.Width) - s.PaddleW
+		s.PaddleVX = 0
+	}
+
+	// A served ball rides on the paddle until launched.
+	if s.Serving {
+		s.BallX = s.PaddleX + s.PaddleW/2.0
+		s.BallY = s.PaddleY - 1
+		if in.Launch {
+			s.Serving = false
+		}
+		s.followBall()
+		return
 	}
 
+	s.Stats.PlayTime += dt
+	s.inPlay += dt
+	s.stepPowerUps(dt)
+
 	// Integrate ball.
-	s.BallX += s.BallVX * dt
-	s.BallY += s.BallVY * dt
+	ballDT := dt * s.Pace()
+	if s.SlowLeft > 0 {
+		ballDT *= slowFactor
+	}
+	s.BallX += s.BallVX * ballDT
+	s.BallY += s.BallVY * ballDT
 
 	// Wall collisions.
 	if s.BallX < 0 {
 		s.BallX = 0
 		s.BallVX = math.Abs(s.BallVX)
+		e := s.ballEvent(EventWallBounce)
+		e.Wall = WallLeft
+		s.emit(e)
 	} else if s.BallX > float64(s.Width-1) {
 		s.BallX = float64(s.Width - 1)
 		s.BallVX = -math.Abs(s.BallVX)
+		e := s.ballEvent(EventWallBounce)
+		e.Wall = WallRight
+		s.emit(e)
 	}
 
 	// "Invisible ceiling" just above the bricks to shorten travel time.
 	if s.BallY < float64(s.TopWallY) {
 		s.BallY = float64(s.TopWallY)
 		s.BallVY = math.Abs(s.BallVY)
+		e := s.ballEvent(EventWallBounce)
+		e.Wall = WallTop
+		s.emit(e)
 	}
 
-	// Paddle collision (treat ball as point).
+	// Paddle collision (treat ball as point). A fast ball may cross the paddle
+	// line within one step, so check the path rather than the end point.
 	paddleTop := s.PaddleY - 0.5
-	if s.BallY >= paddleTop-0.2 && s.BallY <= paddleTop+0.2 &&
+	if s.BallY >= paddleTop-0.2 && prevY <= paddleTop+0.2 &&
 		s.BallX >= s.PaddleX && s.BallX <= s.PaddleX+s.PaddleW &&
 		s.BallVY > 0 {
 		rel := (s.BallX - (s.PaddleX + s.PaddleW/2.0)) / (s.PaddleW / 2.0) // -1..+1
+		// Bounce from the paddle line, not from wherever the step ended.
+		s.BallY = paddleTop
 		s.BallVY = -math.Abs(s.BallVY)
 		s.BallVX += rel * 12
 		// Clamp speed a bit.
@@ -173,61 +408,104 @@ func (s *State) Step(dt float64, in Input) {
 		} else if s.BallVX < -30 {
 			s.BallVX = -30
 		}
+		e := s.ballEvent(EventPaddleHit)
+		e.Offset = math.Max(-1, math.Min(rel, 1))
+		s.emit(e)
+		s.rampPaddleHit()
 	}
 
 	// Brick collision.
-	by := int(math.Floor(s.BallY))
-	br := by - s.TopOffset
-	if br >= 0 && br < len(s.Bricks) {
-		bx := int(math.Floor(s.BallX))
-		bc := bx / s.BrickW
-		if bc >= 0 && bc < len(s.Bricks[br]) && s.Bricks[br][bc] > 0 {
-			// Score: higher-intensity (higher HP) bricks are worth more.
-			// We add points per hit so hard bricks feel rewarding.
-			base := s.BrickMax[br][bc]
-			if base < 1 {
-				base = 1
+	if i := s.BrickAt(int(math.Floor(s.BallX)), int(math.Floor(s.BallY))); i >= 0 && s.Bricks[i].HP > 0 {
+		b := &s.Bricks[i]
+		b.HP--
+		s.emitBrick(EventBrickHit, i)
+		if b.HP == 0 {
+			s.BricksRemaining--
+			s.emitBrick(EventBrickDestroyed, i)
+			if b.Streak {
+				s.spawnDrop(i)
 			}
-			s.Score += 10 * base
-
-			s.Bricks[br][bc]--
-			if s.Bricks[br][bc] == 0 {
-				s.BricksRemaining--
-				if s.BricksRemaining <= 0 {
-					s.Cleared = true
-				}
-			}
-			// Bounce based on which side we entered from.
-			left := float64(bc * s.BrickW)
-			right := float64((bc + 1) * s.BrickW)
-			top := float64(s.TopOffset + br)
-			bottom := top + 1.0
-
-			const eps = 0.01
-			switch {
-			// Entered from left/right side.
-			case prevX < left && s.BallX >= left:
-				s.BallX = left - eps
-				s.BallVX = -math.Abs(s.BallVX)
-			case prevX >= right && s.BallX < right:
-				s.BallX = right + eps
-				s.BallVX = math.Abs(s.BallVX)
-			// Entered from top/bottom side.
-			case prevY < top && s.BallY >= top:
-				s.BallY = top - eps
-				s.BallVY = -math.Abs(s.BallVY)
-			case prevY >= bottom && s.BallY < bottom:
-				s.BallY = bottom + eps
-				s.BallVY = math.Abs(s.BallVY)
-			default:
-				// Fallback (corner cases): flip vertical.
-				s.BallVY = -s.BallVY
+			if s.BricksRemaining <= 0 {
+				s.Cleared = true
+				s.emit(s.ballEvent(EventCleared))
 			}
 		}
+		s.bounceOff(prevX, prevY, float64(b.X), float64(b.X+b.W), float64(b.Y), float64(b.Y+b.H))
 	}
+	s.rampBreach()
 
-	// Bottom: game over (missed the paddle).
-	if s.BallY > float64(s.Height) {
+	if s.Boss != nil {
+		s.stepBoss(dt, prevX, prevY)
+	}
+
+	// Bottom: lose a ball (missed the paddle).
+	if s.BallY > float64(s.Height) && !s.GameOver {
+		s.loseBall()
+	}
+
+	s.followBall()
+}
+
+// bounceOff bounces the ball off the rectangle [left, right) x [top, bottom) it
+// just entered from (prevX, prevY), based on which side it came through.
+func (s *State) bounceOff(prevX, prevY, left, right, top, bottom float64) {
+	const eps = 0.01
+	switch {
+	// Entered from left/right side.
+	case prevX < left && s.BallX >= left:
+		s.BallX = left - eps
+		s.BallVX = -math.Abs(s.BallVX)
+	case prevX >= right && s.BallX < right:
+		s.BallX = right + eps
+		s.BallVX = math.Abs(s.BallVX)
+	// Entered from top/bottom side.
+	case prevY < top && s.BallY >= top:
+		s.BallY = top - eps
+		s.BallVY = -math.Abs(s.BallVY)
+	case prevY >= bottom && s.BallY < bottom:
+		s.BallY = bottom + eps
+		s.BallVY = math.Abs(s.BallVY)
+	default:
+		// Fallback (corner cases): flip vertical.
+		s.BallVY = -s.BallVY
+	}
+}
+
+// accelPaddle updates PaddleVX for one step with direction move held.
+func (s *State) accelPaddle(dt float64, move int) {
+	dir := float64(move)
+	switch {
+	case move != 0 && s.PaddleVX*dir >= 0:
+		// Speeding up in the held direction.
+		s.PaddleVX += dir * s.PaddleAccel * dt
+	case s.PaddleVX != 0:
+		// Braking: nothing held, or reversing.
+		dv := s.PaddleDecel * dt
+		if math.Abs(s.PaddleVX) <= dv {
+			s.PaddleVX = 0
+		} else {
+			s.PaddleVX -= math.Copysign(dv, s.PaddleVX)
+		}
+	}
+	s.PaddleVX = math.Max(-s.PaddleMaxSpeed, math.Min(s.PaddleVX, s.PaddleMaxSpeed))
+}
+
+// loseBall spends a life and serves a fresh ball from the center, or ends the game
+// when no lives remain.
+func (s *State) loseBall() {
+	s.Lives = max(s.Lives-1, 0)
+	e := s.ballEvent(EventBallLost)
+	e.Lives = s.Lives
+	s.emit(e)
+	s.clearPowerUps()
+	s.Projectiles = s.Projectiles[:0]
+	if s.Lives == 0 {
 		s.GameOver = true
+		return
 	}
+	s.serves++
+	s.PaddleX = float64(s.Width)/2.0 - s.PaddleW/2.0
+	s.PaddleVX = 0
+	s.ResetBall(s.seed + s.serves)
+	s.Serving = s.manualServe
 }
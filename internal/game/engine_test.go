@@ -0,0 +1,295 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+// testGrid builds a rows x cols grid with every cell at the given HP.
+func testGrid(rows, cols, hp int) mapping.BrickGrid {
+	g := mapping.BrickGrid{Rows: rows, Cols: cols, MonthStarts: make([]bool, cols)}
+	for r := 0; r < rows; r++ {
+		row := make([]mapping.BrickCell, cols)
+		for c := range row {
+			row[c] = mapping.BrickCell{Count: hp, HP: hp}
+		}
+		g.Cells = append(g.Cells, row)
+	}
+	return g
+}
+
+func TestNewStateWithOptions_BrickGeometry(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(2, 4, 1)
+	grid.MonthStarts[2] = true
+
+	s := NewStateWithOptions(grid, 80, 30, 1, Options{BrickW: 3, BrickH: 2, MonthGutters: true})
+	if s.Width != 4*3+1 {
+		t.Fatalf("width should include one gutter: got %d", s.Width)
+	}
+	if got := s.ColX; got[0] != 0 || got[1] != 3 || got[2] != 7 || got[3] != 10 {
+		t.Fatalf("unexpected column offsets: %v", got)
+	}
+	if len(s.Bricks) != 8 || s.BricksTotal != 8 {
+		t.Fatalf("expected 8 bricks, got %d", len(s.Bricks))
+	}
+
+	// Row 1 spans two field rows below row 0.
+	y := s.TopOffset + 3
+	if i := s.BrickAt(8, y); i < 0 || s.Bricks[i].Row != 1 || s.Bricks[i].Col != 2 {
+		t.Fatalf("expected brick (1,2) at (8,%d), got index %d", y, i)
+	}
+	if i := s.BrickAt(6, y); i != -1 {
+		t.Fatalf("expected the gutter column to be empty, got index %d", i)
+	}
+}
+
+func TestFollowBall_KeepsThePaddleInView(t *testing.T) {
+	t.Parallel()
+
+	// A 60-column board in a 20-column view.
+	s := NewStateWithOptions(testGrid(1, 20, 1), 20, 30, 1, Options{BrickW: 3})
+	if s.ViewW >= s.Width {
+		t.Fatalf("the board should scroll: view %d, field %d", s.ViewW, s.Width)
+	}
+	inView := func() bool {
+		return s.PaddleX >= s.ViewX && s.PaddleX+s.PaddleW <= s.ViewX+float64(s.ViewW)
+	}
+	for _, tc := range []struct{ ball, paddle float64 }{
+		{ball: float64(s.Width) - 1, paddle: 0},
+		{ball: 0, paddle: float64(s.Width) - s.PaddleW},
+	} {
+		s.BallX, s.PaddleX = tc.ball, tc.paddle
+		s.followBall()
+		if !inView() {
+			t.Fatalf("ball at %.0f, paddle at %.0f: view [%.1f, %.1f) lost the paddle", tc.ball, tc.paddle, s.ViewX, s.ViewX+float64(s.ViewW))
+		}
+	}
+
+	// Near each other, the view still follows the ball.
+	s.BallX, s.PaddleX = float64(s.Width)/2, float64(s.Width)/2-s.PaddleW/2
+	s.followBall()
+	if !inView() || s.BallX < s.ViewX || s.BallX >= s.ViewX+float64(s.ViewW) {
+		t.Fatalf("both should be in view: view %.1f, ball %.1f, paddle %.1f", s.ViewX, s.BallX, s.PaddleX)
+	}
+}
+
+func TestRefit(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(2, 10, 2)
+	prev := NewStateWithOptions(grid, 40, 30, 1, Options{})
+	prev.Bricks[3].HP = 1
+	prev.BricksRemaining = 19
+	prev.Score, prev.Lives, prev.Stats.PlayTime = 40, 2, 12
+	_, bottom := prev.BrickBand()
+	prev.BallX, prev.BallY = 5.5, (float64(bottom)+prev.PaddleY)/2
+	prev.BallVX, prev.BallVY = 3, -9
+
+	// A shorter terminal moves the bricks up.
+	s := NewStateWithOptions(grid, 40, 12, 1, Options{})
+	if !s.Refit(&prev) {
+		t.Fatalf("the same grid should refit")
+	}
+	dy := s.TopOffset - prev.TopOffset
+	if dy >= 0 || s.Height != 12 || s.Width != prev.Width {
+		t.Fatalf("the field should get shorter, keeping its width: offset %d, %dx%d", dy, s.Width, s.Height)
+	}
+	b := s.Bricks[3]
+	if b.HP != 1 || b.Y != prev.Bricks[3].Y+dy || s.BrickAt(b.X, b.Y) != 3 || s.BricksRemaining != 19 {
+		t.Fatalf("the bricks should carry over to their new rows: %+v, %d left", b, s.BricksRemaining)
+	}
+	if s.Score != 40 || s.Lives != 2 || s.Stats.PlayTime != 12 || s.BallVX != 3 || s.BallVY != -9 || s.BallX != 5.5 {
+		t.Fatalf("the run and the ball should carry over: score %d, lives %d, ball %+v", s.Score, s.Lives, s)
+	}
+	_, bottom = s.BrickBand()
+	if want := (float64(bottom) + s.PaddleY) / 2; math.Abs(s.BallY-want) > 1e-9 {
+		t.Fatalf("the ball should keep its place between the bricks and the paddle: y %.2f, want %.2f", s.BallY, want)
+	}
+
+	// Another grid cannot continue the game.
+	other := NewStateWithOptions(testGrid(2, 8, 2), 40, 12, 1, Options{})
+	if other.Refit(&prev) || other.Width != 16 || other.Bricks[3].HP != 2 {
+		t.Fatalf("a different layout should not refit")
+	}
+}
+
+func TestStep_BallHitsBrickRectangle(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 3, 2), 40, 30, 1, Options{BrickW: 3})
+	top, bottom := s.BrickBand()
+
+	// Fire the ball straight up into the middle brick.
+	s.BallX = 4.5
+	s.BallY = float64(bottom) + 0.1
+	s.BallVX = 0
+	s.BallVY = -20
+	for range 10 {
+		s.Step(1.0/120.0, Input{})
+	}
+
+	mid := s.Bricks[s.BrickAt(4, top)]
+	if mid.HP != 1 {
+		t.Fatalf("expected the middle brick to take one hit, HP=%d", mid.HP)
+	}
+	if s.BallVY <= 0 {
+		t.Fatalf("expected the ball to bounce down, vy=%v", s.BallVY)
+	}
+	if s.Score != 10*2 {
+		t.Fatalf("expected score 20, got %d", s.Score)
+	}
+}
+
+func TestStep_PaddleChasesTarget(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 20, 1), 80, 30, 1)
+	const dt = 1.0 / 120.0
+	in := Input{Target: true, TargetX: 2}
+
+	// One step moves at most targetSpeed*dt.
+	before := s.PaddleX
+	s.Step(dt, in)
+	if got := before - s.PaddleX; got > targetSpeed*dt+1e-9 {
+		t.Fatalf("paddle moved %.3f in one step, cap is %.3f", got, targetSpeed*dt)
+	}
+
+	for range 120 {
+		s.Step(dt, in)
+	}
+	// The target is near the left wall, so the paddle ends clamped at 0.
+	if s.PaddleX != 0 {
+		t.Fatalf("expected paddle clamped at the left wall, got %.3f", s.PaddleX)
+	}
+
+	in.TargetX = 20
+	for range 120 {
+		s.Step(dt, in)
+	}
+	if center := s.PaddleX + s.PaddleW/2; center < 19.99 || center > 20.01 {
+		t.Fatalf("expected paddle centered on 20, got %.3f", center)
+	}
+}
+
+func TestStep_ManualServeWaitsForLaunch(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 20, 1), 80, 30, 1, Options{ManualServe: true, Lives: 2})
+	const dt = 1.0 / 120.0
+	for range 60 {
+		s.Step(dt, Input{Move: 1})
+	}
+	if !s.Serving {
+		t.Fatalf("ball should wait on the paddle until launched")
+	}
+	if want := s.PaddleX + s.PaddleW/2; s.BallX != want || s.BallY != s.PaddleY-1 {
+		t.Fatalf("ball should ride the paddle: ball (%.2f,%.2f) paddle center %.2f", s.BallX, s.BallY, want)
+	}
+
+	s.Step(dt, Input{Launch: true})
+	s.Step(dt, Input{})
+	if s.Serving || s.BallY >= s.PaddleY-1 {
+		t.Fatalf("ball should be in flight after launch: serving=%v y=%.2f", s.Serving, s.BallY)
+	}
+
+	// Losing a ball serves the next one manually again.
+	s.BallY = float64(s.Height) + 1
+	s.BallVY = 10
+	s.Step(dt, Input{})
+	if s.Lives != 1 || !s.Serving {
+		t.Fatalf("expected a manual serve after losing a ball: lives=%d serving=%v", s.Lives, s.Serving)
+	}
+}
+
+func TestStep_HeldMoveAcceleratesToMaxSpeed(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 60, 1), 200, 30, 1)
+	const dt = 1.0 / 120.0
+
+	// The first step only reaches accel*dt.
+	s.Step(dt, Input{Move: 1})
+	if want := s.PaddleAccel * dt; math.Abs(s.PaddleVX-want) > 1e-9 {
+		t.Fatalf("expected v=%.3f after one step, got %.3f", want, s.PaddleVX)
+	}
+
+	// Speed grows monotonically and saturates at PaddleMaxSpeed.
+	prev := s.PaddleVX
+	steps := 1
+	for s.PaddleVX < s.PaddleMaxSpeed && steps < 120 {
+		s.Step(dt, Input{Move: 1})
+		if s.PaddleVX < prev {
+			t.Fatalf("speed dropped while held: %.3f -> %.3f", prev, s.PaddleVX)
+		}
+		prev = s.PaddleVX
+		steps++
+	}
+	if s.PaddleVX != s.PaddleMaxSpeed {
+		t.Fatalf("expected max speed %.1f, got %.3f", s.PaddleMaxSpeed, s.PaddleVX)
+	}
+	if wantSteps := int(math.Ceil(s.PaddleMaxSpeed / (s.PaddleAccel * dt))); steps != wantSteps {
+		t.Fatalf("expected to reach max speed in %d steps, took %d", wantSteps, steps)
+	}
+	s.Step(dt, Input{Move: 1})
+	if s.PaddleVX != s.PaddleMaxSpeed {
+		t.Fatalf("speed must stay capped, got %.3f", s.PaddleVX)
+	}
+}
+
+func TestStep_ReleaseAndReverseBrake(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 60, 1), 200, 30, 1)
+	const dt = 1.0 / 120.0
+	s.PaddleVX = s.PaddleMaxSpeed
+
+	// Released: the paddle coasts to a stop without overshooting into reverse.
+	x0 := s.PaddleX
+	for range 120 {
+		s.Step(dt, Input{})
+		if s.PaddleVX < 0 {
+			t.Fatalf("braking must not reverse the paddle, got v=%.3f", s.PaddleVX)
+		}
+	}
+	if s.PaddleVX != 0 {
+		t.Fatalf("expected paddle to stop, got v=%.3f", s.PaddleVX)
+	}
+	// Stopping distance is v²/(2·decel), within one step of slack.
+	dist := s.PaddleX - x0
+	if want := s.PaddleMaxSpeed * s.PaddleMaxSpeed / (2 * s.PaddleDecel); math.Abs(dist-want) > s.PaddleMaxSpeed*dt {
+		t.Fatalf("expected stopping distance ~%.2f, got %.2f", want, dist)
+	}
+
+	// Reversing brakes at PaddleDecel before accelerating the other way.
+	s.PaddleVX = s.PaddleMaxSpeed
+	s.Step(dt, Input{Move: -1})
+	if want := s.PaddleMaxSpeed - s.PaddleDecel*dt; math.Abs(s.PaddleVX-want) > 1e-9 {
+		t.Fatalf("expected braking to v=%.3f, got %.3f", want, s.PaddleVX)
+	}
+	for range 120 {
+		s.Step(dt, Input{Move: -1})
+	}
+	if s.PaddleVX != -s.PaddleMaxSpeed && s.PaddleX != 0 {
+		t.Fatalf("expected full speed to the left, got v=%.3f", s.PaddleVX)
+	}
+}
+
+func TestStep_WallStopsPaddle(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 20, 1), 80, 30, 1)
+	const dt = 1.0 / 120.0
+	for range 240 {
+		s.Step(dt, Input{Move: 1})
+	}
+	if s.PaddleX+s.PaddleW != float64(s.Width) {
+		t.Fatalf("expected paddle against the right wall, got x=%.3f", s.PaddleX)
+	}
+	if s.PaddleVX != 0 {
+		t.Fatalf("wall should absorb paddle speed, got v=%.3f", s.PaddleVX)
+	}
+}
@@ -0,0 +1,124 @@
+package game
+
+// EventKind identifies what happened during a Step.
+type EventKind int
+
+const (
+	// EventBrickHit: the ball hit a brick (Brick, Row, Col, HPLeft, Count, First, Last).
+	EventBrickHit EventKind = iota + 1
+	// EventBrickDestroyed follows EventBrickHit when the brick's HP reached 0.
+	EventBrickDestroyed
+	// EventPaddleHit: the ball bounced off the paddle (Offset).
+	EventPaddleHit
+	// EventWallBounce: the ball bounced off a wall or the ceiling (Wall).
+	EventWallBounce
+	// EventBallLost: the ball fell past the paddle (Lives is what remains).
+	EventBallLost
+	// EventCleared: the last brick was destroyed.
+	EventCleared
+	// EventPowerUp: the paddle caught a power-up (Power; X, Y is the drop and
+	// Lives the balls left after it).
+	EventPowerUp
+	// EventBossHit: the ball hit the boss (HPLeft is the boss's HP after it and
+	// Count its MaxHP).
+	EventBossHit
+	// EventBossDefeated follows EventBossHit when the boss's HP reached 0; then
+	// EventCleared.
+	EventBossDefeated
+	// EventHazardHit: a projectile hit the paddle. EventBallLost follows.
+	EventHazardHit
+	// EventSpeedUp: the ball sped up (see SpeedRamp); Pace is its new pace.
+	EventSpeedUp
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventBrickHit:
+		return "BrickHit"
+	case EventBrickDestroyed:
+		return "BrickDestroyed"
+	case EventPaddleHit:
+		return "PaddleHit"
+	case EventWallBounce:
+		return "WallBounce"
+	case EventBallLost:
+		return "BallLost"
+	case EventCleared:
+		return "Cleared"
+	case EventPowerUp:
+		return "PowerUp"
+	case EventBossHit:
+		return "BossHit"
+	case EventBossDefeated:
+		return "BossDefeated"
+	case EventHazardHit:
+		return "HazardHit"
+	case EventSpeedUp:
+		return "SpeedUp"
+	default:
+		return "Event(?)"
+	}
+}
+
+// Wall identifies the wall of an EventWallBounce.
+type Wall int
+
+const (
+	WallLeft Wall = iota + 1
+	WallRight
+	WallTop
+)
+
+// Event is one thing that happened during a Step. It is a flat struct rather than
+// an interface so the event buffer can be reused without allocating; fields that
+// do not apply to Kind are zero (Brick is -1).
+type Event struct {
+	Kind EventKind
+	// X, Y is the ball position when the event happened.
+	X, Y float64
+
+	// Brick events: index into State.Bricks, grid cell, HP left after the hit and
+	// the brick's contributions (see Brick).
+	Brick       int
+	Row, Col    int
+	HPLeft      int
+	Count       int
+	First, Last string
+
+	// Offset is where the ball hit the paddle, from -1 (left edge) to +1 (right edge).
+	Offset float64
+	// Wall is the wall the ball bounced off.
+	Wall Wall
+	// Lives is the number of balls left after EventBallLost and EventPowerUp.
+	Lives int
+	// Power is the power-up of EventPowerUp.
+	Power PowerUp
+	// Pace is the ball's pace after EventSpeedUp (see State.Pace).
+	Pace float64
+}
+
+// Events returns what happened during the last Step, in order. The slice is
+// reused by the next Step, so callers must copy anything they keep.
+func (s *State) Events() []Event {
+	return s.events
+}
+
+func (s *State) emit(e Event) {
+	s.events = append(s.events, e)
+	s.Stats.record(e, s.Bricks)
+	s.rules.Event(s, e)
+}
+
+func (s *State) emitBrick(kind EventKind, i int) {
+	b := &s.Bricks[i]
+	s.emit(Event{
+		Kind: kind, X: s.BallX, Y: s.BallY,
+		Brick: i, Row: b.Row, Col: b.Col, HPLeft: b.HP,
+		Count: b.Count, First: b.First, Last: b.Last,
+	})
+}
+
+// ballEvent returns an event of kind at the ball position, for the caller to fill in.
+func (s *State) ballEvent(kind EventKind) Event {
+	return Event{Kind: kind, X: s.BallX, Y: s.BallY, Brick: -1}
+}
@@ -0,0 +1,147 @@
+package game
+
+import "testing"
+
+const stepDT = 1.0 / 120.0
+
+// stepUntil steps s until an event of kind is emitted (or n steps pass) and
+// returns that event.
+func stepUntil(t *testing.T, s *State, kind EventKind, n int) Event {
+	t.Helper()
+	for range n {
+		s.Step(stepDT, Input{})
+		for _, e := range s.Events() {
+			if e.Kind == kind {
+				return e
+			}
+		}
+	}
+	t.Fatalf("no %v event within %d steps", kind, n)
+	return Event{}
+}
+
+func TestEvents_BrickHitAndDestroyed(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(1, 3, 1)
+	grid.Cells[0][1].HP = 2
+	grid.Cells[0][1].Count = 23
+	grid.Cells[0][1].First, grid.Cells[0][1].Last = "2025-03-04", "2025-03-04"
+	s := NewStateWithOptions(grid, 40, 30, 1, Options{BrickW: 3})
+	_, bottom := s.BrickBand()
+
+	s.BallX, s.BallY = 4.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	hit := stepUntil(t, &s, EventBrickHit, 20)
+	if hit.Row != 0 || hit.Col != 1 || hit.HPLeft != 1 || hit.Count != 23 || hit.First != "2025-03-04" || hit.Last != "2025-03-04" {
+		t.Fatalf("unexpected hit event: %+v", hit)
+	}
+	for _, e := range s.Events() {
+		if e.Kind == EventBrickDestroyed {
+			t.Fatalf("a brick with HP left must not be destroyed: %+v", e)
+		}
+	}
+
+	// Second hit: BrickHit with 0 HP left, then BrickDestroyed in the same step.
+	s.BallX, s.BallY = 4.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	stepUntil(t, &s, EventBrickHit, 20)
+	ev := s.Events()
+	var kinds []EventKind
+	for _, e := range ev {
+		kinds = append(kinds, e.Kind)
+	}
+	if len(ev) < 2 || ev[0].Kind != EventBrickHit || ev[0].HPLeft != 0 || ev[1].Kind != EventBrickDestroyed || ev[1].Col != 1 {
+		t.Fatalf("expected BrickHit(0 left) then BrickDestroyed, got %v", kinds)
+	}
+}
+
+func TestEvents_ClearedAfterLastBrick(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 1, 1), 40, 30, 1, Options{BrickW: 3})
+	_, bottom := s.BrickBand()
+	s.BallX, s.BallY = 1.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+
+	stepUntil(t, &s, EventCleared, 20)
+	if !s.Cleared {
+		t.Fatalf("Cleared event without Cleared state")
+	}
+	s.Step(stepDT, Input{})
+	if len(s.Events()) != 0 {
+		t.Fatalf("a finished game must not emit events, got %+v", s.Events())
+	}
+}
+
+func TestEvents_PaddleHitOffset(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 20, 1), 80, 30, 1)
+	// Drop the ball onto the right quarter of the paddle.
+	s.BallX = s.PaddleX + s.PaddleW*0.75
+	s.BallY = s.PaddleY - 2
+	s.BallVX, s.BallVY = 0, 20
+
+	e := stepUntil(t, &s, EventPaddleHit, 30)
+	if e.Offset < 0.45 || e.Offset > 0.55 {
+		t.Fatalf("expected offset ~0.5, got %.3f", e.Offset)
+	}
+}
+
+func TestEvents_WallBounces(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		x, y   float64
+		vx, vy float64
+		want   Wall
+	}{
+		{"left", 0.5, 20, -20, 0, WallLeft},
+		{"right", 38.5, 20, 20, 0, WallRight},
+		{"top", 10, 7, 0, -20, WallTop},
+	} {
+		s := NewState(testGrid(1, 20, 1), 80, 30, 1)
+		// Keep bricks out of the way.
+		for i := range s.Bricks {
+			s.Bricks[i].HP = 0
+		}
+		s.BallX, s.BallY, s.BallVX, s.BallVY = tc.x, tc.y, tc.vx, tc.vy
+		if e := stepUntil(t, &s, EventWallBounce, 30); e.Wall != tc.want {
+			t.Fatalf("%s: expected wall %v, got %v", tc.name, tc.want, e.Wall)
+		}
+	}
+}
+
+func TestEvents_BallLost(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 20, 1), 80, 30, 1, Options{Lives: 2})
+	s.BallX = 1
+	s.PaddleX = 30
+	s.BallY, s.BallVX, s.BallVY = float64(s.Height)-0.5, 0, 20
+
+	if e := stepUntil(t, &s, EventBallLost, 30); e.Lives != 1 || s.GameOver {
+		t.Fatalf("expected one life left, got %+v (game over=%v)", e, s.GameOver)
+	}
+	s.BallX, s.PaddleX = 1, 30
+	s.BallY, s.BallVX, s.BallVY = float64(s.Height)-0.5, 0, 20
+	if e := stepUntil(t, &s, EventBallLost, 30); e.Lives != 0 || !s.GameOver {
+		t.Fatalf("expected game over, got %+v (game over=%v)", e, s.GameOver)
+	}
+}
+
+func TestEvents_BufferIsReused(t *testing.T) {
+	s := NewStateWithOptions(testGrid(1, 20, 1), 80, 30, 1, Options{Lives: 1000})
+	// Warm up so the buffer has grown.
+	for range 600 {
+		s.Step(stepDT, Input{Move: 1})
+	}
+	allocs := testing.AllocsPerRun(200, func() {
+		s.Step(stepDT, Input{Move: 1})
+	})
+	if allocs != 0 {
+		t.Fatalf("Step allocated %.1f times per call, want 0", allocs)
+	}
+}
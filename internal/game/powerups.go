@@ -0,0 +1,116 @@
+package game
+
+import "math"
+
+// PowerUp is what a streak brick (see Brick.Streak) breaks into. It falls as a
+// Drop and takes effect when the paddle catches it.
+type PowerUp int
+
+const (
+	// PowerWide widens the paddle for a while.
+	PowerWide PowerUp = iota + 1
+	// PowerSlow slows the ball down for a while.
+	PowerSlow
+	// PowerLife adds a ball.
+	PowerLife
+
+	numPowerUps = 3
+)
+
+func (p PowerUp) String() string {
+	switch p {
+	case PowerWide:
+		return "wide paddle"
+	case PowerSlow:
+		return "slow ball"
+	case PowerLife:
+		return "extra ball"
+	default:
+		return "PowerUp(?)"
+	}
+}
+
+// Drop is a power-up falling toward the paddle, in field cells.
+type Drop struct {
+	X, Y  float64
+	Power PowerUp
+}
+
+const (
+	// dropSpeed is how fast drops fall, in rows/s.
+	dropSpeed = 12.0
+
+	wideDuration = 12.0
+	wideFactor   = 1.5
+	slowDuration = 8.0
+	// slowFactor scales the ball's speed while PowerSlow lasts.
+	slowFactor = 0.6
+)
+
+// spawnDrop releases the power-up of destroyed streak brick i below it.
+func (s *State) spawnDrop(i int) {
+	b := &s.Bricks[i]
+	// Vary the power-up by brick, the same way for the same seed.
+	n := (s.seed + uint64(i)*0x9e3779b97f4a7c15) >> 32
+	s.Drops = append(s.Drops, Drop{
+		X:     float64(b.X) + float64(b.W)/2,
+		Y:     float64(b.Y + b.H),
+		Power: PowerUp(1 + n%numPowerUps),
+	})
+}
+
+// stepPowerUps moves the drops, applies the ones the paddle catches, and runs
+// down the timed power-ups.
+func (s *State) stepPowerUps(dt float64) {
+	paddleTop := s.PaddleY - 0.5
+	kept := s.Drops[:0]
+	for _, d := range s.Drops {
+		d.Y += dropSpeed * dt
+		switch {
+		case d.Y >= paddleTop && d.X >= s.PaddleX && d.X <= s.PaddleX+s.PaddleW:
+			s.applyPowerUp(d)
+		case d.Y <= float64(s.Height):
+			kept = append(kept, d)
+		}
+	}
+	s.Drops = kept
+
+	if s.WideLeft > 0 {
+		s.WideLeft = math.Max(s.WideLeft-dt, 0)
+		if s.WideLeft == 0 {
+			s.setPaddleW(s.basePaddleW)
+		}
+	}
+	s.SlowLeft = math.Max(s.SlowLeft-dt, 0)
+}
+
+func (s *State) applyPowerUp(d Drop) {
+	switch d.Power {
+	case PowerWide:
+		s.WideLeft = wideDuration
+		s.setPaddleW(math.Min(s.basePaddleW*wideFactor, float64(s.Width)))
+	case PowerSlow:
+		s.SlowLeft = slowDuration
+	case PowerLife:
+		s.Lives++
+	}
+	e := s.ballEvent(EventPowerUp)
+	e.X, e.Y = d.X, d.Y
+	e.Power = d.Power
+	e.Lives = s.Lives
+	s.emit(e)
+}
+
+// setPaddleW resizes the paddle around its center, keeping it on the field.
+func (s *State) setPaddleW(w float64) {
+	center := s.PaddleX + s.PaddleW/2
+	s.PaddleW = w
+	s.PaddleX = math.Max(0, math.Min(center-w/2, float64(s.Width)-w))
+}
+
+// clearPowerUps drops everything a lost ball forfeits.
+func (s *State) clearPowerUps() {
+	s.Drops = s.Drops[:0]
+	s.WideLeft, s.SlowLeft = 0, 0
+	s.PaddleW = s.basePaddleW
+}
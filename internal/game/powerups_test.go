@@ -0,0 +1,99 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// streakState returns a state whose middle brick is a streak brick.
+func streakState() State {
+	grid := testGrid(1, 3, 1)
+	grid.Cells[0][1].Streak = true
+	return NewStateWithOptions(grid, 40, 30, 1, Options{BrickW: 3, Lives: 3})
+}
+
+func TestPowerUps_StreakBrickDrops(t *testing.T) {
+	t.Parallel()
+
+	s := streakState()
+	_, bottom := s.BrickBand()
+	s.BallX, s.BallY = 0.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	stepUntil(t, &s, EventBrickDestroyed, 20)
+	if len(s.Drops) != 0 {
+		t.Fatalf("plain bricks should not drop power-ups")
+	}
+
+	s.BallX, s.BallY = 4.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	stepUntil(t, &s, EventBrickDestroyed, 20)
+	if len(s.Drops) != 1 || s.Drops[0].X != 4.5 {
+		t.Fatalf("expected one drop under the streak brick, got %+v", s.Drops)
+	}
+	if p := s.Drops[0].Power; p < PowerWide || p > PowerLife {
+		t.Fatalf("unexpected power-up %v", p)
+	}
+}
+
+func TestPowerUps_Catch(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []PowerUp{PowerWide, PowerSlow, PowerLife} {
+		s := streakState()
+		s.BallX, s.BallY, s.BallVX, s.BallVY = 1, 20, 0, -1 // out of the way
+		w, lives := s.PaddleW, s.Lives
+		center := s.PaddleX + w/2
+		s.Drops = append(s.Drops, Drop{X: center, Y: s.PaddleY - 3, Power: p})
+
+		e := stepUntil(t, &s, EventPowerUp, 60)
+		if e.Power != p || len(s.Drops) != 0 {
+			t.Fatalf("%v: expected the drop to be caught, got %+v", p, e)
+		}
+		switch p {
+		case PowerWide:
+			if math.Abs(s.PaddleW-w*wideFactor) > 1e-9 || math.Abs(s.PaddleX+s.PaddleW/2-center) > 1e-9 {
+				t.Fatalf("wide: paddle %.2f at %.2f, want %.2f centered", s.PaddleW, s.PaddleX, w*wideFactor)
+			}
+			for s.WideLeft > 0 {
+				s.Step(stepDT, Input{})
+			}
+			if s.PaddleW != w {
+				t.Fatalf("wide should wear off, paddle is %.2f", s.PaddleW)
+			}
+		case PowerSlow:
+			y := s.BallY
+			s.Step(stepDT, Input{})
+			if got := y - s.BallY; math.Abs(got-stepDT*slowFactor) > 1e-9 {
+				t.Fatalf("slow: ball moved %.4f, want %.4f", got, stepDT*slowFactor)
+			}
+		case PowerLife:
+			if s.Lives != lives+1 || e.Lives != lives+1 {
+				t.Fatalf("life: got %d lives", s.Lives)
+			}
+		}
+	}
+}
+
+func TestPowerUps_MissedAndLost(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 20, 1), 40, 30, 1, Options{Lives: 3})
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 1, 20, 0, -1
+	s.PaddleX = 30
+	s.Drops = append(s.Drops, Drop{X: 5, Y: s.PaddleY - 1, Power: PowerLife})
+	for range 60 {
+		s.Step(stepDT, Input{})
+	}
+	if len(s.Drops) != 0 || s.Lives != 3 {
+		t.Fatalf("a missed drop should fall off the field, got %+v", s.Drops)
+	}
+
+	s.applyPowerUp(Drop{Power: PowerWide})
+	s.Drops = append(s.Drops, Drop{X: 5, Y: 10, Power: PowerSlow})
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 1, float64(s.Height)-0.5, 0, 20
+	s.PaddleX = 30
+	stepUntil(t, &s, EventBallLost, 30)
+	if len(s.Drops) != 0 || s.WideLeft != 0 || s.PaddleW != s.basePaddleW {
+		t.Fatalf("losing a ball should forfeit power-ups")
+	}
+}
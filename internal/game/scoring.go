@@ -0,0 +1,195 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScoreKind labels where points came from, for the score breakdown.
+type ScoreKind int
+
+const (
+	// ScoreBricks: points for hitting bricks.
+	ScoreBricks ScoreKind = iota
+	// ScoreCombo: extra points for brick hits chained without touching the paddle.
+	ScoreCombo
+	// ScoreStreak: extra points for breaking consecutive contribution days.
+	ScoreStreak
+	// ScoreTime: bonus for clearing the board quickly.
+	ScoreTime
+	// ScoreLives: bonus for balls left when the board is cleared.
+	ScoreLives
+
+	numScoreKinds
+)
+
+var scoreKindNames = [numScoreKinds]string{"bricks", "combo", "streak", "time", "lives"}
+
+func (k ScoreKind) String() string {
+	if k < 0 || k >= numScoreKinds {
+		return fmt.Sprintf("ScoreKind(%d)", int(k))
+	}
+	return scoreKindNames[k]
+}
+
+// ScoreBreakdown holds points per ScoreKind; they add up to State.Score.
+type ScoreBreakdown [numScoreKinds]int
+
+// Rules decide how points are scored. The engine passes every event it emits to
+// its rules, in order and after the event took effect; rules add points with
+// State.Award. A Rules value belongs to one game (see Options.Rules).
+type Rules interface {
+	Event(s *State, e Event)
+}
+
+func newRules(f func() Rules) Rules {
+	if f == nil {
+		return NewStandardRules()
+	}
+	return f()
+}
+
+// Award adds pts of kind to the score.
+func (s *State) Award(kind ScoreKind, pts int) {
+	if pts == 0 || kind < 0 || kind >= numScoreKinds {
+		return
+	}
+	s.Score += pts
+	s.Breakdown[kind] += pts
+}
+
+// ClassicRules is the original scoring: 10 points per hit times the brick's
+// starting HP (10 per HP taken from a boss), and nothing else.
+type ClassicRules struct{}
+
+// NewClassicRules returns ClassicRules; it fits Options.Rules.
+func NewClassicRules() Rules { return ClassicRules{} }
+
+func (ClassicRules) Event(s *State, e Event) {
+	switch e.Kind {
+	case EventBrickHit:
+		s.Award(ScoreBricks, brickPoints(s.Bricks[e.Brick]))
+	case EventBossHit:
+		s.Award(ScoreBricks, bossPoints(s.Boss))
+	}
+}
+
+func brickPoints(b Brick) int {
+	return 10 * max(b.MaxHP, 1)
+}
+
+func bossPoints(b *Boss) int {
+	return 10 * b.Damage
+}
+
+const (
+	// Each brick hit in a combo (after the first) adds comboStep of its points,
+	// up to maxComboBonus times its points.
+	comboStep     = 0.25
+	maxComboBonus = 2.0
+	// streakPoints are awarded per other day in the run of broken consecutive
+	// days a destroyed brick joins.
+	streakPoints = 20
+	// streakBrickPoints are awarded for destroying a streak brick.
+	streakBrickPoints = 100
+	// The time bonus pays timeBonusRate points per second under par, where par
+	// is parPerBrick seconds per brick.
+	parPerBrick   = 1.5
+	bossPar       = 40.0
+	timeBonusRate = 10
+	lifeBonus     = 500
+)
+
+// StandardRules score like ClassicRules, plus:
+//   - combos: every brick hit without the ball touching the paddle in between
+//     is worth 25% more than the previous one, up to triple points;
+//   - streaks: destroying a brick next to already destroyed bricks on the
+//     calendar (the next or previous contribution day) pays 20 points per day
+//     in that run, so breaking real contribution streaks pays off, and 100
+//     more for a streak brick (see Brick.Streak);
+//   - a time bonus for clearing the board under par (1.5 s per brick, 40 s
+//     for a boss);
+//   - 500 points per ball left on clear.
+type StandardRules struct {
+	combo int
+	// next[i] and prev[i] are the bricks starting one day after and before
+	// brick i (-1 if none); linked lazily on the first destroyed brick.
+	next, prev []int
+	linked     bool
+}
+
+// NewStandardRules returns fresh StandardRules; it fits Options.Rules.
+func NewStandardRules() Rules { return &StandardRules{} }
+
+func (r *StandardRules) Event(s *State, e Event) {
+	switch e.Kind {
+	case EventBrickHit:
+		r.hit(s, brickPoints(s.Bricks[e.Brick]))
+	case EventBossHit:
+		r.hit(s, bossPoints(s.Boss))
+	case EventPaddleHit, EventBallLost:
+		r.combo = 0
+	case EventBrickDestroyed:
+		if run := r.streak(s, e.Brick); run > 1 {
+			s.Award(ScoreStreak, streakPoints*(run-1))
+		}
+		if s.Bricks[e.Brick].Streak {
+			s.Award(ScoreStreak, streakBrickPoints)
+		}
+	case EventCleared:
+		par := parPerBrick * float64(s.BricksTotal)
+		if s.Boss != nil {
+			par = bossPar
+		}
+		s.Award(ScoreTime, int(max(par-s.Stats.PlayTime, 0))*timeBonusRate)
+		s.Award(ScoreLives, lifeBonus*s.Lives)
+	}
+}
+
+// hit scores a hit worth pts, with the combo bonus.
+func (r *StandardRules) hit(s *State, pts int) {
+	s.Award(ScoreBricks, pts)
+	r.combo++
+	bonus := min(comboStep*float64(r.combo-1), maxComboBonus)
+	s.Award(ScoreCombo, int(float64(pts)*bonus))
+}
+
+// streak returns the number of destroyed bricks in the run of consecutive
+// contribution days through brick i.
+func (r *StandardRules) streak(s *State, i int) int {
+	if !r.linked {
+		r.link(s.Bricks)
+	}
+	run := 1
+	for j := r.prev[i]; j >= 0 && s.Bricks[j].HP == 0; j = r.prev[j] {
+		run++
+	}
+	for j := r.next[i]; j >= 0 && s.Bricks[j].HP == 0; j = r.next[j] {
+		run++
+	}
+	return run
+}
+
+// link connects bricks by their first day. Compressed bricks cover several
+// weeks; they link through their first week, which keeps weekday runs intact.
+func (r *StandardRules) link(bricks []Brick) {
+	r.linked = true
+	r.next = make([]int, len(bricks))
+	r.prev = make([]int, len(bricks))
+	byDay := make(map[string]int, len(bricks))
+	for i := range bricks {
+		r.next[i], r.prev[i] = -1, -1
+		if bricks[i].First != "" {
+			byDay[bricks[i].First] = i
+		}
+	}
+	for i := range bricks {
+		d, err := time.Parse(time.DateOnly, bricks[i].First)
+		if err != nil {
+			continue
+		}
+		if j, ok := byDay[d.AddDate(0, 0, 1).Format(time.DateOnly)]; ok {
+			r.next[i], r.prev[j] = j, i
+		}
+	}
+}
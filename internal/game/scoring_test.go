@@ -0,0 +1,108 @@
+package game
+
+import "testing"
+
+// scoringState returns a state with one brick per day, HP 2, for feeding
+// events to rules directly.
+func scoringState(days ...string) State {
+	grid := testGrid(1, len(days), 2)
+	for c, d := range days {
+		grid.Cells[0][c].First, grid.Cells[0][c].Last = d, d
+	}
+	return NewStateWithOptions(grid, 40, 30, 1, Options{Rules: NewClassicRules})
+}
+
+func TestStandardRules_Combo(t *testing.T) {
+	t.Parallel()
+
+	s := scoringState("2025-03-01")
+	r := NewStandardRules()
+	hit := Event{Kind: EventBrickHit, Brick: 0}
+	for range 4 {
+		r.Event(&s, hit)
+	}
+	// 20 points per hit, then +25% per chained hit: 0 + 5 + 10 + 15.
+	if s.Breakdown[ScoreBricks] != 80 || s.Breakdown[ScoreCombo] != 30 {
+		t.Fatalf("unexpected breakdown %v", s.Breakdown)
+	}
+
+	r.Event(&s, Event{Kind: EventPaddleHit, Brick: -1})
+	r.Event(&s, hit)
+	if s.Breakdown[ScoreCombo] != 30 {
+		t.Fatalf("the paddle should end the combo, got %v", s.Breakdown)
+	}
+
+	// The bonus is capped at triple points.
+	for range 20 {
+		r.Event(&s, hit)
+	}
+	before := s.Breakdown[ScoreCombo]
+	r.Event(&s, hit)
+	if got := s.Breakdown[ScoreCombo] - before; got != 40 {
+		t.Fatalf("expected the capped bonus of 40, got %d", got)
+	}
+	if s.Score != s.Breakdown[ScoreBricks]+s.Breakdown[ScoreCombo] {
+		t.Fatalf("score %d should add up the breakdown %v", s.Score, s.Breakdown)
+	}
+}
+
+func TestStandardRules_Streak(t *testing.T) {
+	t.Parallel()
+
+	s := scoringState("2025-03-01", "2025-03-02", "2025-03-03", "2025-03-05")
+	r := NewStandardRules()
+	destroy := func(i int) int {
+		before := s.Breakdown[ScoreStreak]
+		s.Bricks[i].HP = 0
+		r.Event(&s, Event{Kind: EventBrickDestroyed, Brick: i})
+		return s.Breakdown[ScoreStreak] - before
+	}
+
+	if got := destroy(0); got != 0 {
+		t.Fatalf("a lone day is no streak, got %d", got)
+	}
+	if got := destroy(2); got != 0 {
+		t.Fatalf("03-01 and 03-03 are not consecutive, got %d", got)
+	}
+	if got := destroy(1); got != 2*streakPoints {
+		t.Fatalf("closing 03-01..03-03 should pay for two more days, got %d", got)
+	}
+	if got := destroy(3); got != 0 {
+		t.Fatalf("03-04 has no brick, so 03-05 stands alone, got %d", got)
+	}
+}
+
+func TestStandardRules_ClearBonuses(t *testing.T) {
+	t.Parallel()
+
+	s := scoringState("2025-03-01")
+	s.BricksTotal, s.Lives, s.Stats.PlayTime = 20, 2, 10
+	NewStandardRules().Event(&s, Event{Kind: EventCleared, Brick: -1})
+	// Par is 30 s: 20 s to spare.
+	if s.Breakdown[ScoreTime] != 20*timeBonusRate || s.Breakdown[ScoreLives] != 2*lifeBonus {
+		t.Fatalf("unexpected clear bonuses %v", s.Breakdown)
+	}
+
+	slow := scoringState("2025-03-01")
+	slow.BricksTotal, slow.Lives, slow.Stats.PlayTime = 20, 1, 90
+	NewStandardRules().Event(&slow, Event{Kind: EventCleared, Brick: -1})
+	if slow.Breakdown[ScoreTime] != 0 {
+		t.Fatalf("no time bonus over par, got %v", slow.Breakdown)
+	}
+}
+
+func TestOptionsRules_Classic(t *testing.T) {
+	t.Parallel()
+
+	s := scoringState("2025-03-01", "2025-03-02", "2025-03-03")
+	_, bottom := s.BrickBand()
+	for range 2 {
+		s.BallX, s.BallY = 2.5, float64(bottom)+0.1
+		s.BallVX, s.BallVY = 0, -20
+		stepUntil(t, &s, EventBrickHit, 20)
+	}
+	want := ScoreBreakdown{ScoreBricks: 40}
+	if s.Score != 40 || s.Breakdown != want {
+		t.Fatalf("classic rules score hits only, got %d %v", s.Score, s.Breakdown)
+	}
+}
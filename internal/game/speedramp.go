@@ -0,0 +1,149 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SpeedRamp speeds the ball up while a rally lasts. Each step adds Step times
+// the serve speed, up to a cap; a new ball starts over at the serve speed. The
+// zero value never steps up.
+type SpeedRamp struct {
+	// EveryHits steps up every EveryHits paddle hits (0 = never).
+	EveryHits int
+	// Breach steps up when a ball first reaches the deepest brick row.
+	Breach bool
+	// Step is the speed-up per step. 0 means defaultRampStep.
+	Step float64
+	// Max caps Pace. 0 means the difficulty's MaxPace.
+	Max float64
+}
+
+const defaultRampStep = 0.1
+
+// DefaultSpeedRamp is what ParseSpeedRamp returns for an empty spec. It is
+// off, so the presets play as they did before ramps existed.
+var DefaultSpeedRamp = SpeedRamp{}
+
+// ParseSpeedRamp parses a ramp spec: "off", or a comma-separated list of
+// "hits=N", "breach", "step=F" and "max=F", e.g. "hits=5,breach,max=2". An
+// empty spec selects DefaultSpeedRamp.
+func ParseSpeedRamp(spec string) (SpeedRamp, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "":
+		return DefaultSpeedRamp, nil
+	case "off":
+		return SpeedRamp{}, nil
+	}
+
+	var r SpeedRamp
+	for _, part := range strings.Split(spec, ",") {
+		key, val, hasVal := strings.Cut(strings.TrimSpace(part), "=")
+		switch {
+		case key == "breach" && !hasVal:
+			r.Breach = true
+		case key == "hits" && hasVal:
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				return SpeedRamp{}, fmt.Errorf("hits must be a non-negative integer: %q", val)
+			}
+			r.EveryHits = n
+		case (key == "step" || key == "max") && hasVal:
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil || f <= 0 || (key == "max" && f < 1) {
+				return SpeedRamp{}, fmt.Errorf("%s must be a number > 0 (max >= 1): %q", key, val)
+			}
+			if key == "step" {
+				r.Step = f
+			} else {
+				r.Max = f
+			}
+		default:
+			return SpeedRamp{}, fmt.Errorf("unknown speed ramp setting %q (expected off, hits=N, breach, step=F or max=F)", part)
+		}
+	}
+	return r, nil
+}
+
+// String returns r as a spec for ParseSpeedRamp.
+func (r SpeedRamp) String() string {
+	var parts []string
+	if r.EveryHits > 0 {
+		parts = append(parts, "hits="+strconv.Itoa(r.EveryHits))
+	}
+	if r.Breach {
+		parts = append(parts, "breach")
+	}
+	if len(parts) == 0 {
+		return "off"
+	}
+	if r.Step > 0 {
+		parts = append(parts, "step="+strconv.FormatFloat(r.Step, 'g', -1, 64))
+	}
+	if r.Max > 0 {
+		parts = append(parts, "max="+strconv.FormatFloat(r.Max, 'g', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r SpeedRamp) step() float64 {
+	if r.Step <= 0 {
+		return defaultRampStep
+	}
+	return r.Step
+}
+
+// Pace is how many times faster than its serve the ball currently moves: the
+// difficulty's ramp over time plus the SpeedRamp steps, capped.
+func (s *State) Pace() float64 {
+	p := 1 + s.Difficulty.Ramp*s.inPlay/60 + float64(s.rampSteps)*s.speedRamp.step()
+	return math.Min(p, s.maxPace())
+}
+
+func (s *State) maxPace() float64 {
+	if s.speedRamp.Max > 0 {
+		return s.speedRamp.Max
+	}
+	return math.Max(s.Difficulty.MaxPace, 1)
+}
+
+// rampPaddleHit counts a paddle hit toward the next SpeedRamp step.
+func (s *State) rampPaddleHit() {
+	s.rallyHits++
+	if n := s.speedRamp.EveryHits; n > 0 && s.rallyHits%n == 0 {
+		s.speedUp()
+	}
+}
+
+// rampBreach steps up the first time the ball reaches the deepest (top) brick
+// row. The boss stage has no rows to breach.
+func (s *State) rampBreach() {
+	if !s.speedRamp.Breach || s.breached || s.Boss != nil || s.BrickRows == 0 {
+		return
+	}
+	if s.BallY < float64(s.TopOffset+s.BrickH) {
+		s.breached = true
+		s.speedUp()
+	}
+}
+
+// speedUp adds a SpeedRamp step unless the ball is already at its top speed.
+func (s *State) speedUp() {
+	if s.Pace() >= s.maxPace() {
+		return
+	}
+	s.rampSteps++
+	e := s.ballEvent(EventSpeedUp)
+	e.Pace = s.Pace()
+	s.emit(e)
+}
+
+// resetRamp returns a new ball to its serve speed.
+func (s *State) resetRamp() {
+	s.inPlay = 0
+	s.rampSteps, s.rallyHits = 0, 0
+	s.breached = false
+}
@@ -0,0 +1,169 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSpeedRamp(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		spec string
+		want SpeedRamp
+		str  string
+	}{
+		{"", DefaultSpeedRamp, "off"},
+		{"off", SpeedRamp{}, "off"},
+		{"hits=5", SpeedRamp{EveryHits: 5}, "hits=5"},
+		{" Breach, max=2 ", SpeedRamp{Breach: true, Max: 2}, "breach,max=2"},
+		{"hits=3,step=0.25,max=1.5", SpeedRamp{EveryHits: 3, Step: 0.25, Max: 1.5}, "hits=3,step=0.25,max=1.5"},
+	}
+	for _, tc := range cases {
+		got, err := ParseSpeedRamp(tc.spec)
+		if err != nil || got != tc.want {
+			t.Fatalf("%q: got %+v, %v; want %+v", tc.spec, got, err, tc.want)
+		}
+		if got.String() != tc.str {
+			t.Fatalf("%q: String() = %q, want %q", tc.spec, got.String(), tc.str)
+		}
+	}
+	for _, spec := range []string{"fast", "hits", "hits=-1", "step=0", "max=0.5", "breach=1"} {
+		if _, err := ParseSpeedRamp(spec); err == nil {
+			t.Fatalf("%q: expected an error", spec)
+		}
+	}
+}
+
+// rampState returns a state with one brick row and the ball heading for the
+// paddle from just above it.
+func rampState(r SpeedRamp) State {
+	s := NewStateWithOptions(testGrid(1, 20, 1), 40, 30, 1, Options{Lives: 2, SpeedRamp: r})
+	s.BallX, s.BallY, s.BallVX, s.BallVY = s.PaddleX+s.PaddleW/2, s.PaddleY-2, 0, 10
+	return s
+}
+
+func TestSpeedRamp_EveryHits(t *testing.T) {
+	t.Parallel()
+
+	s := rampState(SpeedRamp{EveryHits: 2, Max: 1.25})
+	for hit := 1; hit <= 6; hit++ {
+		s.BallY, s.BallVY = s.PaddleY-2, 10
+		stepUntil(t, &s, EventPaddleHit, 60)
+		want := 1 + 0.1*float64(hit/2)
+		if hit >= 6 {
+			want = 1.25 // capped
+		}
+		if math.Abs(s.Pace()-want) > 1e-9 {
+			t.Fatalf("after %d hits pace = %.2f, want %.2f", hit, s.Pace(), want)
+		}
+	}
+
+	// A lost ball starts over.
+	s.BallY, s.BallVY = float64(s.Height)+1, 10
+	stepUntil(t, &s, EventBallLost, 1)
+	if s.Pace() != 1 {
+		t.Fatalf("pace should reset with a new ball, got %.2f", s.Pace())
+	}
+}
+
+func TestSpeedRamp_EventAndCap(t *testing.T) {
+	t.Parallel()
+
+	s := rampState(SpeedRamp{EveryHits: 1, Step: 0.3})
+	e := stepUntil(t, &s, EventSpeedUp, 60)
+	if math.Abs(e.Pace-1.3) > 1e-9 {
+		t.Fatalf("expected pace 1.3, got %+v", e)
+	}
+	// The next step would pass the difficulty's cap (1.5 for normal): the pace
+	// stops there, and no further steps are announced.
+	for range 2 {
+		s.BallY, s.BallVY = s.PaddleY-2, 10
+		stepUntil(t, &s, EventPaddleHit, 60)
+	}
+	if s.Pace() != DefaultDifficulty.MaxPace {
+		t.Fatalf("pace should stop at %.2f, got %.2f", DefaultDifficulty.MaxPace, s.Pace())
+	}
+	s.BallY, s.BallVY = s.PaddleY-2, 10
+	for range 60 {
+		s.Step(stepDT, Input{})
+		for _, e := range s.Events() {
+			if e.Kind == EventSpeedUp {
+				t.Fatalf("no speed-up expected at top speed, got %+v", e)
+			}
+		}
+	}
+}
+
+func TestSpeedRamp_Breach(t *testing.T) {
+	t.Parallel()
+
+	s := rampState(SpeedRamp{Breach: true})
+	s.Bricks[3].HP = 0 // a gap to slip through
+	s.BricksRemaining--
+	s.indexBricks()
+	s.BallX, s.BallY, s.BallVX, s.BallVY = 6.5, float64(s.TopOffset+3), 0, -10
+	e := stepUntil(t, &s, EventSpeedUp, 60)
+	if e.Y >= float64(s.TopOffset+s.BrickH) || math.Abs(s.Pace()-1.1) > 1e-9 {
+		t.Fatalf("expected a speed-up on reaching the top row, got %+v (pace %.2f)", e, s.Pace())
+	}
+	// Only the first breach of each ball counts.
+	s.BallX, s.BallY, s.BallVY = 6.5, float64(s.TopOffset+3), -10
+	for range 60 {
+		s.Step(stepDT, Input{})
+		for _, e := range s.Events() {
+			if e.Kind == EventSpeedUp {
+				t.Fatalf("a second breach should not speed up, got %+v", e)
+			}
+		}
+	}
+}
+
+func TestStep_FastBallHitsPaddle(t *testing.T) {
+	t.Parallel()
+
+	s := NewState(testGrid(1, 20, 1), 40, 30, 1)
+	paddleTop := s.PaddleY - 0.5
+	// Fast enough to jump over the paddle line in one step.
+	s.BallX, s.BallY, s.BallVX, s.BallVY = s.PaddleX+s.PaddleW/2, paddleTop-0.25, 0, 0.6/stepDT
+	e := stepUntil(t, &s, EventPaddleHit, 1)
+	if e.Y != paddleTop || s.BallVY >= 0 {
+		t.Fatalf("expected the crossing ball to bounce from the paddle line, got %+v", e)
+	}
+}
+
+func TestSpeedRamp_FastBallBouncesFromThePaddleLine(t *testing.T) {
+	t.Parallel()
+
+	insane, err := ParseDifficulty("insane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStateWithOptions(testGrid(1, 20, 1), 40, 30, 1, Options{Lives: 2, Difficulty: insane, SpeedRamp: SpeedRamp{EveryHits: 1, Step: 1}})
+	s.rampSteps = 10 // capped at the top speed
+	if s.Pace() != insane.MaxPace {
+		t.Fatalf("expected max pace %.2f, got %.2f", insane.MaxPace, s.Pace())
+	}
+	// A step at this speed covers most of a cell, so it ends past the paddle line.
+	paddleTop := s.PaddleY - 0.5
+	s.BallX, s.BallY, s.BallVX, s.BallVY = s.PaddleX+s.PaddleW/2, paddleTop-0.15, 0, 50
+
+	hits := 0
+	for i := range 5 {
+		s.Step(stepDT, Input{})
+		for _, e := range s.Events() {
+			if e.Kind == EventPaddleHit {
+				hits++
+				if s.BallY > paddleTop {
+					t.Fatalf("the ball should leave from the paddle line, got y=%.2f (line %.2f)", s.BallY, paddleTop)
+				}
+			}
+		}
+		if i == 0 && hits != 1 {
+			t.Fatalf("expected the ball to bounce in the first step")
+		}
+	}
+	if hits != 1 || s.BallVY >= 0 || s.Lives != 2 {
+		t.Fatalf("expected exactly one bounce and no lost ball, got %d hits, vy %.2f, %d lives", hits, s.BallVY, s.Lives)
+	}
+}
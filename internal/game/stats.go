@@ -0,0 +1,86 @@
+package game
+
+import "github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+
+// Stats summarizes a game so far. The engine updates them from its own events
+// (see State.emit), so they always agree with what happened on the field.
+type Stats struct {
+	// PlayTime is the simulated time with a ball in play, in seconds, which
+	// the speed setting scales; it sets the time bonus. Time spent waiting for
+	// a serve does not count.
+	PlayTime float64
+
+	PaddleHits int
+	// LongestRally is the most paddle hits without losing a ball.
+	LongestRally int
+	BallsLost    int
+	BrickHits    int
+
+	// Destroyed counts destroyed bricks by their starting HP (1..mapping.MaxHP).
+	Destroyed [mapping.MaxHP + 1]int
+	// ContributionsDestroyed and ContributionsTotal sum Brick.Count over the
+	// destroyed bricks and over all bricks.
+	ContributionsDestroyed int
+	ContributionsTotal     int
+	// Busiest is the index into State.Bricks of the destroyed brick with the
+	// highest Count (the earliest on ties), or -1.
+	Busiest int
+
+	rally int
+}
+
+func newStats(bricks []Brick) Stats {
+	st := Stats{Busiest: -1}
+	for i := range bricks {
+		st.ContributionsTotal += bricks[i].Count
+	}
+	return st
+}
+
+// Accuracy is the share of balls reaching the paddle that were returned, from 0
+// to 1 (0 before the first catch or miss).
+func (st *Stats) Accuracy() float64 {
+	n := st.PaddleHits + st.BallsLost
+	if n == 0 {
+		return 0
+	}
+	return float64(st.PaddleHits) / float64(n)
+}
+
+// DestroyedShare is ContributionsDestroyed over ContributionsTotal, from 0 to 1.
+func (st *Stats) DestroyedShare() float64 {
+	if st.ContributionsTotal == 0 {
+		return 0
+	}
+	return float64(st.ContributionsDestroyed) / float64(st.ContributionsTotal)
+}
+
+// TotalDestroyed is the number of destroyed bricks.
+func (st *Stats) TotalDestroyed() int {
+	n := 0
+	for _, d := range st.Destroyed {
+		n += d
+	}
+	return n
+}
+
+func (st *Stats) record(e Event, bricks []Brick) {
+	switch e.Kind {
+	case EventPaddleHit:
+		st.PaddleHits++
+		st.rally++
+		st.LongestRally = max(st.LongestRally, st.rally)
+	case EventBallLost:
+		st.BallsLost++
+		st.rally = 0
+	case EventBrickHit:
+		st.BrickHits++
+	case EventBrickDestroyed:
+		b := &bricks[e.Brick]
+		st.Destroyed[min(max(b.MaxHP, 0), mapping.MaxHP)]++
+		st.ContributionsDestroyed += b.Count
+		if st.Busiest < 0 || b.Count > bricks[st.Busiest].Count {
+			st.Busiest = e.Brick
+		}
+	}
+}
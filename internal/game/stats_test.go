@@ -0,0 +1,91 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStats_BricksAndContributions(t *testing.T) {
+	t.Parallel()
+
+	// Counts 1, 9, 2 with HP 1, 3, 1.
+	grid := testGrid(1, 3, 1)
+	grid.Cells[0][1].Count, grid.Cells[0][1].HP = 9, 3
+	grid.Cells[0][2].Count = 2
+	s := NewStateWithOptions(grid, 40, 30, 1, Options{BrickW: 3})
+	if s.Stats.ContributionsTotal != 12 || s.Stats.Busiest != -1 {
+		t.Fatalf("unexpected initial stats: %+v", s.Stats)
+	}
+	_, bottom := s.BrickBand()
+
+	// Break the middle brick: three hits.
+	for range 3 {
+		s.BallX, s.BallY = 4.5, float64(bottom)+0.1
+		s.BallVX, s.BallVY = 0, -20
+		stepUntil(t, &s, EventBrickHit, 20)
+	}
+	// And the right one.
+	s.BallX, s.BallY = 7.5, float64(bottom)+0.1
+	s.BallVX, s.BallVY = 0, -20
+	stepUntil(t, &s, EventBrickDestroyed, 20)
+
+	st := s.Stats
+	if st.BrickHits != 4 || st.Destroyed[3] != 1 || st.Destroyed[1] != 1 || st.TotalDestroyed() != 2 {
+		t.Fatalf("unexpected brick stats: %+v", st)
+	}
+	if st.ContributionsDestroyed != 11 || math.Abs(st.DestroyedShare()-11.0/12.0) > 1e-9 {
+		t.Fatalf("expected 11 of 12 contributions destroyed, got %d (%.3f)", st.ContributionsDestroyed, st.DestroyedShare())
+	}
+	if st.Busiest < 0 || s.Bricks[st.Busiest].Count != 9 {
+		t.Fatalf("busiest should be the 9-contribution brick, got %d", st.Busiest)
+	}
+}
+
+func TestStats_RalliesAndAccuracy(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 20, 1), 80, 30, 1, Options{Lives: 3})
+	catch := func() {
+		s.BallX = s.PaddleX + s.PaddleW/2
+		s.BallY, s.BallVX, s.BallVY = s.PaddleY-2, 0, 20
+		stepUntil(t, &s, EventPaddleHit, 30)
+	}
+	miss := func() {
+		s.BallX, s.PaddleX = 1, 30
+		s.BallY, s.BallVX, s.BallVY = float64(s.Height)-0.5, 0, 20
+		stepUntil(t, &s, EventBallLost, 30)
+	}
+
+	catch()
+	catch()
+	catch()
+	miss()
+	catch()
+	st := s.Stats
+	if st.PaddleHits != 4 || st.BallsLost != 1 || st.LongestRally != 3 {
+		t.Fatalf("unexpected rally stats: %+v", st)
+	}
+	if got := st.Accuracy(); math.Abs(got-0.8) > 1e-9 {
+		t.Fatalf("expected 80%% accuracy, got %.3f", got)
+	}
+	if st.PlayTime <= 0 {
+		t.Fatalf("play time should advance")
+	}
+}
+
+func TestStats_ServingIsNotPlayTime(t *testing.T) {
+	t.Parallel()
+
+	s := NewStateWithOptions(testGrid(1, 20, 1), 80, 30, 1, Options{ManualServe: true})
+	for range 120 {
+		s.Step(stepDT, Input{})
+	}
+	if s.Stats.PlayTime != 0 {
+		t.Fatalf("waiting for a serve should not count, got %.3f", s.Stats.PlayTime)
+	}
+	s.Step(stepDT, Input{Launch: true})
+	s.Step(stepDT, Input{})
+	if s.Stats.PlayTime == 0 {
+		t.Fatalf("play time should start after the launch")
+	}
+}
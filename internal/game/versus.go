@@ -0,0 +1,25 @@
+package game
+
+// VersusResult decides a two-player match between boards a and b. The first
+// board to clear wins; once time is up or both are out of balls, the higher
+// score wins. It returns the winning board (0 or 1, -1 for a draw) and whether
+// the match is over.
+func VersusResult(a, b *State, timeUp bool) (winner int, over bool) {
+	switch {
+	case a.Cleared && !b.Cleared:
+		return 0, true
+	case b.Cleared && !a.Cleared:
+		return 1, true
+	case a.Cleared, timeUp, a.GameOver && b.GameOver:
+		// Both cleared in the same step, or nobody did.
+		switch {
+		case a.Score > b.Score:
+			return 0, true
+		case b.Score > a.Score:
+			return 1, true
+		default:
+			return -1, true
+		}
+	}
+	return -1, false
+}
@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestVersusResult(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		a, b   State
+		timeUp bool
+		winner int
+		over   bool
+	}{
+		{name: "playing", winner: -1},
+		{name: "first to clear", a: State{Score: 10}, b: State{Score: 500, Cleared: true}, winner: 1, over: true},
+		{name: "clear beats out of balls", a: State{Cleared: true}, b: State{GameOver: true, Score: 9}, winner: 0, over: true},
+		{name: "one out of balls", a: State{GameOver: true, Score: 50}, winner: -1},
+		{name: "both out of balls", a: State{GameOver: true, Score: 50}, b: State{GameOver: true, Score: 40}, winner: 0, over: true},
+		{name: "time up", a: State{Score: 50}, b: State{Score: 60}, timeUp: true, winner: 1, over: true},
+		{name: "time up tie", a: State{Score: 50}, b: State{Score: 50}, timeUp: true, winner: -1, over: true},
+		{name: "cleared together", a: State{Cleared: true, Score: 70}, b: State{Cleared: true, Score: 60}, winner: 0, over: true},
+	}
+	for _, tc := range cases {
+		winner, over := VersusResult(&tc.a, &tc.b, tc.timeUp)
+		if winner != tc.winner || over != tc.over {
+			t.Fatalf("%s: got winner %d, over %v; want %d, %v", tc.name, winner, over, tc.winner, tc.over)
+		}
+	}
+}
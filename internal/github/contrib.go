@@ -81,7 +81,7 @@ func graphqlRequest(ctx context.Context, query string, variables map[string]any,
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/graphql", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", graphqlEndpoint(HostFromContext(ctx)), bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -129,8 +129,16 @@ func useTokenClient() bool {
 	return os.Getenv("GITHUB_TOKEN") != "" || os.Getenv("GH_TOKEN") != ""
 }
 
+// ghClient returns a gh-authenticated GraphQL client for the host in ctx.
+func ghClient(ctx context.Context) (*api.GraphQLClient, error) {
+	if host := HostFromContext(ctx); host != "" {
+		return api.NewGraphQLClient(api.ClientOptions{Host: host})
+	}
+	return api.DefaultGraphQLClient()
+}
+
 func fetchViewerContributionCalendarRangeWithGh(ctx context.Context, from, to time.Time) (string, Calendar, error) {
-	client, err := api.DefaultGraphQLClient()
+	client, err := ghClient(ctx)
 	if err != nil {
 		return "", Calendar{}, err
 	}
@@ -213,7 +221,7 @@ query($from: DateTime!, $to: DateTime!) {
 }
 
 func fetchUserContributionCalendarRangeWithGh(ctx context.Context, login string, from, to time.Time) (string, Calendar, error) {
-	client, err := api.DefaultGraphQLClient()
+	client, err := ghClient(ctx)
 	if err != nil {
 		return "", Calendar{}, err
 	}
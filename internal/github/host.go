@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultHost is the GitHub host used when none is configured.
+const DefaultHost = "github.com"
+
+type hostKey struct{}
+
+// WithHost returns a context that directs fetches to the given GitHub host
+// (e.g. a GitHub Enterprise Server hostname). An empty host means github.com.
+//
+// The host travels via context so the Fetch* signatures stay stable.
+func WithHost(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, hostKey{}, normalizeHost(host))
+}
+
+// HostFromContext returns the host set by WithHost, or "" for the default host.
+func HostFromContext(ctx context.Context) string {
+	h, _ := ctx.Value(hostKey{}).(string)
+	return h
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == DefaultHost || host == "api.github.com" {
+		return ""
+	}
+	return host
+}
+
+// graphqlEndpoint returns the GraphQL endpoint for host ("" = github.com).
+// GitHub Enterprise Server exposes GraphQL under /api/graphql.
+func graphqlEndpoint(host string) string {
+	if host == "" {
+		return "https://api.github.com/graphql"
+	}
+	return "https://" + host + "/api/graphql"
+}
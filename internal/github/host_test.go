@@ -0,0 +1,31 @@
+package github
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithHost_NormalizesDefaultHost(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":                         "",
+		"github.com":               "",
+		"https://api.github.com/":  "",
+		"GHE.example.com":          "ghe.example.com",
+		"https://ghe.example.com/": "ghe.example.com",
+	}
+	for in, want := range cases {
+		got := HostFromContext(WithHost(context.Background(), in))
+		if got != want {
+			t.Fatalf("WithHost(%q): got %q, want %q", in, got, want)
+		}
+	}
+
+	if got := graphqlEndpoint("ghe.example.com"); got != "https://ghe.example.com/api/graphql" {
+		t.Fatalf("unexpected enterprise endpoint: %q", got)
+	}
+	if got := graphqlEndpoint(""); got != "https://api.github.com/graphql" {
+		t.Fatalf("unexpected default endpoint: %q", got)
+	}
+}
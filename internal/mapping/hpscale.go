@@ -0,0 +1,185 @@
+package mapping
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxHP is the highest brick HP (GitHub's four shades of green).
+const MaxHP = 4
+
+// CountStats summarizes the non-zero contribution counts of a grid.
+// Scales that depend on the distribution (quartiles, percentiles) read Sorted.
+type CountStats struct {
+	Max    int
+	Sorted []int // non-zero counts, ascending
+}
+
+// NewCountStats collects stats from the given counts; non-positive counts are ignored.
+func NewCountStats(counts []int) CountStats {
+	sorted := make([]int, 0, len(counts))
+	for _, c := range counts {
+		if c > 0 {
+			sorted = append(sorted, c)
+		}
+	}
+	slices.Sort(sorted)
+	st := CountStats{Sorted: sorted}
+	if n := len(sorted); n > 0 {
+		st.Max = sorted[n-1]
+	}
+	return st
+}
+
+// HPScale maps a contribution count to brick HP in 1..MaxHP.
+// A count <= 0 always yields 0 (no brick), as may counts a scale deems too
+// small (see ThresholdScale).
+type HPScale interface {
+	Name() string
+	HP(count int, stats CountStats) int
+}
+
+// LinearScale maps count/max linearly into 1..MaxHP (the original behavior).
+// A single very busy day makes every other brick 1 HP.
+type LinearScale struct{}
+
+func (LinearScale) Name() string { return "linear" }
+
+func (LinearScale) HP(count int, stats CountStats) int {
+	return HPFromCount(count, stats.Max)
+}
+
+// QuartileScale buckets counts by the quartiles of the non-zero distribution,
+// like GitHub's own calendar shading. The busiest days always get MaxHP, even
+// when they tie with the top quartile boundary.
+type QuartileScale struct{}
+
+func (QuartileScale) Name() string { return "quartile" }
+
+func (QuartileScale) HP(count int, stats CountStats) int {
+	if count <= 0 {
+		return 0
+	}
+	n := len(stats.Sorted)
+	if n == 0 {
+		return 1
+	}
+	if count >= stats.Max {
+		return MaxHP
+	}
+	hp := 1
+	for q := 1; q < MaxHP; q++ {
+		// Nearest-rank quartile boundary.
+		idx := int(math.Ceil(float64(q*n)/float64(MaxHP))) - 1
+		idx = min(max(idx, 0), n-1)
+		if count > stats.Sorted[idx] {
+			hp = q + 1
+		}
+	}
+	return hp
+}
+
+// LogScale maps log(1+count)/log(1+max) into 1..MaxHP, compressing outliers.
+type LogScale struct{}
+
+func (LogScale) Name() string { return "log" }
+
+func (LogScale) HP(count int, stats CountStats) int {
+	if count <= 0 {
+		return 0
+	}
+	if stats.Max <= 1 {
+		return 1
+	}
+	v := math.Log1p(float64(count)) / math.Log1p(float64(stats.Max))
+	return min(max(int(math.Ceil(MaxHP*v)), 1), MaxHP)
+}
+
+// PercentileScale maps the percentile rank of a count (mid-rank for ties)
+// evenly into 1..MaxHP, so each HP tier holds roughly the same number of bricks.
+type PercentileScale struct{}
+
+func (PercentileScale) Name() string { return "percentile" }
+
+func (PercentileScale) HP(count int, stats CountStats) int {
+	if count <= 0 {
+		return 0
+	}
+	n := len(stats.Sorted)
+	if n == 0 {
+		return 1
+	}
+	below := sort.SearchInts(stats.Sorted, count)
+	upto := sort.SearchInts(stats.Sorted, count+1)
+	rank := (float64(below) + float64(upto-below)/2.0) / float64(n)
+	return min(max(int(rank*MaxHP)+1, 1), MaxHP)
+}
+
+// ThresholdScale uses fixed count thresholds: HP is the number of thresholds
+// the count reaches, so counts below the first get no brick. Thresholds must
+// be ascending; the first is usually 1.
+type ThresholdScale struct {
+	Thresholds [MaxHP]int
+}
+
+// DefaultThresholds are used by "fixed" without explicit values.
+var DefaultThresholds = [MaxHP]int{1, 4, 8, 16}
+
+func (ThresholdScale) Name() string { return "fixed" }
+
+func (s ThresholdScale) HP(count int, _ CountStats) int {
+	if count <= 0 {
+		return 0
+	}
+	hp := 0
+	for i, t := range s.Thresholds {
+		if count >= t {
+			hp = i + 1
+		}
+	}
+	return hp
+}
+
+// HPScaleNames lists the values accepted by ParseHPScale (for flag help).
+var HPScaleNames = []string{"linear", "quartile", "log", "percentile", "fixed[:t1,t2,t3,t4]"}
+
+// ParseHPScale resolves a scale by name. "fixed" accepts optional thresholds,
+// e.g. "fixed:1,5,10,20". An empty name selects LinearScale.
+func ParseHPScale(name string) (HPScale, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "", "linear":
+		return LinearScale{}, nil
+	case "quartile", "quartiles", "github":
+		return QuartileScale{}, nil
+	case "log":
+		return LogScale{}, nil
+	case "percentile":
+		return PercentileScale{}, nil
+	case "fixed":
+		return ThresholdScale{Thresholds: DefaultThresholds}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(name, "fixed:"); ok {
+		parts := strings.Split(rest, ",")
+		if len(parts) != MaxHP {
+			return nil, fmt.Errorf("fixed HP scale needs %d thresholds, got %d", MaxHP, len(parts))
+		}
+		var s ThresholdScale
+		prev := 0
+		for i, p := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil || v <= prev {
+				return nil, fmt.Errorf("fixed HP scale thresholds must be ascending positive integers: %q", rest)
+			}
+			s.Thresholds[i] = v
+			prev = v
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown HP scale %q (expected one of: %s)", name, strings.Join(HPScaleNames, ", "))
+}
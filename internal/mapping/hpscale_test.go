@@ -0,0 +1,180 @@
+package mapping
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// sampleCalendar builds a deterministic 52-week calendar from a per-day count function.
+func sampleCalendar(count func(day int) int) github.Calendar {
+	var cal github.Calendar
+	for w := range 52 {
+		var week github.Week
+		for d := range daysPerWeek {
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Weekday:           d,
+				ContributionCount: count(w*daysPerWeek + d),
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	return cal
+}
+
+// lcg is a tiny deterministic generator so sample calendars never change.
+func lcg(day int) int {
+	return int((uint32(day)*1103515245 + 12345) >> 16)
+}
+
+var sampleCalendars = []struct {
+	name string
+	cal  github.Calendar
+}{
+	{
+		// Mostly 1-10 commits a day with a single 80-commit outlier.
+		name: "spike",
+		cal: sampleCalendar(func(day int) int {
+			if day == 200 {
+				return 80
+			}
+			return 1 + lcg(day)%10
+		}),
+	},
+	{
+		// Weekdays only, steadily increasing over the year.
+		name: "steady",
+		cal: sampleCalendar(func(day int) int {
+			if wd := day % daysPerWeek; wd == 0 || wd == 6 {
+				return 0
+			}
+			return 1 + day/30
+		}),
+	},
+	{
+		// Occasional contributions with long gaps.
+		name: "sparse",
+		cal: sampleCalendar(func(day int) int {
+			if lcg(day)%5 != 0 {
+				return 0
+			}
+			return 1 + lcg(day+7)%4
+		}),
+	},
+}
+
+func hpHistogram(g BrickGrid) [MaxHP + 1]int {
+	var h [MaxHP + 1]int
+	for r := range g.Rows {
+		for c := range g.Cols {
+			h[g.Cells[r][c].HP]++
+		}
+	}
+	return h
+}
+
+func TestHPScale_Golden(t *testing.T) {
+	t.Parallel()
+
+	scales := []HPScale{
+		LinearScale{},
+		QuartileScale{},
+		LogScale{},
+		PercentileScale{},
+		ThresholdScale{Thresholds: DefaultThresholds},
+	}
+	for _, scale := range scales {
+		t.Run(scale.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			for _, sc := range sampleCalendars {
+				g := BuildBrickGrid(sc.cal, 52, GridOptions{Scale: scale})
+				h := hpHistogram(g)
+				line := fmt.Sprintf("%-7s", sc.name)
+				for hp, n := range h {
+					line += fmt.Sprintf(" hp%d=%-3d", hp, n)
+				}
+				b.WriteString(strings.TrimRight(line, " ") + "\n")
+			}
+			got := b.String()
+
+			path := filepath.Join("testdata", "hpscale_"+scale.Name()+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if got != string(want) {
+				t.Fatalf("histogram mismatch for %s\ngot:\n%s\nwant:\n%s", scale.Name(), got, want)
+			}
+		})
+	}
+}
+
+func TestHPScale_Cases(t *testing.T) {
+	t.Parallel()
+
+	// A quarter of the days tie at the busiest count, on the top quartile
+	// boundary.
+	ties := NewCountStats([]int{1, 1, 2, 2, 3, 3, 5, 5})
+	tests := []struct {
+		name  string
+		scale HPScale
+		stats CountStats
+		count int
+		want  int
+	}{
+		{"quartile max ties at the boundary", QuartileScale{}, ties, 5, MaxHP},
+		{"quartile below the boundary", QuartileScale{}, ties, 3, 3},
+		{"quartile lowest", QuartileScale{}, ties, 1, 1},
+		{"quartile all equal", QuartileScale{}, NewCountStats([]int{2, 2, 2}), 2, MaxHP},
+		{"quartile zero", QuartileScale{}, ties, 0, 0},
+		{"fixed below the first threshold", ThresholdScale{Thresholds: [MaxHP]int{5, 10, 20, 40}}, CountStats{}, 4, 0},
+		{"fixed at the first threshold", ThresholdScale{Thresholds: [MaxHP]int{5, 10, 20, 40}}, CountStats{}, 5, 1},
+		{"fixed between thresholds", ThresholdScale{Thresholds: [MaxHP]int{5, 10, 20, 40}}, CountStats{}, 39, 3},
+		{"fixed above the last threshold", ThresholdScale{Thresholds: [MaxHP]int{5, 10, 20, 40}}, CountStats{}, 400, MaxHP},
+		{"fixed zero", ThresholdScale{Thresholds: DefaultThresholds}, CountStats{}, 0, 0},
+		{"fixed zero value, zero count", ThresholdScale{}, CountStats{}, 0, 0},
+		{"fixed zero value, negative count", ThresholdScale{}, CountStats{}, -3, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.scale.HP(tt.count, tt.stats); got != tt.want {
+			t.Errorf("%s: HP(%d) = %d, want %d", tt.name, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestParseHPScale(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "linear", "quartile", "log", "percentile", "fixed", "fixed:1,5,10,20"} {
+		if _, err := ParseHPScale(name); err != nil {
+			t.Fatalf("ParseHPScale(%q): unexpected error %v", name, err)
+		}
+	}
+	for _, name := range []string{"cubic", "fixed:1,2,3", "fixed:4,3,2,1", "fixed:a,b,c,d"} {
+		if _, err := ParseHPScale(name); err == nil {
+			t.Fatalf("ParseHPScale(%q): expected error", name)
+		}
+	}
+
+	s, _ := ParseHPScale("fixed:1,5,10,20")
+	for count, want := range map[int]int{0: 0, 1: 1, 4: 1, 5: 2, 19: 3, 20: 4, 500: 4} {
+		if got := s.HP(count, CountStats{}); got != want {
+			t.Fatalf("fixed HP(%d): got %d, want %d", count, got, want)
+		}
+	}
+}
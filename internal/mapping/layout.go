@@ -0,0 +1,134 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// Layout selects how calendar days are arranged on the board.
+type Layout int
+
+const (
+	// LayoutWeeks is GitHub's layout: weekday rows x week columns (default).
+	LayoutWeeks Layout = iota
+	// LayoutRotated puts weeks on rows and weekdays on columns, for tall narrow terminals.
+	LayoutRotated
+	// LayoutMirrored is LayoutWeeks flipped horizontally (most recent week on the left).
+	LayoutMirrored
+	// LayoutMonths groups days into one 7-row block per month, separated by gaps.
+	LayoutMonths
+)
+
+// LayoutNames lists the values accepted by ParseLayout, in Layout order.
+var LayoutNames = []string{"weeks", "rotated", "mirrored", "months"}
+
+func (l Layout) String() string {
+	if l < 0 || int(l) >= len(LayoutNames) {
+		return fmt.Sprintf("Layout(%d)", int(l))
+	}
+	return LayoutNames[l]
+}
+
+// ParseLayout resolves a layout by name. An empty name selects LayoutWeeks.
+func ParseLayout(name string) (Layout, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return LayoutWeeks, nil
+	}
+	for i, n := range LayoutNames {
+		if n == name {
+			return Layout(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown layout %q (expected one of: %s)", name, strings.Join(LayoutNames, ", "))
+}
+
+// maxWeeksPerMonth is the most week columns a calendar month can touch.
+const maxWeeksPerMonth = 6
+
+// buildMonthBlocks lays out one 7-row block per calendar month, wrapping blocks into
+// as many block rows as needed. Blocks are separated by one empty column/row.
+// Among arrangements that fit maxCols x maxRows, the one keeping the most week
+// columns per month wins; months are compressed into that width per mode.
+// Calendars without dates fall back to LayoutWeeks.
+func buildMonthBlocks(weeks []github.Week, maxCols, maxRows int, mode Compression) [][]BrickCell {
+	months := splitMonths(weeks)
+	if len(months) == 0 {
+		return compressWeeks(weeks, fitCount(len(weeks), maxCols, mode), mode)
+	}
+
+	widest := 1
+	for _, m := range months {
+		widest = max(widest, len(m))
+	}
+	if mode == CompressScroll {
+		// Months never scroll; keep them at full width and wrap instead.
+		mode = CompressMax
+	}
+
+	n := len(months)
+	perRow, blockW := 0, 0
+	for bpr := n; bpr >= 1; bpr-- {
+		blockRows := (n + bpr - 1) / bpr
+		if maxRows > 0 && blockRows*(daysPerWeek+1)-1 > maxRows {
+			break // fewer blocks per row only gets taller
+		}
+		k := min(widest, (maxCols-(bpr-1))/bpr)
+		if k > blockW {
+			perRow, blockW = bpr, k
+		}
+	}
+	if blockW < 1 {
+		return compressWeeks(weeks, fitCount(len(weeks), maxCols, mode), mode)
+	}
+
+	blockRows := (n + perRow - 1) / perRow
+	rows := blockRows*(daysPerWeek+1) - 1
+	cols := perRow*(blockW+1) - 1
+	cells := make([][]BrickCell, rows)
+	for r := range rows {
+		cells[r] = make([]BrickCell, cols)
+	}
+
+	for i, m := range months {
+		block := compressWeeks(m, min(blockW, len(m)), mode)
+		r0 := (i / perRow) * (daysPerWeek + 1)
+		c0 := (i % perRow) * (blockW + 1)
+		for r := range block {
+			copy(cells[r0+r][c0:], block[r])
+		}
+	}
+	return cells
+}
+
+// splitMonths regroups weeks by calendar month (from Day.Date "YYYY-MM-DD").
+// A week spanning two months contributes a partial week to each.
+// It returns nil if any day lacks a date.
+func splitMonths(weeks []github.Week) [][]github.Week {
+	var months [][]github.Week
+	cur := ""
+	for _, w := range weeks {
+		var part github.Week
+		for _, d := range w.ContributionDays {
+			if len(d.Date) < len("2006-01") {
+				return nil
+			}
+			key := d.Date[:len("2006-01")]
+			if key != cur {
+				if len(part.ContributionDays) > 0 {
+					months[len(months)-1] = append(months[len(months)-1], part)
+					part = github.Week{}
+				}
+				months = append(months, nil)
+				cur = key
+			}
+			part.ContributionDays = append(part.ContributionDays, d)
+		}
+		if len(part.ContributionDays) > 0 {
+			months[len(months)-1] = append(months[len(months)-1], part)
+		}
+	}
+	return months
+}
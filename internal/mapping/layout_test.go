@@ -0,0 +1,118 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// datedCalendar builds weeks starting on Sunday 2025-01-05 where each day's count is
+// its day of month, so blocks can be checked by date.
+func datedCalendar(weeks int) github.Calendar {
+	start := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	var cal github.Calendar
+	for w := range weeks {
+		var week github.Week
+		for d := range daysPerWeek {
+			day := start.AddDate(0, 0, w*daysPerWeek+d)
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Date:              day.Format("2006-01-02"),
+				Weekday:           d,
+				ContributionCount: day.Day(),
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	return cal
+}
+
+func TestBuildBrickGrid_RotatedAndMirrored(t *testing.T) {
+	t.Parallel()
+
+	cal := datedCalendar(10)
+	base := BuildBrickGrid(cal, 100, GridOptions{})
+
+	rot := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutRotated})
+	if rot.Rows != base.Cols || rot.Cols != base.Rows {
+		t.Fatalf("rotated dims: got %dx%d, want %dx%d", rot.Rows, rot.Cols, base.Cols, base.Rows)
+	}
+	if rot.Cells[3][5].Count != base.Cells[5][3].Count {
+		t.Fatalf("rotated grid is not a transpose")
+	}
+
+	rotCapped := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutRotated, MaxRows: 4})
+	if rotCapped.Rows != 4 {
+		t.Fatalf("rotated rows should be capped by MaxRows, got %d", rotCapped.Rows)
+	}
+
+	mir := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutMirrored})
+	if mir.Cells[2][0].Count != base.Cells[2][base.Cols-1].Count {
+		t.Fatalf("mirrored grid should start with the latest week")
+	}
+}
+
+func TestBuildBrickGrid_MonthBlocks(t *testing.T) {
+	t.Parallel()
+
+	// 2025-01-05 .. 2025-03-01: January (4 weeks), February (5 partial weeks), March 1st.
+	cal := datedCalendar(8)
+
+	g := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutMonths})
+	if g.Rows != daysPerWeek {
+		t.Fatalf("all months should fit in one block row, got %d rows", g.Rows)
+	}
+	// Blocks are 5 weeks wide with a 1-column gap: Jan [0..4], gap 5, Feb [6..10], gap 11, Mar [12..].
+	for r := range daysPerWeek {
+		if g.Cells[r][5].Count != 0 || g.Cells[r][11].Count != 0 {
+			t.Fatalf("expected empty gap columns between months (row %d)", r)
+		}
+	}
+	// Saturday 2025-02-01 starts February's block.
+	if got := g.Cells[6][6].Count; got != 1 {
+		t.Fatalf("expected Feb 1 at the start of February's block, got count %d", got)
+	}
+	// Sunday..Friday of that week stay in January's last column.
+	if got := g.Cells[5][3].Count; got != 31 {
+		t.Fatalf("expected Jan 31 in January's last column, got count %d", got)
+	}
+
+	// A narrow terminal wraps blocks into more block rows.
+	narrow := BuildBrickGrid(cal, 9, GridOptions{Layout: LayoutMonths})
+	if narrow.Rows <= daysPerWeek || narrow.Cols > 9 {
+		t.Fatalf("expected wrapped month blocks within 9 columns, got %dx%d", narrow.Rows, narrow.Cols)
+	}
+}
+
+func TestBuildBrickGrid_AxisInfo(t *testing.T) {
+	t.Parallel()
+
+	cal := datedCalendar(10)
+
+	base := BuildBrickGrid(cal, 100, GridOptions{})
+	if len(base.ColMonths) != base.Cols || base.ColMonths[0] != "2025-01" || base.ColMonths[base.Cols-1] != "2025-03" {
+		t.Fatalf("unexpected column months %v", base.ColMonths)
+	}
+	if len(base.RowWeekdays) != 7 || base.RowWeekdays[1] != 1 {
+		t.Fatalf("unexpected row weekdays %v", base.RowWeekdays)
+	}
+
+	mir := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutMirrored})
+	if mir.ColMonths[0] != "2025-03" {
+		t.Fatalf("mirrored months should start with the latest, got %v", mir.ColMonths)
+	}
+
+	rot := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutRotated})
+	if rot.ColMonths != nil || rot.RowWeekdays != nil {
+		t.Fatalf("rotated rows are weeks; expected no axis info")
+	}
+
+	months := BuildBrickGrid(cal, 100, GridOptions{Layout: LayoutMonths, MaxRows: 7})
+	if months.ColMonths != nil || months.RowWeekdays[0] != 0 {
+		t.Fatalf("month blocks keep weekday rows only, got %v %v", months.ColMonths, months.RowWeekdays)
+	}
+	stacked := BuildBrickGrid(cal, 10, GridOptions{Layout: LayoutMonths})
+	if stacked.Rows > 7 && (stacked.RowWeekdays[7] != -1 || stacked.RowWeekdays[8] != 0) {
+		t.Fatalf("gap rows between month blocks should be -1, got %v", stacked.RowWeekdays)
+	}
+}
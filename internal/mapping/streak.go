@@ -0,0 +1,85 @@
+package mapping
+
+import (
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// Streak is a run of consecutive days with at least one contribution. The zero
+// value is no streak.
+type Streak struct {
+	// First and Last are the run's first and last days ("YYYY-MM-DD").
+	First, Last string
+	Days        int
+}
+
+// Contains reports whether day ("YYYY-MM-DD") is part of the streak.
+func (s Streak) Contains(day string) bool {
+	return s.Days > 0 && day != "" && day >= s.First && day <= s.Last
+}
+
+// FindStreaks returns the longest contribution streak in cal (the earliest on
+// ties) and the current one: the run ending on the calendar's last day, or on
+// the day before when the last day has no contributions yet, as on GitHub.
+// Days without a date end any run.
+func FindStreaks(cal github.Calendar) (longest, current Streak) {
+	// grace is the run that ended the day before the day just read, when that
+	// day was empty.
+	var run, grace Streak
+	var prev time.Time
+	for _, w := range cal.Weeks {
+		for _, d := range w.ContributionDays {
+			grace = Streak{}
+			day, err := time.Parse(time.DateOnly, d.Date)
+			next := err == nil && run.Days > 0 && day.Equal(prev.AddDate(0, 0, 1))
+			if err != nil || d.ContributionCount <= 0 {
+				if next {
+					grace = run
+				}
+				run = Streak{}
+				continue
+			}
+			if !next {
+				run = Streak{First: d.Date}
+			}
+			run.Last = d.Date
+			run.Days++
+			prev = day
+			if run.Days > longest.Days {
+				longest = run
+			}
+		}
+	}
+	if run.Days > 0 {
+		return longest, run
+	}
+	return longest, grace
+}
+
+// markStreaks flags the cells that cover a day of any of the streaks. A cell
+// covers the days First, First+7, ..., Last, so compressed columns are flagged
+// when any of their merged days is.
+func markStreaks(cells [][]BrickCell, streaks ...Streak) {
+	for r := range cells {
+		for c := range cells[r] {
+			cell := &cells[r][c]
+			if cell.Count <= 0 || cell.First == "" {
+				continue
+			}
+			first, err1 := time.Parse(time.DateOnly, cell.First)
+			last, err2 := time.Parse(time.DateOnly, cell.Last)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			for d := first; !d.After(last) && !cell.Streak; d = d.AddDate(0, 0, daysPerWeek) {
+				for _, s := range streaks {
+					if s.Contains(d.Format(time.DateOnly)) {
+						cell.Streak = true
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// streakCalendar builds weeks of dated days from Sunday 2025-01-05 with one
+// contribution on the given day offsets and none elsewhere.
+func streakCalendar(weeks int, active ...int) github.Calendar {
+	cal := datedCalendar(weeks)
+	on := make(map[int]bool, len(active))
+	for _, d := range active {
+		on[d] = true
+	}
+	for w := range cal.Weeks {
+		for d := range cal.Weeks[w].ContributionDays {
+			day := &cal.Weeks[w].ContributionDays[d]
+			day.ContributionCount = 0
+			if on[w*daysPerWeek+d] {
+				day.ContributionCount = 1
+			}
+		}
+	}
+	return cal
+}
+
+func span(from, to int) []int {
+	var days []int
+	for d := from; d <= to; d++ {
+		days = append(days, d)
+	}
+	return days
+}
+
+func dayOf(offset int) string {
+	return time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC).AddDate(0, 0, offset).Format(time.DateOnly)
+}
+
+func TestFindStreaks(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name             string
+		active           []int
+		longest, current Streak
+	}{
+		{
+			name:   "none",
+			active: nil,
+		},
+		{
+			// Friday..Tuesday crosses the Saturday/Sunday week boundary.
+			name:    "across a week boundary",
+			active:  span(5, 9),
+			longest: Streak{First: dayOf(5), Last: dayOf(9), Days: 5},
+		},
+		{
+			name:    "current ends on the last day",
+			active:  append(span(2, 4), span(24, 27)...),
+			longest: Streak{First: dayOf(24), Last: dayOf(27), Days: 4},
+			current: Streak{First: dayOf(24), Last: dayOf(27), Days: 4},
+		},
+		{
+			name:    "an empty last day keeps the current streak",
+			active:  append(span(0, 5), span(23, 26)...),
+			longest: Streak{First: dayOf(0), Last: dayOf(5), Days: 6},
+			current: Streak{First: dayOf(23), Last: dayOf(26), Days: 4},
+		},
+		{
+			name:    "two empty days end it",
+			active:  span(20, 25),
+			longest: Streak{First: dayOf(20), Last: dayOf(25), Days: 6},
+		},
+		{
+			name:    "the earliest wins ties",
+			active:  append(span(1, 3), span(10, 12)...),
+			longest: Streak{First: dayOf(1), Last: dayOf(3), Days: 3},
+		},
+	}
+	for _, tc := range cases {
+		longest, current := FindStreaks(streakCalendar(4, tc.active...))
+		if longest != tc.longest || current != tc.current {
+			t.Fatalf("%s: got longest %+v current %+v, want %+v %+v", tc.name, longest, current, tc.longest, tc.current)
+		}
+	}
+}
+
+func TestFindStreaks_MissingDates(t *testing.T) {
+	t.Parallel()
+
+	// A week without dates splits what would be one run.
+	cal := streakCalendar(3, span(0, 20)...)
+	for d := range cal.Weeks[1].ContributionDays {
+		cal.Weeks[1].ContributionDays[d].Date = ""
+	}
+	longest, current := FindStreaks(cal)
+	if longest.Days != 7 || longest.First != dayOf(0) || current.First != dayOf(14) {
+		t.Fatalf("undated days should end runs, got %+v %+v", longest, current)
+	}
+}
+
+func TestBuildBrickGrid_StreakCells(t *testing.T) {
+	t.Parallel()
+
+	// Longest: Fri 01-10 .. Mon 01-13; current: Wed 01-29 .. Sat 02-01.
+	cal := streakCalendar(4, append(span(5, 8), span(24, 27)...)...)
+
+	full := BuildBrickGrid(cal, 4, GridOptions{})
+	if full.Longest.Days != 4 || full.Current.First != dayOf(24) {
+		t.Fatalf("unexpected streaks %+v %+v", full.Longest, full.Current)
+	}
+	want := map[[2]int]bool{
+		{5, 0}: true, {6, 0}: true, {0, 1}: true, {1, 1}: true, // across the week boundary
+		{3, 3}: true, {4, 3}: true, {5, 3}: true, {6, 3}: true,
+	}
+	for r := range full.Rows {
+		for c := range full.Cols {
+			if got := full.Cells[r][c].Streak; got != want[[2]int{r, c}] {
+				t.Fatalf("cell %d,%d: streak %v, want %v", r, c, got, !got)
+			}
+		}
+	}
+
+	// Two columns: the second merges weeks 3 and 4, so Wednesday..Saturday of
+	// the current streak mark it; the first merges weeks 1 and 2.
+	merged := BuildBrickGrid(cal, 2, GridOptions{})
+	for r, want := range []bool{true, true, false, true, true, true, true} {
+		if got := merged.Cells[r][0].Streak || merged.Cells[r][1].Streak; got != want {
+			t.Fatalf("row %d: streak %v, want %v", r, got, want)
+		}
+	}
+	if merged.Cells[0][1].Streak || !merged.Cells[0][0].Streak {
+		t.Fatalf("only the first column holds a Sunday of a streak")
+	}
+
+	// Latest keeps only the later week of each group: week 2 and week 4.
+	latest := BuildBrickGrid(cal, 2, GridOptions{Compress: CompressLatest})
+	if latest.Cells[5][0].Streak || !latest.Cells[0][0].Streak || !latest.Cells[6][1].Streak {
+		t.Fatalf("latest should only mark the shown week's days")
+	}
+
+	// Rotated layouts carry the flags with the cells.
+	rot := BuildBrickGrid(cal, 4, GridOptions{Layout: LayoutRotated})
+	if !rot.Cells[0][5].Streak || rot.Cells[0][4].Streak {
+		t.Fatalf("rotated cells should keep their streak flag")
+	}
+}
@@ -1,7 +1,10 @@
 package mapping
 
 import (
+	"fmt"
 	"math"
+	"slices"
+	"strings"
 
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
 )
@@ -11,15 +14,37 @@ const daysPerWeek = 7
 type BrickCell struct {
 	Count int
 	HP    int
+	// First and Last are the earliest and latest days merged into the cell
+	// ("YYYY-MM-DD"; "" if unknown). They differ only when weeks are compressed.
+	First, Last string
+	// Streak marks cells covering a day of the longest or current contribution
+	// streak (see FindStreaks).
+	Streak bool
 }
 
-// BrickGrid is a 7(row: weekday 0..6) x N(col) grid.
-// Rows correspond to GitHub's weekday numbering (0=Sunday..6=Saturday).
+// BrickGrid is a Rows x Cols grid of bricks; cells with HP 0 are empty.
+// With the default LayoutWeeks it is 7(row: weekday 0..6) x N(col: week), and rows
+// correspond to GitHub's weekday numbering (0=Sunday..6=Saturday).
 type BrickGrid struct {
 	Rows     int
 	Cols     int
 	MaxCount int
 	Cells    [][]BrickCell // [row][col]
+
+	// MonthStarts[c] reports whether column c begins a new calendar month
+	// (week-column layouts only; nil otherwise). Renderers may add gutters there.
+	MonthStarts []bool
+
+	// ColMonths[c] is the month ("YYYY-MM", "" if unknown) of the first week in
+	// column c (week-column layouts only; nil otherwise).
+	ColMonths []string
+	// RowWeekdays[r] is the weekday (0=Sunday..6=Saturday) of every cell in row r,
+	// or -1 for gap rows (nil when rows are not weekdays, as in LayoutRotated).
+	RowWeekdays []int
+
+	// Longest and Current are the calendar's contribution streaks; the cells
+	// covering them have Streak set.
+	Longest, Current Streak
 }
 
 func HPFromCount(count, maxCount int) int {
@@ -33,12 +58,70 @@ func HPFromCount(count, maxCount int) int {
 	return hp
 }
 
+// Compression selects how weeks are merged when the calendar has more weeks than
+// the terminal has columns.
+type Compression int
+
+const (
+	// CompressMax keeps the per-weekday maximum of each week group (default).
+	CompressMax Compression = iota
+	// CompressSum adds up each week group.
+	CompressSum
+	// CompressMean averages each week group (rounded up so no contribution vanishes).
+	CompressMean
+	// CompressLatest keeps only the most recent week of each group.
+	CompressLatest
+	// CompressScroll never merges weeks; the board is wider than the terminal and
+	// the view pans horizontally instead.
+	CompressScroll
+)
+
+// CompressionNames lists the values accepted by ParseCompression, in Compression order.
+var CompressionNames = []string{"max", "sum", "mean", "latest", "scroll"}
+
+func (c Compression) String() string {
+	if c < 0 || int(c) >= len(CompressionNames) {
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+	return CompressionNames[c]
+}
+
+// ParseCompression resolves a compression mode by name. An empty name selects CompressMax.
+func ParseCompression(name string) (Compression, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return CompressMax, nil
+	}
+	for i, n := range CompressionNames {
+		if n == name {
+			return Compression(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown compression %q (expected one of: %s)", name, strings.Join(CompressionNames, ", "))
+}
+
+// GridOptions tunes BuildBrickGrid. The zero value reproduces the original behavior.
+type GridOptions struct {
+	// Scale maps counts to HP. nil means LinearScale.
+	Scale HPScale
+	// Compress selects how weeks are merged to fit maxCols.
+	Compress Compression
+	// Layout arranges days on the board. The zero value is LayoutWeeks.
+	Layout Layout
+	// MaxRows caps the number of brick rows (0 = unlimited). Layouts that put weeks
+	// on rows compress them to fit.
+	MaxRows int
+}
+
 // BuildBrickGrid converts a GitHub Contribution Calendar into a brick grid.
 //
 // The calendar is week-major (N weeks x 7 days). For terminal constraints, weeks are
-// compressed into up to maxCols columns by grouping weeks and taking the per-weekday
-// MAX contributionCount within each group.
-func BuildBrickGrid(cal github.Calendar, maxCols int) BrickGrid {
+// compressed into up to maxCols columns by grouping weeks and aggregating each
+// weekday within a group according to opts.Compress (MAX by default).
+// CompressScroll ignores maxCols and keeps one column per week.
+//
+// opts.Layout rearranges the result; see Layout.
+func BuildBrickGrid(cal github.Calendar, maxCols int, opts GridOptions) BrickGrid {
 	weeks := cal.Weeks
 	if maxCols <= 0 {
 		maxCols = 1
@@ -47,49 +130,203 @@ func BuildBrickGrid(cal github.Calendar, maxCols int) BrickGrid {
 		return BrickGrid{Rows: daysPerWeek, Cols: 0, Cells: make([][]BrickCell, daysPerWeek)}
 	}
 
-	cols := len(weeks)
-	if cols > maxCols {
-		cols = maxCols
+	var cells [][]BrickCell
+	var colMonths []string
+	weekdays := true
+	switch opts.Layout {
+	case LayoutRotated:
+		cells = transpose(compressWeeks(weeks, fitCount(len(weeks), opts.MaxRows, opts.Compress), opts.Compress))
+		weekdays = false
+	case LayoutMirrored:
+		cols := fitCount(len(weeks), maxCols, opts.Compress)
+		cells = compressWeeks(weeks, cols, opts.Compress)
+		for _, row := range cells {
+			slices.Reverse(row)
+		}
+		colMonths = columnMonths(weeks, cols)
+		slices.Reverse(colMonths)
+	case LayoutMonths:
+		cells = buildMonthBlocks(weeks, maxCols, opts.MaxRows, opts.Compress)
+	default:
+		cols := fitCount(len(weeks), maxCols, opts.Compress)
+		cells = compressWeeks(weeks, cols, opts.Compress)
+		colMonths = columnMonths(weeks, cols)
+	}
+
+	rows := len(cells)
+	cols := 0
+	if rows > 0 {
+		cols = len(cells[0])
+	}
+
+	counts := make([]int, 0, rows*cols)
+	for r := range rows {
+		for c := 0; c < cols; c++ {
+			counts = append(counts, cells[r][c].Count)
+		}
+	}
+	stats := NewCountStats(counts)
+	longest, current := FindStreaks(cal)
+	markStreaks(cells, longest, current)
+
+	scale := opts.Scale
+	if scale == nil {
+		scale = LinearScale{}
+	}
+	for r := range rows {
+		for c := 0; c < cols; c++ {
+			cells[r][c].HP = min(scale.HP(cells[r][c].Count, stats), MaxHP)
+		}
 	}
 
+	return BrickGrid{
+		Rows:        rows,
+		Cols:        cols,
+		MaxCount:    stats.Max,
+		Cells:       cells,
+		MonthStarts: monthBoundaries(colMonths),
+		ColMonths:   colMonths,
+		RowWeekdays: rowWeekdays(rows, weekdays),
+		Longest:     longest,
+		Current:     current,
+	}
+}
+
+// columnMonths returns the month ("YYYY-MM") of the first dated day in each of the
+// cols week groups used by compressWeeks ("" when unknown).
+func columnMonths(weeks []github.Week, cols int) []string {
+	months := make([]string, cols)
+	if len(weeks) == 0 || cols <= 0 {
+		return months
+	}
+	for wi, w := range weeks {
+		col := (wi * cols) / len(weeks)
+		if months[col] != "" {
+			continue
+		}
+		for _, d := range w.ContributionDays {
+			if len(d.Date) >= len("2006-01") {
+				months[col] = d.Date[:len("2006-01")]
+				break
+			}
+		}
+	}
+	return months
+}
+
+// rowWeekdays returns BrickGrid.RowWeekdays for a grid of 7-row week blocks
+// separated by gap rows (LayoutMonths stacks several), or nil when !weekdays.
+func rowWeekdays(rows int, weekdays bool) []int {
+	if !weekdays {
+		return nil
+	}
+	out := make([]int, rows)
+	for r := range out {
+		out[r] = r % (daysPerWeek + 1)
+		if out[r] == daysPerWeek {
+			out[r] = -1
+		}
+	}
+	return out
+}
+
+func monthBoundaries(months []string) []bool {
+	if months == nil {
+		return nil
+	}
+	starts := make([]bool, len(months))
+	for c := 1; c < len(months); c++ {
+		starts[c] = months[c] != "" && months[c-1] != "" && months[c] != months[c-1]
+	}
+	return starts
+}
+
+// fitCount returns how many week groups to use for n weeks within limit
+// (limit <= 0 or CompressScroll means no compression).
+func fitCount(n, limit int, mode Compression) int {
+	if limit <= 0 || mode == CompressScroll {
+		return n
+	}
+	return min(n, limit)
+}
+
+// compressWeeks merges weeks into a 7(row: weekday) x cols grid of counts, evenly
+// distributing week indices into [0..cols-1] and aggregating per mode.
+func compressWeeks(weeks []github.Week, cols int, mode Compression) [][]BrickCell {
 	cells := make([][]BrickCell, daysPerWeek)
 	for r := range daysPerWeek {
 		cells[r] = make([]BrickCell, cols)
 	}
+	if len(weeks) == 0 || cols <= 0 {
+		return cells
+	}
+
+	// Days merged into each cell (for CompressMean).
+	var days [][]int
+	if mode == CompressMean {
+		days = make([][]int, daysPerWeek)
+		for r := range daysPerWeek {
+			days[r] = make([]int, cols)
+		}
+	}
 
-	// Evenly distribute week indices into [0..cols-1].
 	for wi, w := range weeks {
 		col := (wi * cols) / len(weeks)
+		if mode == CompressLatest && wi+1 < len(weeks) && ((wi+1)*cols)/len(weeks) == col {
+			// A later week in this group wins.
+			continue
+		}
 		for _, d := range w.ContributionDays {
 			r := d.Weekday
 			if r < 0 || r >= daysPerWeek {
 				continue
 			}
-			if d.ContributionCount > cells[r][col].Count {
-				cells[r][col].Count = d.ContributionCount
+			cell := &cells[r][col]
+			if d.Date != "" {
+				if cell.First == "" {
+					cell.First = d.Date
+				}
+				cell.Last = d.Date
+			}
+			switch mode {
+			case CompressSum:
+				cell.Count += d.ContributionCount
+			case CompressMean:
+				cell.Count += d.ContributionCount
+				days[r][col]++
+			case CompressLatest:
+				cell.Count = d.ContributionCount
+			default:
+				if d.ContributionCount > cell.Count {
+					cell.Count = d.ContributionCount
+				}
 			}
 		}
 	}
 
-	maxCount := 0
-	for r := range daysPerWeek {
-		for c := 0; c < cols; c++ {
-			if cells[r][c].Count > maxCount {
-				maxCount = cells[r][c].Count
+	if mode == CompressMean {
+		for r := range daysPerWeek {
+			for c := 0; c < cols; c++ {
+				if n := days[r][c]; n > 1 {
+					cells[r][c].Count = (cells[r][c].Count + n - 1) / n
+				}
 			}
 		}
 	}
+	return cells
+}
 
-	for r := range daysPerWeek {
-		for c := 0; c < cols; c++ {
-			cells[r][c].HP = HPFromCount(cells[r][c].Count, maxCount)
-		}
+func transpose(cells [][]BrickCell) [][]BrickCell {
+	if len(cells) == 0 {
+		return nil
 	}
-
-	return BrickGrid{
-		Rows:     daysPerWeek,
-		Cols:     cols,
-		MaxCount: maxCount,
-		Cells:    cells,
+	rows, cols := len(cells), len(cells[0])
+	out := make([][]BrickCell, cols)
+	for c := range cols {
+		out[c] = make([]BrickCell, rows)
+		for r := range rows {
+			out[c][r] = cells[r][c]
+		}
 	}
+	return out
 }
@@ -0,0 +1,89 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// weeksWithSunday builds a calendar where only Sundays have contributions.
+func weeksWithSunday(counts ...int) github.Calendar {
+	var cal github.Calendar
+	for _, n := range counts {
+		var w github.Week
+		for d := range daysPerWeek {
+			c := 0
+			if d == 0 {
+				c = n
+			}
+			w.ContributionDays = append(w.ContributionDays, github.Day{Weekday: d, ContributionCount: c})
+		}
+		cal.Weeks = append(cal.Weeks, w)
+	}
+	return cal
+}
+
+func TestBuildBrickGrid_Compression(t *testing.T) {
+	t.Parallel()
+
+	// 4 weeks into 2 columns: groups {3,1} and {0,5}.
+	cal := weeksWithSunday(3, 1, 0, 5)
+
+	cases := []struct {
+		mode Compression
+		want []int
+	}{
+		{CompressMax, []int{3, 5}},
+		{CompressSum, []int{4, 5}},
+		{CompressMean, []int{2, 3}},
+		{CompressLatest, []int{1, 5}},
+		{CompressScroll, []int{3, 1, 0, 5}},
+	}
+	for _, tc := range cases {
+		g := BuildBrickGrid(cal, 2, GridOptions{Compress: tc.mode})
+		if g.Cols != len(tc.want) {
+			t.Fatalf("%s: cols got %d, want %d", tc.mode, g.Cols, len(tc.want))
+		}
+		for c, want := range tc.want {
+			if got := g.Cells[0][c].Count; got != want {
+				t.Fatalf("%s: col %d count got %d, want %d", tc.mode, c, got, want)
+			}
+		}
+	}
+}
+
+func TestBuildBrickGrid_DateRange(t *testing.T) {
+	t.Parallel()
+
+	// 4 weeks from Sunday 2025-01-05.
+	cal := datedCalendar(4)
+
+	full := BuildBrickGrid(cal, 4, GridOptions{})
+	if c := full.Cells[5][1]; c.First != "2025-01-17" || c.Last != "2025-01-17" {
+		t.Fatalf("uncompressed cell should cover one day, got %s..%s", c.First, c.Last)
+	}
+
+	merged := BuildBrickGrid(cal, 2, GridOptions{})
+	if c := merged.Cells[0][1]; c.First != "2025-01-19" || c.Last != "2025-01-26" {
+		t.Fatalf("merged cell should cover both Sundays, got %s..%s", c.First, c.Last)
+	}
+
+	latest := BuildBrickGrid(cal, 2, GridOptions{Compress: CompressLatest})
+	if c := latest.Cells[0][1]; c.First != "2025-01-26" || c.Last != "2025-01-26" {
+		t.Fatalf("latest keeps only the later week, got %s..%s", c.First, c.Last)
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	t.Parallel()
+
+	for i, name := range CompressionNames {
+		got, err := ParseCompression(name)
+		if err != nil || got != Compression(i) {
+			t.Fatalf("ParseCompression(%q): got %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParseCompression("median"); err == nil {
+		t.Fatalf("expected error for unknown compression")
+	}
+}
@@ -0,0 +1,33 @@
+package mapping
+
+import "github.com/fchimpan/gh-kusa-breaker/internal/github"
+
+// WeekTotal is one calendar week's total contributions.
+type WeekTotal struct {
+	// First and Last are the week's first and last days ("YYYY-MM-DD"; "" if
+	// unknown).
+	First, Last string
+	Count       int
+}
+
+// BusiestWeek returns the week of cal with the most contributions (the earliest
+// on ties). It reports false when cal has no contributions at all.
+func BusiestWeek(cal github.Calendar) (WeekTotal, bool) {
+	var best WeekTotal
+	for _, w := range cal.Weeks {
+		var t WeekTotal
+		for _, d := range w.ContributionDays {
+			t.Count += max(d.ContributionCount, 0)
+			if d.Date != "" {
+				if t.First == "" {
+					t.First = d.Date
+				}
+				t.Last = d.Date
+			}
+		}
+		if t.Count > best.Count {
+			best = t
+		}
+	}
+	return best, best.Count > 0
+}
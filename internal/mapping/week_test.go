@@ -0,0 +1,25 @@
+package mapping
+
+import "testing"
+
+func TestBusiestWeek(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := BusiestWeek(streakCalendar(3)); ok {
+		t.Fatalf("an empty calendar has no busiest week")
+	}
+
+	// datedCalendar counts the day of the month: the last week, 01-26..02-01,
+	// sums 26+...+31+1 = 172.
+	got, ok := BusiestWeek(datedCalendar(4))
+	want := WeekTotal{First: "2025-01-26", Last: "2025-02-01", Count: 172}
+	if !ok || got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// Ties go to the earlier week.
+	got, _ = BusiestWeek(streakCalendar(3, 1, 2, 15, 16))
+	if got.First != dayOf(0) || got.Count != 2 {
+		t.Fatalf("expected the first week on a tie, got %+v", got)
+	}
+}
@@ -0,0 +1,481 @@
+// Package netplay runs a two-player match over TCP.
+//
+// The engine is deterministic, so the two ends only exchange the players'
+// calendars and the host's settings when they connect, then each paddle's
+// input per step. Both ends simulate both boards from the same inputs, in
+// lockstep: a step runs only once both inputs for it are known. Every
+// checkEvery steps the ends also swap a checksum of both boards, so builds
+// that compute a step differently (the engine uses floating point, and some
+// CPU architectures fuse multiply-adds) stop with ErrDesync instead of
+// silently playing different games.
+package netplay
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+const (
+	// Version is bumped whenever the protocol or the engine changes in a way
+	// that breaks lockstep between builds. Both ends must match.
+	Version = 2
+
+	// FieldW and FieldH are the size of each board. They are fixed so that both
+	// ends build the same boards whatever their terminal size.
+	FieldW = 50
+	FieldH = 20
+
+	// StepDT is the length of one simulation step in seconds.
+	StepDT = 1.0 / 120
+
+	// inputDelay is how many steps ahead local inputs are scheduled; it hides
+	// the round trip to the peer. maxLead caps how far this end schedules ahead
+	// of the steps simulated, so it waits for a slow peer instead of queueing.
+	inputDelay = 6
+	maxLead    = 60
+	// checkEvery is how often, in steps, the ends compare board checksums.
+	checkEvery = 60
+
+	handshakeTimeout = 10 * time.Second
+)
+
+// ErrPeerLeft is returned once the other end has closed the connection.
+var ErrPeerLeft = errors.New("the other player left the match")
+
+// ErrDesync is returned when the two ends have simulated different games.
+var ErrDesync = errors.New("the boards went out of sync between the two players (different CPU architectures?)")
+
+// Player is one side of a match: a login and the calendar their board is built
+// from.
+type Player struct {
+	Login    string          `json:"login"`
+	Calendar github.Calendar `json:"calendar"`
+}
+
+// Settings are the host's game settings. The guest adopts them, so both ends
+// build identical boards.
+type Settings struct {
+	Seed       uint64 `json:"seed"`
+	Difficulty string `json:"difficulty"` // see game.ParseDifficulty
+	Lives      int    `json:"lives"`      // 0 means the difficulty's default
+	SpeedRamp  string `json:"speed_ramp"` // see game.ParseSpeedRamp
+	HPScale    string `json:"hp_scale"`   // "" means the difficulty's default
+	// Speed is the game speed multiplier; both ends pace steps by it.
+	Speed float64 `json:"speed"`
+	// Steps is the match length in simulation steps.
+	Steps int `json:"steps"`
+}
+
+// hello is the guest's first message.
+type hello struct {
+	Version int    `json:"version"`
+	Player  Player `json:"player"`
+}
+
+// welcome is the host's answer to hello. Error is set when the host turns the
+// guest away.
+type welcome struct {
+	Version  int      `json:"version"`
+	Player   Player   `json:"player"`
+	Settings Settings `json:"settings"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// inputs carries the sender's paddle moves for steps From, From+1, ...,
+// and the checksums of the boards it has simulated since its last message.
+type inputs struct {
+	From  int    `json:"f"`
+	Moves []int8 `json:"m"`
+	Sums  []sum  `json:"s,omitempty"`
+}
+
+// sum is the checksum of both boards after Step steps.
+type sum struct {
+	Step int    `json:"n"`
+	Hash uint64 `json:"h"`
+}
+
+// Match is a lockstep match between this end and a peer. It is not safe for
+// concurrent use.
+type Match struct {
+	Players  [2]Player
+	Settings Settings
+	// Local is the board this end steers: 0 on the host, 1 on the guest.
+	Local int
+	// States are both boards, indexed like Players.
+	States [2]game.State
+
+	conn   net.Conn
+	enc    *json.Encoder
+	recv   chan inputs
+	closed chan struct{}
+	// readErr is why the reader stopped; it is set before recv is closed.
+	readErr error
+	err     error
+
+	step     int    // steps simulated
+	sent     int    // steps with a local input
+	received int    // steps with a remote input
+	local    []int8 // local moves of steps step..sent-1
+	remote   []int8 // remote moves of steps step..received-1
+
+	// unsent are local checksums not yet sent; localSums and remoteSums are
+	// the checksums of each end still waiting for the other's.
+	unsent     []sum
+	localSums  map[int]uint64
+	remoteSums map[int]uint64
+}
+
+// Host waits on ln for a guest and returns the match of me (board 0) against
+// them. Canceling ctx stops the wait by closing ln; otherwise the caller
+// closes ln.
+func Host(ctx context.Context, ln net.Listener, me Player, set Settings) (*Match, error) {
+	if _, err := newBoards([2]Player{me, me}, set); err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	conn, err := ln.Accept()
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	var m *Match
+	err = handshake(ctx, conn, func(dec *json.Decoder, enc *json.Encoder) error {
+		var h hello
+		if err := dec.Decode(&h); err != nil {
+			return fmt.Errorf("reading hello: %w", err)
+		}
+		if h.Version != Version {
+			_ = enc.Encode(welcome{Version: Version, Error: fmt.Sprintf("version mismatch: host has %d, you have %d", Version, h.Version)})
+			return fmt.Errorf("guest %q has protocol version %d, want %d", h.Player.Login, h.Version, Version)
+		}
+		if err := enc.Encode(welcome{Version: Version, Player: me, Settings: set}); err != nil {
+			return err
+		}
+		var err error
+		m, err = newMatch(conn, dec, enc, [2]Player{me, h.Player}, set, 0)
+		return err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Join connects to the host at addr and returns the match of the host
+// (board 0) against me.
+func Join(ctx context.Context, addr string, me Player) (*Match, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var m *Match
+	err = handshake(ctx, conn, func(dec *json.Decoder, enc *json.Encoder) error {
+		if err := enc.Encode(hello{Version: Version, Player: me}); err != nil {
+			return err
+		}
+		var w welcome
+		if err := dec.Decode(&w); err != nil {
+			return fmt.Errorf("reading welcome: %w", err)
+		}
+		if w.Error != "" {
+			return fmt.Errorf("host refused: %s", w.Error)
+		}
+		if w.Version != Version {
+			return fmt.Errorf("host has protocol version %d, want %d", w.Version, Version)
+		}
+		var err error
+		m, err = newMatch(conn, dec, enc, [2]Player{w.Player, me}, w.Settings, 1)
+		return err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// handshake runs fn on conn with a deadline, and aborts it when ctx is
+// canceled.
+func handshake(ctx context.Context, conn net.Conn, fn func(dec *json.Decoder, enc *json.Encoder) error) error {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+	err := fn(json.NewDecoder(conn), json.NewEncoder(conn))
+	if !stop() && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
+func newMatch(conn net.Conn, dec *json.Decoder, enc *json.Encoder, players [2]Player, set Settings, local int) (*Match, error) {
+	states, err := newBoards(players, set)
+	if err != nil {
+		return nil, err
+	}
+	m := &Match{
+		Players:  players,
+		Settings: set,
+		Local:    local,
+		States:   states,
+		conn:     conn,
+		enc:      enc,
+		recv:     make(chan inputs, 64),
+		closed:   make(chan struct{}),
+		sent:     inputDelay,
+		received: inputDelay,
+		// Both ends start with inputDelay steps of no input.
+		local:  make([]int8, inputDelay),
+		remote: make([]int8, inputDelay),
+
+		localSums:  make(map[int]uint64),
+		remoteSums: make(map[int]uint64),
+	}
+	go m.read(dec)
+	return m, nil
+}
+
+// newBoards builds both boards from the players' calendars and the host's
+// settings.
+func newBoards(players [2]Player, set Settings) ([2]game.State, error) {
+	var states [2]game.State
+	if set.Steps <= 0 {
+		return states, fmt.Errorf("match length must be > 0")
+	}
+	if set.Speed <= 0 {
+		return states, fmt.Errorf("speed must be > 0")
+	}
+	diff, err := game.ParseDifficulty(set.Difficulty)
+	if err != nil {
+		return states, err
+	}
+	ramp, err := game.ParseSpeedRamp(set.SpeedRamp)
+	if err != nil {
+		return states, err
+	}
+	hpScale := set.HPScale
+	if hpScale == "" {
+		hpScale = diff.HPScale
+	}
+	scale, err := mapping.ParseHPScale(hpScale)
+	if err != nil {
+		return states, err
+	}
+
+	opts := game.Options{Lives: set.Lives, Difficulty: diff, SpeedRamp: ramp}
+	gridOpts := mapping.GridOptions{Scale: scale, MaxRows: game.MaxBrickRows(FieldH)}
+	for i, p := range players {
+		// Bricks are 2 columns wide.
+		grid := mapping.BuildBrickGrid(p.Calendar, FieldW/2, gridOpts)
+		states[i] = game.NewStateWithOptions(grid, FieldW, FieldH, set.Seed, opts)
+	}
+	return states, nil
+}
+
+// read forwards the peer's inputs to recv until the connection fails or the
+// match is closed.
+func (m *Match) read(dec *json.Decoder) {
+	defer close(m.recv)
+	for {
+		var in inputs
+		if err := dec.Decode(&in); err != nil {
+			m.readErr = err
+			return
+		}
+		select {
+		case m.recv <- in:
+		case <-m.closed:
+			m.readErr = net.ErrClosed
+			return
+		}
+	}
+}
+
+// drain takes the inputs that have arrived so far.
+func (m *Match) drain() {
+	for {
+		select {
+		case in, ok := <-m.recv:
+			if !m.take(in, ok) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// take adds in, received from recv (ok is false once it is closed), and
+// reports whether more may follow.
+func (m *Match) take(in inputs, ok bool) bool {
+	if !ok {
+		m.fail(peerLeft(m.readErr))
+		return false
+	}
+	if in.From != m.received {
+		m.fail(fmt.Errorf("peer sent inputs from step %d, want %d", in.From, m.received))
+		return false
+	}
+	m.remote = append(m.remote, in.Moves...)
+	m.received += len(in.Moves)
+	for _, c := range in.Sums {
+		m.check(c, m.remoteSums, m.localSums)
+	}
+	return true
+}
+
+// peerLeft is the error of a connection that failed with err. A peer closing
+// its end shows as EOF to reads and as a broken pipe or reset to writes.
+func peerLeft(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return ErrPeerLeft
+	}
+	return fmt.Errorf("%w: %v", ErrPeerLeft, err)
+}
+
+// check records the checksum c of one end in mine and compares it with the
+// other end's, from theirs, once both are known.
+func (m *Match) check(c sum, mine, theirs map[int]uint64) {
+	h, ok := theirs[c.Step]
+	if !ok {
+		mine[c.Step] = c.Hash
+		return
+	}
+	delete(theirs, c.Step)
+	if h != c.Hash {
+		m.fail(fmt.Errorf("%w: at step %d", ErrDesync, c.Step))
+	}
+}
+
+// checksum hashes what decides both boards' futures.
+func (m *Match) checksum() uint64 {
+	h := fnv.New64a()
+	var b []byte
+	for i := range m.States {
+		s := &m.States[i]
+		for _, f := range []float64{s.BallX, s.BallY, s.BallVX, s.BallVY, s.PaddleX, s.PaddleW} {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+		}
+		for _, v := range []int{s.Score, s.Lives, s.BricksRemaining} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+	}
+	h.Write(b)
+	return h.Sum64()
+}
+
+func (m *Match) fail(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// Advance schedules up to n more steps of the local paddle held in direction
+// move (-1, 0 or +1), sends them to the peer and simulates every step whose
+// inputs both ends know, calling onEvents (when non-nil) with each board's
+// events after each step. It returns the number of steps simulated and the
+// error that ended the match early, e.g. ErrPeerLeft or ErrDesync.
+func (m *Match) Advance(n, move int, onEvents func(board int, events []game.Event)) (int, error) {
+	if _, over := m.Result(); over {
+		// The peer may leave once it has seen the result too.
+		return 0, nil
+	}
+	// Inputs that arrived before an error still count: the peer may have
+	// reached the result and left.
+	m.drain()
+
+	// Checksums go out even at the lead cap, and after a desync: the peer
+	// needs this end's checksums to find out too.
+	k := max(min(n, m.step+maxLead-m.sent), 0)
+	if (k > 0 || len(m.unsent) > 0) && (m.err == nil || errors.Is(m.err, ErrDesync)) {
+		moves := make([]int8, k)
+		for i := range moves {
+			moves[i] = int8(move)
+		}
+		if err := m.enc.Encode(inputs{From: m.sent, Moves: moves, Sums: m.unsent}); err != nil {
+			m.fail(peerLeft(err))
+		} else {
+			m.local = append(m.local, moves...)
+			m.sent += k
+			m.unsent = m.unsent[:0]
+		}
+	}
+
+	steps := 0
+	for len(m.local) > 0 && len(m.remote) > 0 && !errors.Is(m.err, ErrDesync) {
+		var in [2]game.Input
+		in[m.Local].Move = int(m.local[0])
+		in[1-m.Local].Move = int(m.remote[0])
+		m.local, m.remote = m.local[1:], m.remote[1:]
+		for i := range m.States {
+			s := &m.States[i]
+			s.Step(StepDT, in[i])
+			if onEvents != nil {
+				onEvents(i, s.Events())
+			}
+		}
+		m.step++
+		steps++
+		if m.step%checkEvery == 0 {
+			c := sum{Step: m.step, Hash: m.checksum()}
+			m.unsent = append(m.unsent, c)
+			m.check(c, m.localSums, m.remoteSums)
+		}
+		if _, over := m.Result(); over {
+			return steps, nil
+		}
+	}
+	return steps, m.err
+}
+
+// Result decides the match as game.VersusResult does, with time up after
+// Settings.Steps steps.
+func (m *Match) Result() (winner int, over bool) {
+	return game.VersusResult(&m.States[0], &m.States[1], m.step >= m.Settings.Steps)
+}
+
+// StepsLeft is the number of steps until time is up.
+func (m *Match) StepsLeft() int {
+	return max(m.Settings.Steps-m.step, 0)
+}
+
+// Waiting reports whether the match is stalled on the peer's inputs.
+func (m *Match) Waiting() bool {
+	_, over := m.Result()
+	return !over && m.err == nil && len(m.remote) == 0 && m.sent-m.step >= maxLead
+}
+
+// Close ends the match and closes the connection.
+func (m *Match) Close() error {
+	select {
+	case <-m.closed:
+		return nil
+	default:
+	}
+	close(m.closed)
+	return m.conn.Close()
+}
@@ -0,0 +1,291 @@
+package netplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// testCalendar returns weeks weeks of contributions, shifted by offset so that
+// two players get different boards.
+func testCalendar(weeks, offset int) github.Calendar {
+	var cal github.Calendar
+	start := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	for w := range weeks {
+		var week github.Week
+		for d := range 7 {
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Date:              start.AddDate(0, 0, w*7+d).Format("2006-01-02"),
+				Weekday:           d,
+				ContributionCount: (w + d + offset) % 5,
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	return cal
+}
+
+var testSettings = Settings{Seed: 7, Difficulty: "normal", Lives: 3, SpeedRamp: "hits=8,breach", Speed: 1, Steps: 20 * 120}
+
+// connect runs a host and a guest over loopback.
+func connect(t *testing.T, set Settings) (host, guest *Match) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	type result struct {
+		m   *Match
+		err error
+	}
+	hostc := make(chan result, 1)
+	go func() {
+		m, err := Host(ctx, ln, Player{"alice", testCalendar(30, 0)}, set)
+		hostc <- result{m, err}
+	}()
+	guest, err = Join(ctx, ln.Addr().String(), Player{"bob", testCalendar(20, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := <-hostc
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	t.Cleanup(func() {
+		r.m.Close()
+		guest.Close()
+	})
+	return r.m, guest
+}
+
+// receive waits until the peer's next inputs, or the end of the connection,
+// reach m, and reports whether more may follow.
+func receive(m *Match, deadline time.Time) (bool, error) {
+	select {
+	case in, ok := <-m.recv:
+		return m.take(in, ok), nil
+	case <-time.After(time.Until(deadline)):
+		return false, fmt.Errorf("no inputs from the peer by step %d", m.step)
+	}
+}
+
+// advanceUntil advances m until it has simulated steps steps or the match is
+// over, steering with move(step). It waits for the peer's inputs whenever it
+// has run out of them.
+func advanceUntil(m *Match, steps int, move func(step int) int) error {
+	deadline := time.Now().Add(5 * time.Second)
+	for m.step < steps {
+		if _, over := m.Result(); over {
+			return nil
+		}
+		if _, err := m.Advance(min(4, steps-m.sent+inputDelay), move(m.sent), nil); err != nil {
+			return err
+		}
+		if _, over := m.Result(); !over && m.step < steps && len(m.remote) == 0 {
+			if _, err := receive(m, deadline); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// advanceBoth runs advanceUntil on both ends at once.
+func advanceBoth(t *testing.T, host, guest *Match, steps int, hostMove, guestMove func(step int) int) {
+	t.Helper()
+	errc := make(chan error, 1)
+	go func() { errc <- advanceUntil(guest, steps, guestMove) }()
+	if err := advanceUntil(host, steps, hostMove); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatch_Handshake(t *testing.T) {
+	t.Parallel()
+
+	host, guest := connect(t, testSettings)
+	if host.Local != 0 || guest.Local != 1 {
+		t.Fatalf("host should steer board 0, guest board 1: %d, %d", host.Local, guest.Local)
+	}
+	for _, m := range []*Match{host, guest} {
+		if m.Players[0].Login != "alice" || m.Players[1].Login != "bob" || m.Settings != testSettings {
+			t.Fatalf("unexpected match: %+v, %+v", m.Players[0].Login, m.Settings)
+		}
+		if m.States[0].Lives != 3 || m.States[0].BricksTotal == m.States[1].BricksTotal {
+			t.Fatalf("boards should follow the settings and each calendar: %+v", m.States)
+		}
+	}
+}
+
+func TestMatch_Lockstep(t *testing.T) {
+	t.Parallel()
+
+	host, guest := connect(t, testSettings)
+	// Each end steers its own paddle; neither knows the other's moves ahead.
+	hostMove := func(step int) int { return []int{-1, 0, 1}[step/50%3] }
+	guestMove := func(step int) int { return []int{1, 1, -1, 0}[step/70%4] }
+
+	advanceBoth(t, host, guest, 1200, hostMove, guestMove)
+
+	for i := range host.States {
+		a, b := &host.States[i], &guest.States[i]
+		if a.BallX != b.BallX || a.BallY != b.BallY || a.PaddleX != b.PaddleX || a.Score != b.Score || a.BricksRemaining != b.BricksRemaining {
+			t.Fatalf("board %d diverged: ball (%v,%v)/(%v,%v), paddle %v/%v, score %d/%d",
+				i, a.BallX, a.BallY, b.BallX, b.BallY, a.PaddleX, b.PaddleX, a.Score, b.Score)
+		}
+	}
+	if host.States[0].PaddleX == host.States[1].PaddleX {
+		t.Fatalf("paddles should follow their own player's inputs")
+	}
+}
+
+func TestMatch_TimeUpAndPeerLeft(t *testing.T) {
+	t.Parallel()
+
+	set := testSettings
+	set.Steps = 120
+	host, guest := connect(t, set)
+	still := func(int) int { return 0 }
+	advanceBoth(t, host, guest, 1000, still, still)
+	if _, over := host.Result(); !over || host.step != 120 || guest.step != 120 || host.StepsLeft() != 0 {
+		t.Fatalf("both ends should stop at the time limit: %d, %d", host.step, guest.step)
+	}
+
+	// A peer leaving after the result is not an error...
+	guest.Close()
+	if _, err := host.Advance(1, 0, nil); err != nil {
+		t.Fatalf("unexpected error after the match: %v", err)
+	}
+
+	// ...but it is during the match.
+	host, guest = connect(t, testSettings)
+	guest.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := host.Advance(1, 0, nil)
+		if err != nil {
+			if err != ErrPeerLeft {
+				t.Fatalf("expected ErrPeerLeft, got %v", err)
+			}
+			break
+		}
+		if _, err := receive(host, deadline); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHost_RejectsBadSettingsAndVersions(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	bad := testSettings
+	bad.Difficulty = "nightmare"
+	if _, err := Host(context.Background(), ln, Player{Login: "alice"}, bad); err == nil {
+		t.Fatalf("expected an error for an unknown difficulty")
+	}
+
+	// A guest with another protocol version is turned away.
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(`{"version":99,"player":{"login":"bob"}}` + "\n"))
+		var buf [256]byte
+		conn.Read(buf[:])
+	}()
+	_, err = Host(context.Background(), ln, Player{Login: "alice"}, testSettings)
+	if err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+
+	// Canceling stops the wait.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Host(ctx, ln, Player{Login: "alice"}, testSettings); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMatch_ResultBeforePeerLeft(t *testing.T) {
+	t.Parallel()
+
+	set := testSettings
+	set.Steps = 30
+	host, guest := connect(t, set)
+	still := func(int) int { return 0 }
+
+	// The host schedules all its inputs; the guest plays them out, reaches the
+	// result and leaves before the host has simulated them.
+	if _, err := host.Advance(40, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := advanceUntil(guest, 1000, still); err != nil {
+		t.Fatal(err)
+	}
+	if _, over := guest.Result(); !over {
+		t.Fatalf("the guest should have reached the time limit")
+	}
+	guest.Close()
+	// Let the host read the inputs and the close.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		more, err := receive(host, deadline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !more {
+			break
+		}
+	}
+
+	if _, err := host.Advance(1, 0, nil); err != nil {
+		t.Fatalf("the host should reach the result first, got %v", err)
+	}
+	if _, over := host.Result(); !over || host.step != 30 {
+		t.Fatalf("the host should have simulated the buffered steps: step %d", host.step)
+	}
+}
+
+func TestMatch_Desync(t *testing.T) {
+	t.Parallel()
+
+	host, guest := connect(t, testSettings)
+	// Nudge the host's copy of the guest's board, as a different float
+	// rounding would.
+	host.States[1].BallX += 1e-9
+	still := func(int) int { return 0 }
+
+	errc := make(chan error, 2)
+	for _, m := range []*Match{host, guest} {
+		go func() { errc <- advanceUntil(m, 10*checkEvery, still) }()
+	}
+	for range 2 {
+		if err := <-errc; !errors.Is(err, ErrDesync) {
+			t.Fatalf("expected ErrDesync on both ends, got %v", err)
+		}
+	}
+	if host.step > 2*checkEvery {
+		t.Fatalf("the desync should be caught at the next checksum, not step %d", host.step)
+	}
+}
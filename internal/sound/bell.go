@@ -0,0 +1,52 @@
+package sound
+
+import "github.com/fchimpan/gh-kusa-breaker/internal/game"
+
+// bellGap is the shortest time between two bells, so a chain of destroyed
+// bricks does not turn into one long buzz.
+const bellGap = 0.1
+
+// Bell rings the terminal bell (BEL) when a brick is destroyed and on game over.
+// It writes nothing itself: the UI takes each ring with Ringing and draws the
+// BEL with its next frame, so it reaches the terminal through the renderer.
+type Bell struct {
+	wait    float64 // seconds until the next bell may ring
+	pending bool
+}
+
+// NewBell returns a silent Bell.
+func NewBell() *Bell {
+	return &Bell{}
+}
+
+func (b *Bell) Handle(events []game.Event) {
+	for _, ev := range events {
+		switch {
+		case ev.Kind == game.EventBrickDestroyed && b.wait <= 0:
+			b.ring()
+		case ev.Kind == game.EventBallLost && ev.Lives == 0:
+			// Game over always rings.
+			b.ring()
+		}
+	}
+}
+
+func (b *Bell) ring() {
+	b.pending = true
+	b.wait = bellGap
+}
+
+// Ringing reports whether the bell rang since the last call.
+func (b *Bell) Ringing() bool {
+	r := b.pending
+	b.pending = false
+	return r
+}
+
+func (b *Bell) Advance(dt float64) {
+	b.wait -= dt
+}
+
+func (b *Bell) Close() error {
+	return nil
+}
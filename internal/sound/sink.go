@@ -0,0 +1,247 @@
+package sound
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink receives synthesized audio. Tests use an in-memory sink, so nothing
+// here needs a sound card.
+type Sink interface {
+	// WriteSamples writes mono signed 16-bit samples at SampleRate. The slice
+	// is reused by the caller after the call returns.
+	WriteSamples(samples []int16) error
+	Close() error
+}
+
+// wavHeaderSize is the size of a canonical PCM WAV header (RIFF + fmt + data).
+const wavHeaderSize = 44
+
+// WAVSink writes samples to a WAV file. The header is written up front with
+// empty sizes, which Close fills in.
+type WAVSink struct {
+	w      io.WriteSeeker
+	closer io.Closer // nil when the caller owns w
+	n      int64     // data bytes written
+	b      []byte
+}
+
+// NewWAVSink writes a WAV header to w and returns a sink appending samples to
+// it. Close patches the header but does not close w.
+func NewWAVSink(w io.WriteSeeker) (*WAVSink, error) {
+	s := &WAVSink{w: w}
+	if _, err := w.Write(wavHeader(0)); err != nil {
+		return nil, fmt.Errorf("failed to write WAV header: %w", err)
+	}
+	return s, nil
+}
+
+// CreateWAV creates (or truncates) the WAV file at path.
+func CreateWAV(path string) (*WAVSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sound file: %w", err)
+	}
+	s, err := NewWAVSink(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.closer = f
+	return s, nil
+}
+
+func (s *WAVSink) WriteSamples(samples []int16) error {
+	s.b = appendPCM(s.b[:0], samples)
+	n, err := s.w.Write(s.b)
+	s.n += int64(n)
+	return err
+}
+
+// Close fills in the header sizes and closes the file it was created with.
+func (s *WAVSink) Close() error {
+	var err error
+	if _, err = s.w.Seek(0, io.SeekStart); err == nil {
+		if _, err = s.w.Write(wavHeader(s.n)); err == nil {
+			_, err = s.w.Seek(0, io.SeekEnd)
+		}
+	}
+	if s.closer != nil {
+		err = errors.Join(err, s.closer.Close())
+	}
+	return err
+}
+
+// wavHeader returns the header of a mono 16-bit PCM WAV file at SampleRate
+// holding dataSize bytes of samples.
+func wavHeader(dataSize int64) []byte {
+	const channels, bits = 1, 16
+	le := binary.LittleEndian
+	h := make([]byte, 0, wavHeaderSize)
+	h = append(h, "RIFF"...)
+	h = le.AppendUint32(h, uint32(wavHeaderSize-8+dataSize))
+	h = append(h, "WAVEfmt "...)
+	h = le.AppendUint32(h, 16) // fmt chunk size
+	h = le.AppendUint16(h, 1)  // PCM
+	h = le.AppendUint16(h, channels)
+	h = le.AppendUint32(h, SampleRate)
+	h = le.AppendUint32(h, SampleRate*channels*bits/8) // byte rate
+	h = le.AppendUint16(h, channels*bits/8)            // block align
+	h = le.AppendUint16(h, bits)
+	h = append(h, "data"...)
+	h = le.AppendUint32(h, uint32(dataSize))
+	return h
+}
+
+func appendPCM(b []byte, samples []int16) []byte {
+	for _, v := range samples {
+		b = binary.LittleEndian.AppendUint16(b, uint16(v))
+	}
+	return b
+}
+
+// streamQueue is how many sample buffers may wait for the audio player (about
+// a quarter second at 60 ticks per second) before new ones are dropped.
+const streamQueue = 16
+
+// closeTimeout bounds each step of closing a player: draining the queue into
+// it, and waiting for it to exit before it is killed.
+const closeTimeout = time.Second
+
+// streamSink pipes raw PCM into an external audio player. A goroutine does the
+// writes, so a player that stops reading drops audio instead of stalling the
+// game.
+type streamSink struct {
+	in      io.WriteCloser
+	wait    func() error // waits for the player to exit
+	kill    func() error // stops a player that does not exit
+	timeout time.Duration
+	// queue holds encoded buffers for the writer; free recycles them.
+	queue chan []byte
+	free  chan []byte
+	done  chan struct{}
+
+	mu  sync.Mutex
+	err error // first write error
+}
+
+func newStreamSink(in io.WriteCloser, wait, kill func() error) *streamSink {
+	s := &streamSink{
+		in:      in,
+		wait:    wait,
+		kill:    kill,
+		timeout: closeTimeout,
+		queue:   make(chan []byte, streamQueue),
+		free:    make(chan []byte, streamQueue),
+		done:    make(chan struct{}),
+	}
+	go s.write()
+	return s
+}
+
+// write sends the queue to the player until it is closed. After an error the
+// rest is dropped.
+func (s *streamSink) write() {
+	defer close(s.done)
+	var err error
+	for b := range s.queue {
+		if err == nil {
+			if _, err = s.in.Write(b); err != nil {
+				s.mu.Lock()
+				s.err = err
+				s.mu.Unlock()
+			}
+		}
+		select {
+		case s.free <- b:
+		default:
+		}
+	}
+}
+
+// players are the audio players StartPlayer tries, in order. Each reads raw
+// mono s16le PCM at SampleRate from stdin.
+var players = [][]string{
+	{"aplay", "-q", "-t", "raw", "-f", "S16_LE", "-r", "22050", "-c", "1"},
+	{"paplay", "--raw", "--format=s16le", "--rate=22050", "--channels=1"},
+	{"play", "-q", "-t", "raw", "-e", "signed", "-b", "16", "-r", "22050", "-c", "1", "-"},
+}
+
+// StartPlayer starts the first audio player found in PATH (aplay, paplay, or
+// SoX's play) and returns a sink streaming to it.
+func StartPlayer() (Sink, error) {
+	var names []string
+	for _, args := range players {
+		names = append(names, args[0])
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		in, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start %s: %w", args[0], err)
+		}
+		return newStreamSink(in, cmd.Wait, cmd.Process.Kill), nil
+	}
+	return nil, fmt.Errorf("no audio player found (tried %s); use --sound-file to write a WAV file instead", strings.Join(names, ", "))
+}
+
+// WriteSamples queues samples for the player without waiting for it; they
+// are dropped while the queue is full. It returns the first write error.
+func (s *streamSink) WriteSamples(samples []int16) error {
+	s.mu.Lock()
+	err := s.err
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	var b []byte
+	select {
+	case b = <-s.free:
+	default:
+	}
+	b = appendPCM(b[:0], samples)
+	select {
+	case s.queue <- b:
+	default:
+	}
+	return nil
+}
+
+// Close ends the stream and waits for the player to finish what it buffered.
+// A player that stops reading or does not exit in time is cut off and killed,
+// so quitting never hangs on it.
+func (s *streamSink) Close() error {
+	close(s.queue)
+	select {
+	case <-s.done:
+	case <-time.After(s.timeout):
+	}
+	// Closing stdin also fails a write the player is not reading.
+	err := s.in.Close()
+	<-s.done
+
+	exited := make(chan error, 1)
+	go func() { exited <- s.wait() }()
+	select {
+	case werr := <-exited:
+		return errors.Join(err, werr)
+	case <-time.After(s.timeout):
+	}
+	if kerr := s.kill(); kerr != nil {
+		return errors.Join(err, fmt.Errorf("failed to stop the audio player: %w", kerr))
+	}
+	<-exited
+	return errors.Join(err, errors.New("audio player did not exit; killed it"))
+}
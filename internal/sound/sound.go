@@ -0,0 +1,88 @@
+// Package sound turns game events into audio feedback: terminal bells, or
+// chiptune blips synthesized as PCM and written to a Sink.
+package sound
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// Mode selects the kind of audio feedback.
+type Mode int
+
+const (
+	// ModeOff plays nothing (default).
+	ModeOff Mode = iota
+	// ModeBell rings the terminal bell when a brick is destroyed and on game over.
+	ModeBell
+	// ModeSynth plays a short blip for every event.
+	ModeSynth
+)
+
+// ModeNames lists the values accepted by ParseMode, in Mode order.
+var ModeNames = []string{"off", "bell", "synth"}
+
+func (m Mode) String() string {
+	if m < 0 || int(m) >= len(ModeNames) {
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+	return ModeNames[m]
+}
+
+// ParseMode resolves a sound mode by name. An empty name selects ModeOff.
+func ParseMode(name string) (Mode, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ModeOff, nil
+	}
+	for i, n := range ModeNames {
+		if n == name {
+			return Mode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown sound mode %q (expected one of: %s)", name, strings.Join(ModeNames, ", "))
+}
+
+// Player reacts to game events. Calls come from the game loop, one goroutine.
+type Player interface {
+	// Handle reacts to the events of one engine step (see game.State.Events).
+	Handle(events []game.Event)
+	// Advance moves the player's clock by dt seconds of real time.
+	Advance(dt float64)
+	// Close flushes pending output and releases the player's resources.
+	Close() error
+}
+
+// Ringer is a Player that rings the terminal bell. The UI draws the bell with
+// its frames, so that only the renderer writes to the terminal.
+type Ringer interface {
+	Player
+	// Ringing reports whether the bell rang since the last call.
+	Ringing() bool
+}
+
+// Open returns the player for mode, or nil for ModeOff. Bells are left to the
+// UI (see Ringer). Synth output goes to the WAV file at wavPath when set, and
+// otherwise is streamed to an external audio player (see StartPlayer).
+func Open(mode Mode, wavPath string) (Player, error) {
+	switch mode {
+	case ModeBell:
+		return NewBell(), nil
+	case ModeSynth:
+		var sink Sink
+		var err error
+		if wavPath != "" {
+			sink, err = CreateWAV(wavPath)
+		} else {
+			sink, err = StartPlayer()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewSynth(sink), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,260 @@
+package sound
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// memSink collects samples in memory.
+type memSink struct {
+	samples []int16
+	closed  bool
+}
+
+func (s *memSink) WriteSamples(samples []int16) error {
+	s.samples = append(s.samples, samples...)
+	return nil
+}
+
+func (s *memSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+// crossings counts sign changes, twice the number of square-wave periods.
+func crossings(samples []int16) int {
+	n := 0
+	for i := 1; i < len(samples); i++ {
+		if (samples[i-1] < 0) != (samples[i] < 0) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	for i, name := range ModeNames {
+		m, err := ParseMode(name)
+		if err != nil || m != Mode(i) || m.String() != name {
+			t.Fatalf("ParseMode(%q) = %v, %v", name, m, err)
+		}
+	}
+	if m, err := ParseMode(""); err != nil || m != ModeOff {
+		t.Fatalf("empty name should be off, got %v, %v", m, err)
+	}
+	if _, err := ParseMode("midi"); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
+
+func TestBell_RingsOnDestroyAndGameOver(t *testing.T) {
+	t.Parallel()
+
+	b := NewBell()
+	b.Handle([]game.Event{{Kind: game.EventBrickHit, HPLeft: 1}, {Kind: game.EventPaddleHit}, {Kind: game.EventBallLost, Lives: 2}})
+	if b.Ringing() {
+		t.Fatalf("hits, paddle bounces and lost balls should not ring")
+	}
+
+	b.Handle([]game.Event{{Kind: game.EventBrickDestroyed}})
+	b.Advance(bellGap / 2)
+	b.Handle([]game.Event{{Kind: game.EventBrickDestroyed}})
+	if !b.Ringing() || b.Ringing() {
+		t.Fatalf("bells in a burst should be merged into one ring")
+	}
+	b.Advance(bellGap)
+	b.Handle([]game.Event{{Kind: game.EventBrickDestroyed}})
+	if !b.Ringing() {
+		t.Fatalf("expected a bell for the destroy")
+	}
+	b.Handle([]game.Event{{Kind: game.EventBallLost, Lives: 0}})
+	if !b.Ringing() {
+		t.Fatalf("game over should always ring")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestSynth_PitchByHP(t *testing.T) {
+	t.Parallel()
+
+	blip := func(hpLeft int) []int16 {
+		sink := &memSink{}
+		s := NewSynth(sink)
+		s.Handle([]game.Event{{Kind: game.EventBrickHit, HPLeft: hpLeft}})
+		s.Advance(0.05)
+		return sink.samples
+	}
+	low := crossings(blip(1))  // HP 2 before the hit
+	high := crossings(blip(3)) // HP 4 before the hit
+	if low == 0 || high <= low {
+		t.Fatalf("harder bricks should blip higher: HP2 %d crossings, HP4 %d", low, high)
+	}
+}
+
+func TestSynth_WritesSilenceBetweenEvents(t *testing.T) {
+	t.Parallel()
+
+	sink := &memSink{}
+	s := NewSynth(sink)
+	for range 60 {
+		s.Advance(1.0 / 60)
+	}
+	if len(sink.samples) < SampleRate-1 || len(sink.samples) > SampleRate {
+		t.Fatalf("one second should be %d samples, got %d", SampleRate, len(sink.samples))
+	}
+	for _, v := range sink.samples {
+		if v != 0 {
+			t.Fatalf("expected silence without events")
+		}
+	}
+
+	s.Handle([]game.Event{{Kind: game.EventCleared}})
+	s.Advance(1)
+	if len(s.voices) != 0 {
+		t.Fatalf("voices should finish, %d left", len(s.voices))
+	}
+	if crossings(sink.samples) == 0 {
+		t.Fatalf("expected the clear jingle")
+	}
+	if err := s.Close(); err != nil || !sink.closed {
+		t.Fatalf("Close should close the sink: %v", err)
+	}
+}
+
+func TestSynth_AdvanceDoesNotAllocate(t *testing.T) {
+	sink := &WAVSink{w: nopSeeker{}}
+	s := NewSynth(sink)
+	events := []game.Event{{Kind: game.EventBrickHit, HPLeft: 2}, {Kind: game.EventPaddleHit}}
+	s.Handle(events)
+	s.Advance(1.0 / 60)
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Handle(events)
+		s.Advance(1.0 / 60)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected 0 allocs per frame, got %.1f", allocs)
+	}
+}
+
+type nopSeeker struct{}
+
+func (nopSeeker) Write(p []byte) (int, error)    { return len(p), nil }
+func (nopSeeker) Seek(int64, int) (int64, error) { return 0, nil }
+
+func TestStreamSink_StalledPlayerDropsSamples(t *testing.T) {
+	t.Parallel()
+
+	r, w := io.Pipe()
+	nop := func() error { return nil }
+	sink := newStreamSink(w, nop, nop)
+	samples := make([]int16, SampleRate/60)
+	// Nobody reads the pipe: writes must not wait for the player.
+	for range 10 * streamQueue {
+		if err := sink.WriteSamples(samples); err != nil {
+			t.Fatalf("WriteSamples: %v", err)
+		}
+	}
+
+	read := make(chan int64)
+	go func() {
+		n, _ := io.Copy(io.Discard, r)
+		read <- n
+	}()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// The queue plus the buffer the writer was blocked on got through.
+	if n, limit := <-read, int64(2*len(samples)*(streamQueue+1)); n == 0 || n > limit {
+		t.Fatalf("expected 1..%d bytes to reach the player, got %d", limit, n)
+	}
+}
+
+func TestStreamSink_CloseStalledPlayer(t *testing.T) {
+	t.Parallel()
+
+	// The player neither reads its input nor exits until it is killed.
+	_, w := io.Pipe()
+	exited := make(chan struct{})
+	var killed atomic.Bool
+	sink := newStreamSink(w,
+		func() error { <-exited; return errors.New("signal: killed") },
+		func() error { killed.Store(true); close(exited); return nil })
+	sink.timeout = 20 * time.Millisecond
+	samples := make([]int16, SampleRate/60)
+	for range 2 * streamQueue {
+		if err := sink.WriteSamples(samples); err != nil {
+			t.Fatalf("WriteSamples: %v", err)
+		}
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- sink.Close() }()
+	select {
+	case err := <-closed:
+		if err == nil || !killed.Load() {
+			t.Fatalf("Close should kill the stalled player and say so, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Close hung on a stalled player")
+	}
+}
+
+func TestCreateWAV_Header(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "out.wav")
+	p, err := Open(ModeSynth, path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	p.Handle([]game.Event{{Kind: game.EventBrickDestroyed}})
+	p.Advance(0.5)
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) <= wavHeaderSize || string(data[:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " || string(data[36:40]) != "data" {
+		t.Fatalf("not a WAV file: % x", data[:min(len(data), wavHeaderSize)])
+	}
+	le := binary.LittleEndian
+	if got := le.Uint32(data[24:]); got != SampleRate {
+		t.Fatalf("sample rate %d, want %d", got, SampleRate)
+	}
+	dataSize := le.Uint32(data[40:])
+	if int(dataSize) != len(data)-wavHeaderSize || int(le.Uint32(data[4:])) != len(data)-8 {
+		t.Fatalf("header sizes do not match a %d byte file (data %d)", len(data), dataSize)
+	}
+	if dataSize/2 != SampleRate/2 {
+		t.Fatalf("expected half a second of samples, got %d", dataSize/2)
+	}
+}
+
+func TestOpen_OffAndBell(t *testing.T) {
+	t.Parallel()
+
+	p, err := Open(ModeOff, "")
+	if p != nil || err != nil {
+		t.Fatalf("off should return no player, got %v, %v", p, err)
+	}
+	if p, err := Open(ModeBell, ""); err != nil {
+		t.Fatal(err)
+	} else if _, ok := p.(Ringer); !ok {
+		t.Fatalf("the bell should be left to the UI, got %T", p)
+	}
+}
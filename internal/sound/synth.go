@@ -0,0 +1,160 @@
+package sound
+
+import (
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// SampleRate is the rate of the PCM the synth produces, in samples per second.
+const SampleRate = 22050
+
+const (
+	maxVoices = 16
+	// voiceGain is the peak amplitude of one voice; several may overlap.
+	voiceGain = 0.25 * 32767
+)
+
+// hpNotes are the hit blips by brick HP before the hit (C5 E5 G5 C6).
+var hpNotes = [5]float64{0, 523.25, 659.25, 783.99, 1046.50}
+
+// voice is one square-wave blip that slides from f0 to f1 and fades out.
+type voice struct {
+	delay  float64 // seconds before it starts
+	t, dur float64
+	f0, f1 float64 // Hz
+	vol    float64 // 0..1
+	phase  float64 // 0..1
+}
+
+// Synth generates chiptune blips for game events and writes them to a Sink as
+// mono 16-bit PCM at SampleRate. Silence between events is written too, so a
+// WAV recording keeps the game's timing.
+type Synth struct {
+	sink   Sink
+	voices []voice
+	buf    []int16
+	acc    float64 // fractional samples carried to the next Advance
+	err    error
+}
+
+// NewSynth returns a Synth writing to sink. Close closes the sink.
+func NewSynth(sink Sink) *Synth {
+	return &Synth{sink: sink, voices: make([]voice, 0, maxVoices)}
+}
+
+func (s *Synth) Handle(events []game.Event) {
+	for _, ev := range events {
+		switch ev.Kind {
+		case game.EventBrickHit:
+			if ev.HPLeft > 0 {
+				hp := min(ev.HPLeft+1, len(hpNotes)-1)
+				s.play(voice{dur: 0.06, f0: hpNotes[hp], f1: hpNotes[hp], vol: 0.8})
+			}
+		case game.EventBrickDestroyed:
+			// A rising chirp from the HP 1 note.
+			s.play(voice{dur: 0.1, f0: hpNotes[1], f1: hpNotes[1] * 2, vol: 1})
+		case game.EventPaddleHit:
+			s.play(voice{dur: 0.05, f0: 330, f1: 330, vol: 0.7})
+		case game.EventWallBounce:
+			s.play(voice{dur: 0.03, f0: 220, f1: 220, vol: 0.4})
+		case game.EventBallLost:
+			dur := 0.35
+			if ev.Lives == 0 {
+				dur = 0.8
+			}
+			s.play(voice{dur: dur, f0: 440, f1: 110, vol: 1})
+		case game.EventCleared:
+			for i, f := range hpNotes[1:] {
+				s.play(voice{delay: 0.09 * float64(i), dur: 0.12, f0: f, f1: f, vol: 0.9})
+			}
+		case game.EventBossHit:
+			// A low thud that rises as the boss weakens.
+			f := 110 + 110*(1-float64(ev.HPLeft)/float64(max(ev.Count, 1)))
+			s.play(voice{dur: 0.08, f0: f, f1: f * 0.8, vol: 1})
+		case game.EventHazardHit:
+			s.play(voice{dur: 0.15, f0: 880, f1: 440, vol: 0.9})
+		case game.EventPowerUp:
+			// Two quick rising notes.
+			s.play(voice{dur: 0.06, f0: hpNotes[2], f1: hpNotes[2], vol: 0.8})
+			s.play(voice{delay: 0.06, dur: 0.1, f0: hpNotes[4], f1: hpNotes[4] * 1.5, vol: 0.8})
+		case game.EventSpeedUp:
+			// A short rising sweep.
+			s.play(voice{dur: 0.12, f0: 220, f1: 660, vol: 0.6})
+		}
+	}
+}
+
+// play starts v unless every voice is busy.
+func (s *Synth) play(v voice) {
+	if len(s.voices) < maxVoices {
+		s.voices = append(s.voices, v)
+	}
+}
+
+// Advance renders dt seconds of audio and writes it to the sink. After a write
+// error the synth goes quiet; Close reports the error.
+func (s *Synth) Advance(dt float64) {
+	if s.err != nil || dt <= 0 {
+		return
+	}
+	s.acc += dt * SampleRate
+	n := int(s.acc)
+	s.acc -= float64(n)
+	if n == 0 {
+		return
+	}
+	if cap(s.buf) < n {
+		s.buf = make([]int16, n)
+	}
+	buf := s.buf[:n]
+	s.render(buf)
+	s.err = s.sink.WriteSamples(buf)
+}
+
+// render mixes the active voices into buf and drops the ones that finished.
+func (s *Synth) render(buf []int16) {
+	const step = 1.0 / SampleRate
+	for i := range buf {
+		var mix float64
+		for j := range s.voices {
+			v := &s.voices[j]
+			if v.delay > 0 {
+				v.delay -= step
+				continue
+			}
+			if v.t >= v.dur {
+				continue
+			}
+			k := v.t / v.dur
+			f := v.f0 + (v.f1-v.f0)*k
+			level := v.vol * (1 - k)
+			if v.phase < 0.5 {
+				mix += level
+			} else {
+				mix -= level
+			}
+			v.phase += f * step
+			if v.phase >= 1 {
+				v.phase -= 1
+			}
+			v.t += step
+		}
+		buf[i] = int16(min(max(mix*voiceGain, -32768), 32767))
+	}
+
+	live := s.voices[:0]
+	for _, v := range s.voices {
+		if v.t < v.dur {
+			live = append(live, v)
+		}
+	}
+	s.voices = live
+}
+
+// Close closes the sink and returns the first error.
+func (s *Synth) Close() error {
+	err := s.sink.Close()
+	if s.err != nil {
+		return s.err
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorSupported reports whether the terminal on stdout can show colors.
+// It is false for TERM=dumb, NO_COLOR and output that is not a terminal.
+func ColorSupported() bool {
+	return lipgloss.ColorProfile() != termenv.Ascii
+}
+
+// boxChars are the overlay border pieces: horizontal, vertical, then corners
+// top-left, top-right, bottom-left, bottom-right.
+type boxChars struct {
+	h, v, tl, tr, bl, br string
+}
+
+var (
+	roundBox  = boxChars{h: "─", v: "│", tl: "╭", tr: "╮", bl: "╰", br: "╯"}
+	doubleBox = boxChars{h: "═", v: "║", tl: "╔", tr: "╗", bl: "╚", br: "╝"}
+	asciiBox  = boxChars{h: "-", v: "|", tl: "+", tr: "+", bl: "+", br: "+"}
+)
+
+// asciiOverlay are the overlay characters in plain text, where a celebration
+// has no color to tell it apart.
+var asciiOverlay = overlayGlyph{
+	box:          asciiBox,
+	celebrateBox: boxChars{h: "=", v: "|", tl: "*", tr: "*", bl: "*", br: "*"},
+	fill:         " ",
+}
+
+// asciiShade is the ASCII stand-in for ░▒▓█ (HP 1..4).
+var asciiShade = [5]string{" ", ".", ":", "%", "#"}
+
+// newASCIIPalette returns a palette that writes plain ASCII with no escape
+// sequences. The renderers are shared with the colored palettes, so only the
+// cells differ. Bricks show their HP digit, or a density ramp with GlyphsShade.
+func newASCIIPalette(g Glyphs) *palette {
+	p := &palette{
+		name:       "ascii",
+		plain:      true,
+		paddleCell: "=",
+		ballCell:   "*",
+		// Plain text has no bold, so the flash swaps the paddle character.
+		paddleFlash: "#",
+		trailCells:  [3]string{"o", ".", "."},
+		cursorCells: cursorCells(lipgloss.NewStyle()),
+		dropCells:   dropCells(lipgloss.NewStyle()),
+		hazardCell:  "v",
+		barFull:     "#",
+		barEmpty:    ".",
+		arrows:      "arrows",
+		overlay:     asciiOverlay,
+	}
+	for hp := 1; hp <= 4; hp++ {
+		if g == GlyphsShade {
+			p.brickGlyph[hp] = asciiShade[hp]
+		} else {
+			p.brickGlyph[hp] = GlyphsDigits.glyph(hp)
+		}
+		p.brickCell1[hp] = p.brickGlyph[hp]
+		p.streakCells[hp] = [4]string{"[", p.brickGlyph[hp], "]", "@"}
+		p.fragCells[hp] = asciiFragGlyphs
+	}
+	// Zero styles render text unchanged.
+	p.legend = "hp " + p.brickCell1[1] + p.brickCell1[2] + p.brickCell1[3] + p.brickCell1[4]
+	return p
+}
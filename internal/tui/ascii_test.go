@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// TestASCII_NoEscapes renders every screen in ASCII mode on a terminal that
+// does support color, so that any styled cell would show. It changes the
+// global color profile and so must not run in parallel.
+func TestASCII_NoEscapes(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(prev)
+
+	check := func(screen, v string) {
+		t.Helper()
+		if i := strings.Index(v, "\x1b"); i >= 0 {
+			t.Fatalf("%s: escape sequence at byte %d:\n%q", screen, i, v)
+		}
+	}
+
+	// A colored model does write escapes, or the test would prove nothing.
+	colored := NewModel("octocat", inspectCalendar(), 1, Options{})
+	colored.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if !strings.Contains(colored.View(), "\x1b") {
+		t.Fatalf("the color profile should be active")
+	}
+
+	for _, glyphs := range []Glyphs{GlyphsOff, GlyphsShade} {
+		m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true, Glyphs: glyphs, Lives: 3})
+		m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+		check("intro", m.View())
+		m.introActive = false
+
+		// Play a little, with effects.
+		now := time.Now()
+		for range 30 {
+			now = now.Add(time.Second / 60)
+			m.Update(tickMsg(now))
+		}
+		check("play", m.View())
+		m.Update(key("i"))
+		check("inspect", m.View())
+		m.Update(key("esc"))
+
+		m.state.GameOver = true
+		check("game over", m.View())
+		m.state.GameOver = false
+		m.state.Cleared = true
+		for range 30 {
+			now = now.Add(time.Second / 60)
+			m.Update(tickMsg(now)) // confetti
+		}
+		check("clear", m.View())
+
+		m.Update(key("b"))
+		if !m.bossStage {
+			t.Fatalf("b should start the boss stage")
+		}
+		check("boss", m.View())
+		m.state.GameOver = true
+		check("boss game over", m.View())
+		m.state.GameOver = false
+		m.state.Cleared = true
+		check("boss defeated", m.View())
+
+		empty := NewModel("octocat", github.Calendar{}, 1, Options{ASCII: true, Glyphs: glyphs})
+		empty.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+		check("no contributions", empty.View())
+
+		v := versusModel(Options{Glyphs: glyphs})
+		check("versus", v.View())
+		v.over, v.winner = true, 0
+		check("versus over", v.View())
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// bossAvailable reports whether the CLEAR screen offers the boss stage.
+func (m *Model) bossAvailable() bool {
+	return m.hasBoss && !m.bossStage && m.state.Cleared
+}
+
+// startBoss leaves the cleared board for the boss stage, keeping the score and
+// the balls left.
+func (m *Model) startBoss() {
+	m.bossEntry = m.state
+	m.bossStage = true
+	m.newBossState()
+}
+
+// newBossState (re)starts the boss fight for the current field size.
+func (m *Model) newBossState() {
+	gameW, gameH := m.fieldSize()
+	m.state = game.NewBossState(m.busiest, gameW, gameH, m.seed, m.gameOpts)
+	m.state.CarryOver(&m.bossEntry)
+	m.noBricks = false
+	m.inspecting = false
+	m.lastTick = time.Time{}
+	m.acc = 0
+	m.hold.reset()
+	m.confetti = nil
+	m.confettiSpawn = 0
+	m.fx.reset()
+	m.labels = nil
+}
+
+// bossCell returns the cell of the boss at field cell (x, y), or "" when the
+// boss does not cover it. The boss is drawn as an outlined HP 4 brick.
+func bossCell(p *palette, b *game.Boss, x, y int) string {
+	if b == nil || b.HP <= 0 {
+		return ""
+	}
+	x0, y0 := int(math.Floor(b.X)), int(math.Floor(b.Y))
+	if x < x0 || x >= x0+b.W || y < y0 || y >= y0+b.H {
+		return ""
+	}
+	cells := &p.streakCells[4]
+	switch x {
+	case x0:
+		return cells[0]
+	case x0 + b.W - 1:
+		return cells[2]
+	default:
+		return cells[1]
+	}
+}
+
+// drawBoss adds the boss and its projectiles to l (already sized for the view
+// of s).
+func drawBoss(l *fxLayer, p *palette, s *game.State) {
+	vx := s.ViewLeft()
+	if b := s.Boss; b != nil && b.HP > 0 {
+		x0, y0 := int(math.Floor(b.X)), int(math.Floor(b.Y))
+		for y := y0; y < y0+b.H; y++ {
+			for x := x0; x < x0+b.W; x++ {
+				l.put(x-vx, y, bossCell(p, b, x, y))
+			}
+		}
+	}
+	for _, pr := range s.Projectiles {
+		l.put(int(math.Floor(pr.X))-vx, int(math.Floor(pr.Y)), p.hazardCell)
+	}
+}
+
+// bossLines describe the defeated boss on the BOSS DEFEATED overlay.
+func bossLines(b *game.Boss) []string {
+	return []string{fmt.Sprintf("busiest week: %s (%s)", dayRange(b.First, b.Last), contributions(b.MaxHP))}
+}
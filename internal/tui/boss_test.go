@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+func TestBossStage_StartAndRetry(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true, Lives: 3})
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m.introActive = false
+
+	// Not before the board is cleared.
+	m.Update(key("b"))
+	if m.bossStage {
+		t.Fatalf("the boss stage needs a cleared board")
+	}
+
+	m.state.Cleared = true
+	m.state.Award(game.ScoreBricks, 1200)
+	m.state.Lives = 2
+	if v := m.View(); !strings.Contains(v, "(b boss stage, r retry, q quit)") {
+		t.Fatalf("CLEAR should offer the boss stage:\n%s", v)
+	}
+	m.Update(key("b"))
+	b := m.state.Boss
+	if !m.bossStage || b == nil || m.state.Cleared {
+		t.Fatalf("b should start the boss stage")
+	}
+	// The second week, 03-09..03-15, is the busiest (9+...+15 minus 13).
+	if b.First != "2025-03-09" || b.MaxHP != 71 || m.state.Score != 1200 || m.state.Lives != 2 {
+		t.Fatalf("unexpected boss stage: %+v, score %d, lives %d", b, m.state.Score, m.state.Lives)
+	}
+	v := m.View()
+	if !strings.Contains(v, "boss   71/  71 [") || !strings.Contains(v, "[4444") {
+		t.Fatalf("expected the boss and its HP bar:\n%s", v)
+	}
+
+	// Resizing mid-fight keeps the boss's HP and the balls lost; retrying
+	// goes back to the board.
+	b.HP = 10
+	m.state.Lives = 1
+	m.state.Award(game.ScoreBricks, 50)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if !m.bossStage || m.state.Boss.HP != 10 || m.state.Lives != 1 || m.state.Score != 1250 {
+		t.Fatalf("a resize should keep the fight going, got boss HP %d, %d lives, score %d", m.state.Boss.HP, m.state.Lives, m.state.Score)
+	}
+	if b := m.state.Boss; b.X < 0 || b.X+float64(b.W) > float64(m.state.Width) || m.state.BallX >= float64(m.state.Width) {
+		t.Fatalf("positions should fit the new field: boss %+v, ball x %.1f, width %d", b, m.state.BallX, m.state.Width)
+	}
+	m.Update(key("r"))
+	if m.bossStage || m.state.Boss != nil || m.state.BricksRemaining == 0 {
+		t.Fatalf("retry should go back to the board")
+	}
+}
+
+func TestBossStage_Defeated(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true})
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m.introActive = false
+	m.state.Cleared = true
+	m.Update(key("b"))
+
+	m.state.Boss.HP = 0
+	m.state.Cleared = true
+	v := m.View()
+	if !strings.Contains(v, "BOSS DEFEATED! (r retry, q quit)") {
+		t.Fatalf("expected the boss info line:\n%s", v)
+	}
+	if got := bossLines(m.state.Boss)[0]; got != "busiest week: 2025-03-09..2025-03-15 (71 contributions)" {
+		t.Fatalf("unexpected boss line %q", got)
+	}
+	m.Update(key("b"))
+	if m.state.Boss.HP != 0 {
+		t.Fatalf("the boss stage is offered once per board")
+	}
+}
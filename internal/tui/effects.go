@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// fxLayer is a sparse set of cells drawn over the field, in view coordinates.
+// It is refilled every frame; only the cells set last frame are cleared, so an
+// idle layer costs nothing and a busy one does not allocate.
+type fxLayer struct {
+	w, h  int
+	cells []string // [y*w+x]; "" draws the field underneath
+	rowN  []int    // cells set per row
+	set   []int    // indices set since the last clear
+
+	// paddleFlash draws the paddle in its hit color.
+	paddleFlash bool
+}
+
+func (l *fxLayer) resize(w, h int) {
+	if l.w == w && l.h == h {
+		l.clear()
+		return
+	}
+	l.w, l.h = w, h
+	l.cells = make([]string, w*h)
+	l.rowN = make([]int, h)
+	l.set = l.set[:0]
+	l.paddleFlash = false
+}
+
+func (l *fxLayer) clear() {
+	for _, i := range l.set {
+		l.cells[i] = ""
+		l.rowN[i/l.w] = 0
+	}
+	l.set = l.set[:0]
+	l.paddleFlash = false
+}
+
+func (l *fxLayer) put(x, y int, cell string) {
+	if x < 0 || y < 0 || x >= l.w || y >= l.h {
+		return
+	}
+	i := y*l.w + x
+	if l.cells[i] == "" {
+		l.set = append(l.set, i)
+		l.rowN[y]++
+	}
+	l.cells[i] = cell
+}
+
+// row returns the cells of row y, or nil when nothing is drawn on it.
+func (l *fxLayer) row(y int) []string {
+	if l == nil || y < 0 || y >= l.h || l.rowN[y] == 0 {
+		return nil
+	}
+	return l.cells[y*l.w : (y+1)*l.w]
+}
+
+const (
+	maxParticles = 256
+	// trailLen ball positions are kept, one per cell the ball enters.
+	trailLen = 4
+	trailTTL = 0.25
+	// flashTTL is how long the paddle glows after a hit.
+	flashTTL = 0.12
+	// fragGravity pulls shatter fragments down, in cells/s².
+	fragGravity = 40.0
+)
+
+// fragGlyphs are the characters shatter fragments are drawn with.
+var (
+	fragGlyphs      = [4]string{"▪", "·", "'", ","}
+	asciiFragGlyphs = [4]string{".", "'", ",", "`"}
+)
+
+type particle struct {
+	x, y   float64 // field coordinates
+	vx, vy float64
+	age    float64
+	ttl    float64
+	shade  int // brick HP shade (1..4) the fragment takes its color from
+	glyph  int // index into palette.fragCells[shade]
+}
+
+type trailPoint struct {
+	x, y float64
+	age  float64
+}
+
+// effects turns engine events into short-lived visuals: shatter fragments when
+// bricks are hit or destroyed, a fading trail behind the ball, and a paddle
+// flash on hits. Particles keep a shade and glyph index rather than rendered
+// cells, so switching themes recolors them too.
+type effects struct {
+	parts []particle
+	trail [trailLen]trailPoint
+	nTail int
+	flash float64
+}
+
+func (e *effects) reset() {
+	e.parts = e.parts[:0]
+	e.nTail = 0
+	e.flash = 0
+}
+
+// handle reacts to the events of one engine step.
+func (e *effects) handle(events []game.Event, s *game.State, rng *rand.Rand) {
+	for _, ev := range events {
+		switch ev.Kind {
+		case game.EventBrickDestroyed:
+			br := &s.Bricks[ev.Brick]
+			cx := float64(br.X) + float64(br.W)/2
+			cy := float64(br.Y) + float64(br.H)/2
+			// The last shade shown was HP 1.
+			e.burst(cx, cy, 1, 6+2*br.W*br.H, 14, rng)
+		case game.EventBrickHit:
+			if ev.HPLeft > 0 {
+				// The brick darkened by one shade; chip off the old one.
+				e.burst(ev.X, ev.Y, ev.HPLeft+1, 2, 8, rng)
+			}
+		case game.EventBossHit:
+			e.burst(ev.X, ev.Y, 4, 4, 10, rng)
+		case game.EventBossDefeated:
+			if b := s.Boss; b != nil {
+				e.burst(b.X+float64(b.W)/2, b.Y+float64(b.H)/2, 4, 6*b.W, 18, rng)
+			}
+		case game.EventPaddleHit:
+			e.flash = flashTTL
+		case game.EventWallBounce:
+			e.burst(ev.X, ev.Y, 1, 1, 5, rng)
+		}
+	}
+}
+
+func (e *effects) burst(x, y float64, shade, n int, speed float64, rng *rand.Rand) {
+	shade = min(max(shade, 1), 4)
+	for range n {
+		if len(e.parts) >= maxParticles {
+			return
+		}
+		a := rng.Float64() * 2 * math.Pi
+		v := speed * (0.4 + 0.6*rng.Float64())
+		e.parts = append(e.parts, particle{
+			x: x, y: y,
+			vx:    math.Cos(a) * v,
+			vy:    math.Sin(a)*v*0.5 - 4,
+			ttl:   0.35 + 0.35*rng.Float64(),
+			shade: shade,
+			glyph: rng.IntN(len(fragGlyphs)),
+		})
+	}
+}
+
+// update advances the effects by dt seconds with the ball at (bx, by).
+func (e *effects) update(dt, bx, by float64) {
+	out := e.parts[:0]
+	for _, p := range e.parts {
+		p.age += dt
+		if p.age >= p.ttl {
+			continue
+		}
+		p.vy += fragGravity * dt
+		p.x += p.vx * dt
+		p.y += p.vy * dt
+		out = append(out, p)
+	}
+	e.parts = out
+
+	e.flash = math.Max(0, e.flash-dt)
+
+	// Age the trail and drop expired points (they are ordered newest first).
+	for i := 0; i < e.nTail; i++ {
+		e.trail[i].age += dt
+		if e.trail[i].age >= trailTTL {
+			e.nTail = i
+			break
+		}
+	}
+	// Record a point whenever the ball enters a new cell.
+	cx, cy := math.Floor(bx), math.Floor(by)
+	if e.nTail == 0 || e.trail[0].x != cx || e.trail[0].y != cy {
+		copy(e.trail[1:], e.trail[:trailLen-1])
+		e.trail[0] = trailPoint{x: cx, y: cy}
+		e.nTail = min(e.nTail+1, trailLen)
+	}
+}
+
+// draw fills l with the current effects for the view of s.
+func (e *effects) draw(l *fxLayer, p *palette, s *game.State) {
+	l.resize(s.ViewW, s.Height)
+	vx := s.ViewLeft()
+
+	// Skip trail[0]: it is the ball's own cell.
+	for i := e.nTail - 1; i >= 1; i-- {
+		t := e.trail[i]
+		level := min(int(t.age/trailTTL*float64(len(p.trailCells))), len(p.trailCells)-1)
+		l.put(int(t.x)-vx, int(t.y), p.trailCells[level])
+	}
+	for i := range e.parts {
+		pt := &e.parts[i]
+		l.put(int(math.Floor(pt.x))-vx, int(math.Floor(pt.y)), p.fragCells[pt.shade][pt.glyph])
+	}
+	l.paddleFlash = e.flash > 0
+}
+
+// active reports whether anything is left to draw.
+func (e *effects) active() bool {
+	return len(e.parts) > 0 || e.nTail > 1 || e.flash > 0
+}
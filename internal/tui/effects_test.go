@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+func TestEffects_Handle(t *testing.T) {
+	t.Parallel()
+
+	s := benchState()
+	br := s.Bricks[0]
+	tests := []struct {
+		name  string
+		ev    game.Event
+		parts int
+		shade int
+		flash bool
+	}{
+		// A destroyed brick last showed the HP 1 shade.
+		{"brick destroyed", game.Event{Kind: game.EventBrickDestroyed, Brick: 0}, 6 + 2*br.W*br.H, 1, false},
+		// A hit chips off the shade the brick had before it.
+		{"brick hit to 1 HP", game.Event{Kind: game.EventBrickHit, HPLeft: 1, X: 3, Y: 4}, 2, 2, false},
+		{"brick hit to 3 HP", game.Event{Kind: game.EventBrickHit, HPLeft: 3, X: 3, Y: 4}, 2, 4, false},
+		{"boss hit", game.Event{Kind: game.EventBossHit, X: 3, Y: 4}, 4, 4, false},
+		{"wall bounce", game.Event{Kind: game.EventWallBounce, X: 0, Y: 4}, 1, 1, false},
+		{"paddle hit", game.Event{Kind: game.EventPaddleHit}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		var e effects
+		e.handle([]game.Event{tt.ev}, &s, rand.New(rand.NewPCG(1, 2)))
+		if len(e.parts) != tt.parts {
+			t.Fatalf("%s: %d fragments, want %d", tt.name, len(e.parts), tt.parts)
+		}
+		for _, p := range e.parts {
+			if p.shade != tt.shade || p.glyph < 0 || p.glyph >= len(fragGlyphs) {
+				t.Fatalf("%s: fragment shade %d glyph %d, want shade %d", tt.name, p.shade, p.glyph, tt.shade)
+			}
+		}
+		if (e.flash > 0) != tt.flash || e.active() != (tt.parts > 0 || tt.flash) {
+			t.Fatalf("%s: flash %v, active %t", tt.name, e.flash, e.active())
+		}
+	}
+}
+
+func TestEffects_Expire(t *testing.T) {
+	t.Parallel()
+
+	const dt = 0.05
+	tests := []struct {
+		name  string
+		setup func(e *effects)
+		// The effect must still run at alive seconds and be gone by gone.
+		alive, gone float64
+		n           func(e *effects) int
+	}{
+		{"fragments", func(e *effects) {
+			e.burst(10, 10, 2, 8, 10, rand.New(rand.NewPCG(3, 4)))
+		}, 0.3, 0.7, func(e *effects) int { return len(e.parts) }},
+		{"trail", func(e *effects) {
+			for i := range trailLen {
+				e.update(0, 10+float64(i), 10)
+			}
+		}, 0.2, trailTTL, func(e *effects) int { return e.nTail - 1 }},
+		{"flash", func(e *effects) {
+			e.handle([]game.Event{{Kind: game.EventPaddleHit}}, nil, nil)
+		}, 0.1, flashTTL, func(e *effects) int {
+			if e.flash > 0 {
+				return 1
+			}
+			return 0
+		}},
+	}
+	for _, tt := range tests {
+		var e effects
+		tt.setup(&e)
+		prev := tt.n(&e)
+		if prev == 0 || !e.active() {
+			t.Fatalf("%s: nothing to expire", tt.name)
+		}
+		age := 0.0
+		for age < tt.gone {
+			// The ball stays in the newest trail cell.
+			e.update(dt, 10+trailLen-1, 10)
+			age += dt
+			n := tt.n(&e)
+			if n > prev || (n == 0 && age <= tt.alive) {
+				t.Fatalf("%s: %d left (was %d) at %.2fs", tt.name, n, prev, age)
+			}
+			prev = n
+		}
+		if n := tt.n(&e); n != 0 || e.active() {
+			t.Fatalf("%s: %d left and active %t after %.2fs", tt.name, n, e.active(), age)
+		}
+	}
+
+	// Fragments age and fall while they live.
+	var e effects
+	e.parts = []particle{{x: 5, y: 5, ttl: 1}}
+	e.update(0.1, 0, 0)
+	if p := e.parts[0]; p.age != 0.1 || p.y <= 5 || p.vy <= 0 {
+		t.Fatalf("fragment should age and fall: %+v", p)
+	}
+}
+
+func TestEffects_Draw(t *testing.T) {
+	t.Parallel()
+
+	s := benchState()
+	p := newPalette(BuiltinThemes()[0], GlyphsOff)
+	vx := s.ViewLeft()
+
+	var e effects
+	e.parts = []particle{
+		{x: float64(vx+3) + 0.5, y: 4.5, ttl: 1, shade: 2, glyph: 1},
+		{x: float64(vx+7) + 0.5, y: 6.2, ttl: 1, shade: 4, glyph: 0},
+		// Off the view: dropped rather than wrapped onto another row.
+		{x: float64(vx - 1), y: 5, ttl: 1, shade: 1},
+		{x: float64(vx + s.ViewW), y: 5, ttl: 1, shade: 1},
+		{x: float64(vx+2) + 0.5, y: float64(s.Height) + 1, ttl: 1, shade: 1},
+	}
+	// Newest first; the ball's own cell (trail[0]) is not drawn.
+	e.trail[0] = trailPoint{x: float64(vx + 12), y: 9}
+	e.trail[1] = trailPoint{x: float64(vx + 11), y: 9}
+	e.trail[2] = trailPoint{x: float64(vx + 10), y: 9, age: trailTTL * 0.9}
+	e.nTail = 3
+	e.flash = flashTTL
+
+	var l fxLayer
+	// Leftovers from a previous frame must be cleared.
+	l.resize(s.ViewW, s.Height)
+	l.put(0, 0, "stale")
+	e.draw(&l, p, &s)
+
+	want := map[[2]int]string{
+		{3, 4}:  p.fragCells[2][1],
+		{7, 6}:  p.fragCells[4][0],
+		{11, 9}: p.trailCells[0],
+		{10, 9}: p.trailCells[len(p.trailCells)-1],
+	}
+	if l.w != s.ViewW || l.h != s.Height || !l.paddleFlash {
+		t.Fatalf("layer %dx%d flash %t, want %dx%d with a flash", l.w, l.h, l.paddleFlash, s.ViewW, s.Height)
+	}
+	for y := range l.h {
+		for x := range l.w {
+			if got := l.cells[y*l.w+x]; got != want[[2]int{x, y}] {
+				t.Fatalf("cell (%d,%d) = %q, want %q", x, y, got, want[[2]int{x, y}])
+			}
+		}
+		n := 0
+		for c := range want {
+			if c[1] == y {
+				n++
+			}
+		}
+		if (l.row(y) != nil) != (n > 0) || l.rowN[y] != n {
+			t.Fatalf("row %d: %d cells set, want %d", y, l.rowN[y], n)
+		}
+	}
+
+	// Once everything has expired the next frame draws nothing.
+	e.update(1, float64(vx+12), 9)
+	e.draw(&l, p, &s)
+	if e.active() || len(l.set) != 0 || l.paddleFlash {
+		t.Fatalf("expired effects should leave an empty layer: %d cells, flash %t", len(l.set), l.paddleFlash)
+	}
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Glyphs selects whether brick HP is also drawn as a character, so it stays
+// readable without relying on color.
+type Glyphs int
+
+const (
+	// GlyphsOff draws bricks as colored blanks (default).
+	GlyphsOff Glyphs = iota
+	// GlyphsShade draws bricks as ░▒▓█ (HP 1..4) in the brick color.
+	GlyphsShade
+	// GlyphsDigits draws the HP digit on the brick color.
+	GlyphsDigits
+)
+
+// GlyphNames lists the values accepted by ParseGlyphs, in Glyphs order.
+var GlyphNames = []string{"off", "shade", "digits"}
+
+func (g Glyphs) String() string {
+	if g < 0 || int(g) >= len(GlyphNames) {
+		return fmt.Sprintf("Glyphs(%d)", int(g))
+	}
+	return GlyphNames[g]
+}
+
+// ParseGlyphs resolves a glyph mode by name. An empty name selects GlyphsOff.
+func ParseGlyphs(name string) (Glyphs, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return GlyphsOff, nil
+	}
+	for i, n := range GlyphNames {
+		if n == name {
+			return Glyphs(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown glyph mode %q (expected one of: %s)", name, strings.Join(GlyphNames, ", "))
+}
+
+var shadeGlyphs = [5]string{" ", "░", "▒", "▓", "█"}
+
+// glyph returns the character drawn for one brick cell with the given HP (1..4).
+func (g Glyphs) glyph(hp int) string {
+	switch g {
+	case GlyphsShade:
+		return shadeGlyphs[hp]
+	case GlyphsDigits:
+		return strconv.Itoa(hp)
+	default:
+		return " "
+	}
+}
+
+// overlayGlyph are the characters of an overlay panel: the border of alerts
+// (pause, game over), the border of celebrations (CLEAR, winners) and the
+// panel fill.
+type overlayGlyph struct {
+	box, celebrateBox boxChars
+	fill              string
+}
+
+// overlayGlyphs are the overlay characters of each glyph mode. Without glyphs
+// the border color tells a celebration apart; with them the border does too.
+var overlayGlyphs = [...]overlayGlyph{
+	GlyphsOff:    {box: roundBox, celebrateBox: roundBox, fill: " "},
+	GlyphsShade:  {box: roundBox, celebrateBox: doubleBox, fill: " "},
+	GlyphsDigits: {box: roundBox, celebrateBox: doubleBox, fill: " "},
+}
+
+// contrastText returns black or white, whichever reads better on the hex color bg.
+func contrastText(bg string) string {
+	v, err := strconv.ParseUint(strings.TrimPrefix(bg, "#"), 16, 32)
+	if err != nil {
+		return "#ffffff"
+	}
+	r, g, b := float64(v>>16&0xff), float64(v>>8&0xff), float64(v&0xff)
+	// Rec. 601 luma is close enough for picking a text color.
+	if 0.299*r+0.587*g+0.114*b > 140 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
@@ -0,0 +1,92 @@
+package tui
+
+// Most terminals only report key presses and auto-repeats, never releases, so
+// a movement key counts as held until no repeat arrives in time. Terminals
+// speaking the Kitty keyboard protocol also report releases (see
+// KittyKeyboard); once one arrives, keys hold exactly until released.
+const (
+	// firstHold is how long one press holds its direction. It spans most of
+	// the terminal's initial repeat delay (typically 250-600ms), so a held key
+	// keeps moving until the repeats take over; the price is that a tap glides
+	// a little further.
+	firstHold = 0.3
+	// repeatWindow is how late the first auto-repeat may arrive after a press and
+	// still count as the same hold.
+	repeatWindow = 0.6
+	// repeatHold keeps the direction held between auto-repeats (~30Hz, slower
+	// on some terminals and over SSH).
+	repeatHold = 0.1
+)
+
+// keyHold tracks the held movement direction.
+type keyHold struct {
+	dir       int
+	left      float64 // seconds until the hold expires
+	sincePush float64 // seconds since the last press of dir
+	repeating bool
+
+	// exact is set once the terminal has reported a release. Presses then
+	// hold until released, and down records which directions are held (left,
+	// right) for when the newer one is released first.
+	exact bool
+	down  [2]bool
+}
+
+// press records a press (or auto-repeat) of direction dir.
+func (h *keyHold) press(dir int) {
+	if h.exact {
+		h.dir = dir
+		h.down[(dir+1)/2] = true
+		return
+	}
+	if dir == h.dir && h.sincePush <= repeatWindow {
+		h.repeating = true
+		h.left = max(h.left, repeatHold)
+	} else {
+		h.dir = dir
+		h.repeating = false
+		h.left = firstHold
+	}
+	h.sincePush = 0
+}
+
+// advance ages the hold by dt seconds and returns the held direction for that
+// time. A hold that was only a tap keeps its direction until repeatWindow runs
+// out, but stops steering after firstHold.
+func (h *keyHold) advance(dt float64) int {
+	if h.dir == 0 || h.exact {
+		return h.dir
+	}
+	h.sincePush += dt
+	h.left -= dt
+	if h.left > 0 {
+		return h.dir
+	}
+	h.left = 0
+	if h.repeating || h.sincePush > repeatWindow {
+		h.reset()
+	}
+	return 0
+}
+
+// release records a release of direction dir. It ends the hold at once, or
+// hands it back to the other direction if that is still held.
+func (h *keyHold) release(dir int) {
+	if !h.exact {
+		// The first release: from now on presses hold until released.
+		*h = keyHold{exact: true}
+		return
+	}
+	h.down[(dir+1)/2] = false
+	if h.dir == dir {
+		h.dir = 0
+		if other := -dir; h.down[(other+1)/2] {
+			h.dir = other
+		}
+	}
+}
+
+// reset drops the hold; whether releases are reported is kept.
+func (h *keyHold) reset() {
+	*h = keyHold{exact: h.exact}
+}
@@ -0,0 +1,102 @@
+package tui
+
+import "testing"
+
+// holdTick is one tick at 60 FPS; advancing a keyHold by it is the fake clock.
+const holdTick = 1.0 / 60
+
+// heldFor advances h by secs and returns how long it held dir.
+func heldFor(h *keyHold, dir int, secs float64) float64 {
+	held := 0.0
+	for t := 0.0; t < secs-1e-9; t += holdTick {
+		if h.advance(holdTick) == dir {
+			held += holdTick
+		}
+	}
+	return held
+}
+
+func TestKeyHold_PressRepeatRelease(t *testing.T) {
+	t.Parallel()
+
+	// The terminal waits 0.25s before repeating, then repeats at 30Hz.
+	var h keyHold
+	h.press(1)
+	if got := heldFor(&h, 1, 0.25); got < 0.25-1e-9 {
+		t.Fatalf("the initial repeat delay should not stop the paddle, held %.3fs of 0.25s", got)
+	}
+	for range 30 {
+		h.press(1)
+		if got := heldFor(&h, 1, 1.0/30); got < 1.0/30-1e-9 {
+			t.Fatalf("auto-repeats should hold continuously, held %.3fs of %.3fs", got, 1.0/30)
+		}
+	}
+
+	// Released: no more repeats. The hold runs out within repeatHold.
+	if got := heldFor(&h, 1, 0.5); got > repeatHold+1e-9 {
+		t.Fatalf("a released key should stop within %.2fs, held %.3fs", repeatHold, got)
+	}
+	if h.dir != 0 {
+		t.Fatalf("the hold should be cleared after release, got %+v", h)
+	}
+}
+
+func TestKeyHold_Tap(t *testing.T) {
+	t.Parallel()
+
+	var h keyHold
+	h.press(-1)
+	if got := heldFor(&h, -1, 1); got < firstHold-1e-9 || got > firstHold+holdTick {
+		t.Fatalf("a tap should hold for %.2fs, held %.3fs", firstHold, got)
+	}
+	if h.dir != 0 {
+		t.Fatalf("the tap should expire after repeatWindow, got %+v", h)
+	}
+
+	// A press of the other direction reverses at once.
+	h.press(-1)
+	heldFor(&h, -1, 0.1)
+	h.press(1)
+	if got := h.advance(holdTick); got != 1 {
+		t.Fatalf("the opposite key should reverse immediately, got %d", got)
+	}
+}
+
+func TestKeyHold_ReleaseEvents(t *testing.T) {
+	t.Parallel()
+
+	// The first release ends the heuristic hold at once.
+	var h keyHold
+	h.press(1)
+	heldFor(&h, 1, 0.1)
+	h.release(1)
+	if got := h.advance(holdTick); got != 0 || !h.exact {
+		t.Fatalf("a release should stop the paddle and switch to exact holds, got %d, %+v", got, h)
+	}
+
+	// From then on a press holds, without repeats, until released.
+	h.press(-1)
+	if got := heldFor(&h, -1, 2); got < 2-1e-9 {
+		t.Fatalf("a pressed key should hold until released, held %.3fs of 2s", got)
+	}
+	// Pressing the other way while still holding, then letting go of it,
+	// hands the hold back.
+	h.press(1)
+	if got := h.advance(holdTick); got != 1 {
+		t.Fatalf("the newer key should win, got %d", got)
+	}
+	h.release(1)
+	if got := h.advance(holdTick); got != -1 {
+		t.Fatalf("the key still held should take over, got %d", got)
+	}
+	h.release(-1)
+	if got := h.advance(holdTick); got != 0 {
+		t.Fatalf("releasing every key should stop the paddle, got %d", got)
+	}
+
+	h.press(1)
+	h.reset()
+	if !h.exact || h.advance(holdTick) != 0 {
+		t.Fatalf("reset should drop the hold but remember releases, got %+v", h)
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// cursorCells renders the inspect cursor pieces (see palette.cursorCells).
+func cursorCells(st lipgloss.Style) [4]string {
+	return [4]string{st.Render("["), st.Render(" "), st.Render("]"), st.Render("#")}
+}
+
+// dayRange formats the days a brick covers: one date, or first..last when weeks
+// were merged.
+func dayRange(first, last string) string {
+	switch {
+	case first == "" && last == "":
+		return "unknown day"
+	case first == "" || first == last:
+		return last
+	case last == "":
+		return first
+	default:
+		return first + ".." + last
+	}
+}
+
+func contributions(n int) string {
+	if n == 1 {
+		return "1 contribution"
+	}
+	return fmt.Sprintf("%d contributions", n)
+}
+
+// brokeNotice is the info line shown when a brick is destroyed.
+func brokeNotice(ev game.Event) string {
+	return fmt.Sprintf("Broke %s (%s)", dayRange(ev.First, ev.Last), contributions(ev.Count))
+}
+
+// inspectCursor is a position on the brick grid. Inspect mode pauses the game
+// and moves it with the movement keys.
+type inspectCursor struct {
+	row, col int
+	// set is false until a brick was destroyed or the cursor was moved, so the
+	// first inspect starts on the most recent week.
+	set bool
+}
+
+// enterInspect pauses the game and shows the cursor.
+func (m *Model) enterInspect() {
+	if !m.ready || m.introActive || m.noBricks || m.bossStage {
+		return
+	}
+	if !m.cursor.set {
+		m.cursor = inspectCursor{row: m.state.BrickRows / 2, col: m.state.BrickCols - 1, set: true}
+	}
+	m.moveCursor(0, 0)
+	m.inspecting = true
+	m.hold.reset()
+	m.acc = 0
+}
+
+// moveCursor moves the cursor by (dr, dc) cells, clamped to the grid, and pans
+// a scrolling board so the cursor stays visible.
+func (m *Model) moveCursor(dr, dc int) {
+	s := &m.state
+	m.cursor.row = min(max(m.cursor.row+dr, 0), max(s.BrickRows-1, 0))
+	m.cursor.col = min(max(m.cursor.col+dc, 0), max(s.BrickCols-1, 0))
+	m.cursor.set = true
+	if s.ViewW >= s.Width || m.cursor.col >= len(s.ColX) {
+		return
+	}
+	x := s.ColX[m.cursor.col]
+	if x < s.ViewLeft() {
+		s.ViewX = float64(x)
+	} else if x+s.BrickW > s.ViewLeft()+s.ViewW {
+		s.ViewX = float64(x + s.BrickW - s.ViewW)
+	}
+}
+
+// inspectLine describes the grid cell under the cursor.
+func (m *Model) inspectLine() string {
+	s := &m.state
+	r, c := m.cursor.row, m.cursor.col
+	hint := "  (" + m.help.move(m.pal.arrows) + " move, " + m.help.key(actionInspect) + " back)"
+	if r >= m.grid.Rows || c >= m.grid.Cols || c >= len(s.ColX) {
+		return "inspect" + hint
+	}
+	cell := m.grid.Cells[r][c]
+	text := dayRange(cell.First, cell.Last) + ": " + contributions(cell.Count)
+	if cell.Streak {
+		text += ", streak"
+	}
+	if i := s.BrickAt(s.ColX[c], s.TopOffset+r*s.BrickH); i >= 0 {
+		if br := &s.Bricks[i]; br.HP > 0 {
+			text += fmt.Sprintf(", HP %d/%d", br.HP, br.MaxHP)
+		} else {
+			text += ", broken"
+		}
+	}
+	return text + hint
+}
+
+// drawCursor adds the cursor to l (already sized for the view of s).
+func (m *Model) drawCursor(l *fxLayer) {
+	s := &m.state
+	if m.cursor.col >= len(s.ColX) {
+		return
+	}
+	x0 := s.ColX[m.cursor.col] - s.ViewLeft()
+	y0 := s.TopOffset + m.cursor.row*s.BrickH
+	cells := &m.pal.cursorCells
+	for y := y0; y < y0+s.BrickH; y++ {
+		if s.BrickW == 1 {
+			l.put(x0, y, cells[3])
+			continue
+		}
+		for x := 0; x < s.BrickW; x++ {
+			switch x {
+			case 0:
+				l.put(x0+x, y, cells[0])
+			case s.BrickW - 1:
+				l.put(x0+x, y, cells[2])
+			default:
+				l.put(x0+x, y, cells[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+func TestDayRange(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct{ first, last, want string }{
+		{"2025-03-14", "2025-03-14", "2025-03-14"},
+		{"2025-03-09", "2025-03-16", "2025-03-09..2025-03-16"},
+		{"", "2025-03-14", "2025-03-14"},
+		{"", "", "unknown day"},
+	}
+	for _, tc := range cases {
+		if got := dayRange(tc.first, tc.last); got != tc.want {
+			t.Fatalf("dayRange(%q, %q) = %q, want %q", tc.first, tc.last, got, tc.want)
+		}
+	}
+	ev := game.Event{Kind: game.EventBrickDestroyed, Count: 23, First: "2025-03-14", Last: "2025-03-14"}
+	if got := brokeNotice(ev); got != "Broke 2025-03-14 (23 contributions)" {
+		t.Fatalf("unexpected notice %q", got)
+	}
+}
+
+// inspectCalendar has two weeks from Sunday 2025-03-02 where each day's count
+// is its day of month.
+func inspectCalendar() github.Calendar {
+	var cal github.Calendar
+	for w := range 2 {
+		var week github.Week
+		for d := range 7 {
+			day := 2 + w*7 + d
+			count := day
+			if day == 5 || day == 13 {
+				// Leaves 03-06..03-12 as the longest streak.
+				count = 0
+			}
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Date:              fmt.Sprintf("2025-03-%02d", day),
+				Weekday:           d,
+				ContributionCount: count,
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	return cal
+}
+
+func key(s string) tea.KeyMsg {
+	if s == "esc" {
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestInspect_CursorShowsDateAndCount(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true})
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	m.introActive = false
+
+	m.Update(key("i"))
+	if !m.inspecting {
+		t.Fatalf("i should enter inspect mode")
+	}
+	// The cursor starts on the most recent week, mid-week: Wednesday 2025-03-12.
+	if got := m.inspectLine(); !strings.HasPrefix(got, "2025-03-12: 12 contributions, streak, HP") {
+		t.Fatalf("unexpected inspect line %q", got)
+	}
+	m.Update(key("h"))
+	m.Update(key("k"))
+	if got := m.inspectLine(); !strings.HasPrefix(got, "2025-03-04: 4 contributions, HP") {
+		t.Fatalf("cursor should move a week back and a day up, got %q", got)
+	}
+	if v := m.View(); !strings.Contains(v, "2025-03-04: 4 contributions") || !strings.Contains(v, "[ ]") {
+		t.Fatalf("view should show the inspect line and the cursor:\n%s", v)
+	}
+
+	// The game is paused while inspecting.
+	ballY := m.state.BallY
+	m.lastTick = time.Unix(0, 0)
+	m.Update(tickMsg(time.Unix(0, 0).Add(time.Second / 30)))
+	if m.state.BallY != ballY {
+		t.Fatalf("the ball should not move while inspecting")
+	}
+
+	m.Update(key("esc"))
+	if m.inspecting {
+		t.Fatalf("esc should leave inspect mode")
+	}
+}
+
+func TestInspect_BrokeNoticeMovesCursor(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true})
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+
+	m.handleEvents([]game.Event{{Kind: game.EventBrickDestroyed, Row: 5, Col: 0, Count: 7, First: "2025-03-07", Last: "2025-03-07"}})
+	if m.notice != "Broke 2025-03-07 (7 contributions)" {
+		t.Fatalf("unexpected notice %q", m.notice)
+	}
+	m.introActive = false
+	m.Update(key("i"))
+	if got := m.inspectLine(); !strings.HasPrefix(got, "2025-03-07: 7 contributions") {
+		t.Fatalf("inspect should start at the last broken brick, got %q", got)
+	}
+}
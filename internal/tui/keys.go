@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"cmp"
+	"strings"
+)
+
+// KeyMap lists the key names (as reported by tea.KeyMsg.String) bound to each action.
+type KeyMap struct {
+	Left      []string
+	Right     []string
+	Retry     []string
+	Quit      []string
+	SpeedUp   []string
+	SpeedDown []string
+	Theme     []string
+	Launch    []string
+	// Inspect toggles inspect mode; Up and Down only move its cursor (Left and
+	// Right move it sideways).
+	Inspect []string
+	Up      []string
+	Down    []string
+	// Boss starts the boss stage from the CLEAR screen.
+	Boss []string
+}
+
+// DefaultKeyMap returns the built-in bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Left:      []string{"left", "h", "a", "H", "A"},
+		Right:     []string{"right", "l", "d", "L", "D"},
+		Retry:     []string{"r", "R"},
+		Quit:      []string{"q"},
+		SpeedUp:   []string{"+", "="},
+		SpeedDown: []string{"-", "_"},
+		Theme:     []string{"t", "T"},
+		Launch:    []string{" ", "enter"},
+		Inspect:   []string{"i", "I"},
+		Up:        []string{"up", "k", "w", "K", "W"},
+		Down:      []string{"down", "j", "s", "J", "S"},
+		Boss:      []string{"b", "B"},
+	}
+}
+
+type keyAction int
+
+const (
+	actionNone keyAction = iota
+	actionLeft
+	actionRight
+	actionRetry
+	actionQuit
+	actionSpeedUp
+	actionSpeedDown
+	actionTheme
+	actionLaunch
+	actionInspect
+	actionUp
+	actionDown
+	actionBoss
+)
+
+// actionKeys lists the keys of each action in lookup order: a key bound to
+// several actions goes to the first. Empty action lists fall back to the defaults.
+func (k KeyMap) actionKeys() []actionKeys {
+	def := DefaultKeyMap()
+	pick := func(keys, fallback []string) []string {
+		if len(keys) == 0 {
+			return fallback
+		}
+		return keys
+	}
+	return []actionKeys{
+		{actionQuit, pick(k.Quit, def.Quit)},
+		{actionRetry, pick(k.Retry, def.Retry)},
+		{actionLeft, pick(k.Left, def.Left)},
+		{actionRight, pick(k.Right, def.Right)},
+		{actionSpeedUp, pick(k.SpeedUp, def.SpeedUp)},
+		{actionSpeedDown, pick(k.SpeedDown, def.SpeedDown)},
+		{actionTheme, pick(k.Theme, def.Theme)},
+		{actionLaunch, pick(k.Launch, def.Launch)},
+		{actionInspect, pick(k.Inspect, def.Inspect)},
+		{actionUp, pick(k.Up, def.Up)},
+		{actionDown, pick(k.Down, def.Down)},
+		{actionBoss, pick(k.Boss, def.Boss)},
+	}
+}
+
+type actionKeys struct {
+	action keyAction
+	keys   []string
+}
+
+// bindings flattens the map into a lookup table. ctrl+c always quits so a bad
+// config can't trap the user.
+func (k KeyMap) bindings() map[string]keyAction {
+	out := make(map[string]keyAction)
+	out["ctrl+c"] = actionQuit
+	for _, ak := range k.actionKeys() {
+		for _, key := range ak.keys {
+			if _, ok := out[key]; !ok {
+				out[key] = ak.action
+			}
+		}
+	}
+	return out
+}
+
+// keyHelp names the keys of a key map for help text, so the hints follow the
+// user's bindings.
+type keyHelp struct {
+	// names holds the first key that triggers each action.
+	names map[keyAction]string
+	// moves pairs the Left and Right keys, e.g. "a/d"; "" stands for the arrow
+	// keys, which the palette names.
+	moves []string
+}
+
+// help resolves the names of k's keys for help text.
+func (k KeyMap) help() keyHelp {
+	bound := k.bindings()
+	h := keyHelp{names: make(map[keyAction]string)}
+	var left, right []string
+	for _, ak := range k.actionKeys() {
+		for _, key := range ak.keys {
+			if bound[key] != ak.action {
+				continue
+			}
+			if _, ok := h.names[ak.action]; !ok {
+				h.names[ak.action] = keyName(key)
+			}
+			switch ak.action {
+			case actionLeft:
+				left = append(left, key)
+			case actionRight:
+				right = append(right, key)
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for i := range min(len(left), len(right)) {
+		pair := keyName(left[i]) + "/" + keyName(right[i])
+		if left[i] == "left" && right[i] == "right" {
+			pair = ""
+		}
+		// Skip the upper-case twins of bound keys.
+		if seen[strings.ToLower(pair)] {
+			continue
+		}
+		seen[pair] = true
+		h.moves = append(h.moves, pair)
+	}
+	return h
+}
+
+// keyName is how help text shows a key.
+func keyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+// key names the key of action a, or "?" when every key of a was taken by an
+// earlier action.
+func (h keyHelp) key(a keyAction) string {
+	if name, ok := h.names[a]; ok {
+		return name
+	}
+	return "?"
+}
+
+// move names the paddle keys, e.g. "←/→ a/d"; arrows names the arrow keys.
+func (h keyHelp) move(arrows string) string {
+	if len(h.moves) == 0 {
+		return h.key(actionLeft) + "/" + h.key(actionRight)
+	}
+	names := make([]string, len(h.moves))
+	for i, pair := range h.moves {
+		names[i] = cmp.Or(pair, arrows)
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyHelp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		keys KeyMap
+		want map[keyAction]string
+		move string
+	}{
+		{"defaults", KeyMap{}, map[keyAction]string{actionRetry: "r", actionLaunch: "space", actionSpeedUp: "+"}, "arrows h/l a/d"},
+		{"remapped", KeyMap{Left: []string{"z"}, Right: []string{"x"}, Retry: []string{"n"}}, map[keyAction]string{actionRetry: "n", actionQuit: "q"}, "z/x"},
+		// Quit wins "r", so retry falls back to its next key.
+		{"shared key", KeyMap{Quit: []string{"r"}}, map[keyAction]string{actionQuit: "r", actionRetry: "R"}, "arrows h/l a/d"},
+		{"unbound", KeyMap{Quit: []string{"t"}, Theme: []string{"t"}}, map[keyAction]string{actionTheme: "?"}, "arrows h/l a/d"},
+	}
+	for _, tt := range tests {
+		h := tt.keys.help()
+		for a, want := range tt.want {
+			if got := h.key(a); got != want {
+				t.Errorf("%s: key(%d) = %q, want %q", tt.name, a, got, want)
+			}
+		}
+		if got := h.move("arrows"); got != tt.move {
+			t.Errorf("%s: move = %q, want %q", tt.name, got, tt.move)
+		}
+	}
+}
+
+func TestView_HelpFollowsTheKeyMap(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true, Keys: KeyMap{
+		Left: []string{"z"}, Right: []string{"x"}, Retry: []string{"n"}, Quit: []string{"esc"},
+	}})
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	m.introActive = false
+	v := m.View()
+	for _, want := range []string{"(z/x, n retry, +/- speed, t theme, i inspect, esc quit)", "press n to retry (restart), esc to quit"} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("view should name the configured keys in %q:\n%s", want, v)
+		}
+	}
+}
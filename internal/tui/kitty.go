@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Kitty keyboard protocol escape sequences
+// (https://sw.kovidgoyal.net/kitty/keyboard-protocol/). The query is answered
+// with CSI ? flags u only by terminals that speak the protocol; the others
+// ignore it. kittyPush turns on disambiguated escape codes (1) and event types
+// (2), which adds repeat and release reports.
+const (
+	kittyQuery = "\x1b[?u"
+	kittyPush  = "\x1b[>3u"
+	kittyPop   = "\x1b[<u"
+)
+
+// keyReleaseMsg reports a released key. Only terminals speaking the Kitty
+// keyboard protocol send them.
+type keyReleaseMsg tea.Key
+
+func (k keyReleaseMsg) String() string {
+	return tea.KeyMsg(k).String()
+}
+
+// kittyFlagsMsg is the terminal's answer to kittyQuery.
+type kittyFlagsMsg int
+
+// KittyKeyboard reads key release events from terminals that speak the Kitty
+// keyboard protocol. Bubble Tea v1 does not parse the protocol, so Filter, run
+// as the program's message filter, asks the terminal whether it supports it,
+// turns it on when it does, and translates its key reports into key messages.
+// Elsewhere nothing changes and held keys rely on auto-repeats (see keyHold).
+type KittyKeyboard struct {
+	out       io.Writer // the program's output
+	queried   bool
+	enabled   bool
+	suspended bool
+}
+
+// NewKittyKeyboard returns a KittyKeyboard writing its escape sequences to
+// out, the terminal the program draws on.
+func NewKittyKeyboard(out io.Writer) *KittyKeyboard {
+	return &KittyKeyboard{out: out}
+}
+
+// Filter is a tea.WithFilter filter. It queries the terminal on the first
+// message, once the program has put the terminal in raw mode, and turns the
+// protocol off again before the program quits or suspends.
+func (k *KittyKeyboard) Filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	if !k.queried {
+		k.queried = true
+		io.WriteString(k.out, kittyQuery)
+	}
+	switch msg.(type) {
+	case tea.QuitMsg, tea.InterruptMsg:
+		k.Stop()
+		return msg
+	case tea.SuspendMsg:
+		k.suspended = k.enabled
+		k.Stop()
+		return msg
+	case tea.ResumeMsg:
+		if k.suspended {
+			k.suspended = false
+			k.start()
+		}
+		return msg
+	}
+	b, ok := unknownCSI(msg)
+	if !ok {
+		return msg
+	}
+	km, ok := parseKitty(b)
+	if !ok {
+		return msg
+	}
+	if _, ok := km.(kittyFlagsMsg); ok {
+		if !k.enabled {
+			k.start()
+		}
+		return nil
+	}
+	return km
+}
+
+func (k *KittyKeyboard) start() {
+	k.enabled = true
+	io.WriteString(k.out, kittyPush)
+}
+
+// Stop turns the protocol off if Filter turned it on. It is safe to call again
+// after the program has exited.
+func (k *KittyKeyboard) Stop() {
+	if k.enabled {
+		k.enabled = false
+		io.WriteString(k.out, kittyPop)
+	}
+}
+
+// unknownCSI returns the bytes of a CSI sequence Bubble Tea did not recognize.
+// It reports them as an unexported []byte type, so reflection is the only way
+// to read them.
+func unknownCSI(msg tea.Msg) ([]byte, bool) {
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 ||
+		v.Type().PkgPath() != reflect.TypeOf(tea.KeyMsg{}).PkgPath() {
+		return nil, false
+	}
+	b := v.Bytes()
+	return b, len(b) > 2 && b[0] == '\x1b' && b[1] == '['
+}
+
+// Kitty modifier bits; the protocol sends them plus one.
+const (
+	kittyShift = 1 << iota
+	kittyAlt
+	kittyCtrl
+)
+
+// kittyCursorKeys are the keys reported as CSI 1;mods:event letter, by
+// modifier: none, shift, ctrl and ctrl+shift.
+var kittyCursorKeys = map[byte][4]tea.KeyType{
+	'A': {tea.KeyUp, tea.KeyShiftUp, tea.KeyCtrlUp, tea.KeyCtrlShiftUp},
+	'B': {tea.KeyDown, tea.KeyShiftDown, tea.KeyCtrlDown, tea.KeyCtrlShiftDown},
+	'C': {tea.KeyRight, tea.KeyShiftRight, tea.KeyCtrlRight, tea.KeyCtrlShiftRight},
+	'D': {tea.KeyLeft, tea.KeyShiftLeft, tea.KeyCtrlLeft, tea.KeyCtrlShiftLeft},
+	'H': {tea.KeyHome, tea.KeyShiftHome, tea.KeyCtrlHome, tea.KeyCtrlShiftHome},
+	'F': {tea.KeyEnd, tea.KeyShiftEnd, tea.KeyCtrlEnd, tea.KeyCtrlShiftEnd},
+}
+
+// kittyTildeKeys are the keys reported as CSI number;mods:event ~.
+var kittyTildeKeys = map[int]tea.KeyType{
+	2: tea.KeyInsert, 3: tea.KeyDelete, 5: tea.KeyPgUp, 6: tea.KeyPgDown,
+}
+
+// kittyTextKeys are the keys with a code point of their own that Bubble Tea
+// names rather than treats as text.
+var kittyTextKeys = map[int]tea.KeyType{
+	9: tea.KeyTab, 13: tea.KeyEnter, 27: tea.KeyEsc, 127: tea.KeyBackspace,
+}
+
+// parseKitty decodes b, a CSI sequence of the Kitty keyboard protocol: the
+// answer to kittyQuery, or a key press, repeat or release. Presses and
+// repeats become tea.KeyMsg, releases keyReleaseMsg. It reports false for
+// anything else, including keys Bubble Tea has no name for.
+func parseKitty(b []byte) (tea.Msg, bool) {
+	if len(b) < 3 {
+		return nil, false
+	}
+	body, final := string(b[2:len(b)-1]), b[len(b)-1]
+	if rest, ok := strings.CutPrefix(body, "?"); ok && final == 'u' {
+		flags, err := strconv.Atoi(rest)
+		return kittyFlagsMsg(flags), err == nil
+	}
+
+	// number[:alternates];mods[:event][;text]
+	fields := strings.Split(body, ";")
+	num, mods, event := 1, 1, 1
+	var err error
+	if code, _, _ := strings.Cut(fields[0], ":"); code != "" {
+		if num, err = strconv.Atoi(code); err != nil {
+			return nil, false
+		}
+	}
+	if len(fields) > 1 {
+		m, e, hasEvent := strings.Cut(fields[1], ":")
+		if m != "" {
+			if mods, err = strconv.Atoi(m); err != nil {
+				return nil, false
+			}
+		}
+		if hasEvent {
+			if event, err = strconv.Atoi(e); err != nil {
+				return nil, false
+			}
+		}
+	}
+	mods = max(mods-1, 0)
+
+	var k tea.Key
+	switch {
+	case final == 'u':
+		switch t, named := kittyTextKeys[num]; {
+		case named:
+			k.Type = t
+		case num == ' ':
+			k.Type, k.Runes = tea.KeySpace, []rune{' '}
+		case mods&kittyCtrl != 0 && num >= 'a' && num <= 'z':
+			k.Type = tea.KeyCtrlA + tea.KeyType(num-'a')
+		case num >= 0xe000 && num <= 0xf8ff:
+			// Functional keys in the private use area (keypad, modifiers, media).
+			return nil, false
+		default:
+			r := rune(num)
+			if mods&kittyShift != 0 {
+				r = unicode.ToUpper(r)
+			}
+			k.Type, k.Runes = tea.KeyRunes, []rune{r}
+		}
+	case final == '~':
+		t, ok := kittyTildeKeys[num]
+		if !ok {
+			return nil, false
+		}
+		k.Type = t
+	default:
+		keys, ok := kittyCursorKeys[final]
+		if !ok || num != 1 {
+			return nil, false
+		}
+		i := 0
+		if mods&kittyShift != 0 {
+			i |= 1
+		}
+		if mods&kittyCtrl != 0 {
+			i |= 2
+		}
+		k.Type = keys[i]
+	}
+	k.Alt = mods&kittyAlt != 0
+
+	switch event {
+	case 1, 2: // press, repeat
+		return tea.KeyMsg(k), true
+	case 3:
+		return keyReleaseMsg(k), true
+	}
+	return nil, false
+}
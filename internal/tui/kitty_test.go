@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseKitty(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		seq  string
+		want string // "press KEY", "release KEY", "flags N", or "" to ignore
+	}{
+		{"\x1b[?3u", "flags 3"},
+		{"\x1b[104;1:3u", "release h"},
+		{"\x1b[104;1:2u", "press h"},
+		{"\x1b[97;2:3u", "release A"},
+		{"\x1b[99;5u", "press ctrl+c"},
+		{"\x1b[27u", "press esc"},
+		{"\x1b[13;1:3u", "release enter"},
+		{"\x1b[32;1:3u", "release  "},
+		{"\x1b[113;3u", "press alt+q"},
+		{"\x1b[1;1:3D", "release left"},
+		{"\x1b[1;1:2C", "press right"},
+		{"\x1b[1;5:3D", "release ctrl+left"},
+		{"\x1b[5;1:2~", "press pgup"},
+		// Keys Bubble Tea has no name for, and other sequences.
+		{"\x1b[57441;2u", ""},
+		{"\x1b[15;1:3~", ""},
+		{"\x1b[2;1:3Z", ""},
+		{"\x1b[1;1:4D", ""},
+		{"\x1b[x;1u", ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if msg, ok := parseKitty([]byte(tt.seq)); ok {
+			switch msg := msg.(type) {
+			case tea.KeyMsg:
+				got = "press " + msg.String()
+			case keyReleaseMsg:
+				got = "release " + msg.String()
+			case kittyFlagsMsg:
+				got = fmt.Sprintf("flags %d", msg)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("parseKitty(%q) = %q, want %q", tt.seq[1:], got, tt.want)
+		}
+	}
+}
+
+// keyRecorder records the keys a program reads and quits on ctrl+c.
+type keyRecorder struct{ keys []string }
+
+func (r *keyRecorder) Init() tea.Cmd { return nil }
+
+func (r *keyRecorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		r.keys = append(r.keys, msg.String())
+		if msg.Type == tea.KeyCtrlC {
+			return r, tea.Quit
+		}
+	case keyReleaseMsg:
+		r.keys = append(r.keys, "-"+msg.String())
+	}
+	return r, nil
+}
+
+func (r *keyRecorder) View() string { return "" }
+
+func TestKittyKeyboard_Program(t *testing.T) {
+	t.Parallel()
+
+	// The terminal answers the query, then reports a press and release, an
+	// arrow key repeat and release, and ctrl+c as Kitty key reports.
+	in := strings.NewReader("\x1b[?0u" + "l" + "\x1b[108;1:3u" + "\x1b[1;1:2D" + "\x1b[1;1:3D" + "\x1b[99;5u")
+	var out bytes.Buffer
+	kb := NewKittyKeyboard(&out)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rec := &keyRecorder{}
+	p := tea.NewProgram(rec, tea.WithInput(in), tea.WithOutput(&out), tea.WithContext(ctx), tea.WithFilter(kb.Filter))
+	if _, err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	kb.Stop()
+
+	if got, want := strings.Join(rec.keys, " "), "l -l left -left ctrl+c"; got != want {
+		t.Fatalf("keys = %q, want %q", got, want)
+	}
+	o := out.String()
+	query, push, pop := strings.Index(o, kittyQuery), strings.Index(o, kittyPush), strings.Index(o, kittyPop)
+	if query < 0 || push < query || pop < push || strings.Count(o, kittyPop) != 1 {
+		t.Fatalf("expected the query, then one push and one pop, got %q", o)
+	}
+}
+
+func TestKittyKeyboard_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	// Without an answer the protocol stays off, and keys pass through.
+	var out bytes.Buffer
+	kb := NewKittyKeyboard(&out)
+	msg := tea.KeyMsg{Type: tea.KeyLeft}
+	if got := kb.Filter(nil, msg); got.(tea.KeyMsg).Type != tea.KeyLeft {
+		t.Fatalf("keys should pass through, got %v", got)
+	}
+	kb.Filter(nil, tea.QuitMsg{})
+	kb.Stop()
+	if out.String() != kittyQuery {
+		t.Fatalf("only the query should be written, got %q", out.String())
+	}
+}
+
+func TestModel_KeyReleaseEndsTheHold(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m.introActive = false
+	m.Update(key("d"))
+	if m.hold.dir != 1 {
+		t.Fatalf("d should hold the paddle right, got %+v", m.hold)
+	}
+	m.Update(keyReleaseMsg(key("d")))
+	if m.hold.dir != 0 || !m.hold.exact {
+		t.Fatalf("releasing d should stop the paddle, got %+v", m.hold)
+	}
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+// weekdayLabelW is the width of the weekday column left of the field ("Mon ").
+const weekdayLabelW = 4
+
+// weekdayLabels are the rows GitHub labels, by weekday.
+var weekdayLabels = [7]string{1: "Mon", 3: "Wed", 5: "Fri"}
+
+// axisLabels are the GitHub-style legends around the board: weekday names left
+// of the brick rows, month names on the field row above the ceiling, and a
+// "Less ▢▢▢▢ More" key below the field. Each part hides on its own when there
+// is no room for it.
+type axisLabels struct {
+	// left is the weekday column per field row; nil when hidden.
+	left []string
+	// monthY is the field row of the month labels, -1 when hidden; months holds
+	// one cell per field column ("" for blank).
+	monthY int
+	months []string
+	// legend is the key right-aligned under the field ("" when hidden), after
+	// legendPad.
+	legend    string
+	legendPad string
+}
+
+// newAxisLabels lays out the labels for grid as placed in s, in a terminal
+// termW columns wide.
+func newAxisLabels(p *palette, grid mapping.BrickGrid, s *game.State, termW int) *axisLabels {
+	l := &axisLabels{monthY: -1}
+
+	if grid.RowWeekdays != nil && termW >= s.ViewW+weekdayLabelW {
+		blank := strings.Repeat(" ", weekdayLabelW)
+		l.left = make([]string, s.Height)
+		for y := range l.left {
+			l.left[y] = blank
+		}
+		for r, wd := range grid.RowWeekdays {
+			y := s.TopOffset + r*s.BrickH
+			if wd >= 0 && weekdayLabels[wd] != "" && y < s.Height {
+				l.left[y] = p.hudLabel.Render(weekdayLabels[wd]) + blank[len(weekdayLabels[wd]):]
+			}
+		}
+	}
+
+	// The month row sits above the ceiling, where the ball never goes.
+	if monthY := s.TopWallY - 1; monthY >= 0 && len(grid.ColMonths) == len(s.ColX) {
+		l.months = make([]string, s.Width)
+		if placeMonths(l.months, grid.ColMonths, s.ColX, p) {
+			l.monthY = monthY
+		} else {
+			l.months = nil
+		}
+	}
+
+	legendW := len("Less ") + 4 + len(" More")
+	if s.ViewW >= legendW {
+		l.legend = p.hudLabel.Render("Less ") +
+			p.brickCell1[1] + p.brickCell1[2] + p.brickCell1[3] + p.brickCell1[4] +
+			p.hudLabel.Render(" More")
+		l.legendPad = strings.Repeat(" ", l.width()+s.ViewW-legendW)
+	}
+	return l
+}
+
+// placeMonths writes a label at the first column of each month into cells
+// (indexed by field x). A label must end before the next month starts, so
+// months squeezed by compression (and a partial first month) stay unlabeled,
+// like on GitHub. It reports whether any label was placed.
+func placeMonths(cells []string, colMonths []string, colX []int, p *palette) bool {
+	placed := false
+	for c := 0; c < len(colMonths); c++ {
+		if colMonths[c] == "" || (c > 0 && colMonths[c] == colMonths[c-1]) {
+			continue
+		}
+		t, err := time.Parse("2006-01", colMonths[c])
+		if err != nil {
+			continue
+		}
+		name := t.Format("Jan")
+		end := len(cells)
+		for n := c + 1; n < len(colMonths); n++ {
+			if colMonths[n] != colMonths[c] {
+				end = colX[n] - 1 // keep a space before the next label
+				break
+			}
+		}
+		x := colX[c]
+		if x+len(name) > end {
+			continue
+		}
+		for i, ch := range name {
+			cells[x+i] = p.hudLabel.Render(string(ch))
+		}
+		placed = true
+	}
+	return placed
+}
+
+// width is the number of columns the labels add left of the field.
+func (l *axisLabels) width() int {
+	if l == nil || l.left == nil {
+		return 0
+	}
+	return weekdayLabelW
+}
+
+// leftCell returns the weekday column for field row y ("" when hidden).
+func (l *axisLabels) leftCell(y int) string {
+	if l == nil || l.left == nil {
+		return ""
+	}
+	return l.left[y]
+}
+
+// monthRowY returns the field row of the month labels, or -1.
+func (l *axisLabels) monthRowY() int {
+	if l == nil {
+		return -1
+	}
+	return l.monthY
+}
+
+// monthRow returns the month cells of view row y for the view [vx, vx+w), or
+// nil when y is not the month row.
+func (l *axisLabels) monthRow(y, vx, w int) []string {
+	if l == nil || y != l.monthY {
+		return nil
+	}
+	return l.months[vx : vx+w]
+}
+
+// writeMonthRow writes the month labels under the ball and effect cells.
+func writeMonthRow(b *bytes.Buffer, cells []string, ballX int, ballCell string, fx []string) {
+	for x, cell := range cells {
+		switch {
+		case x == ballX:
+			b.WriteString(ballCell)
+		case fx != nil && fx[x] != "":
+			b.WriteString(fx[x])
+		case cell != "":
+			b.WriteString(cell)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+}
+
+// writeLegendLine writes the line under the field: the legend right-aligned
+// below the field, or nothing.
+func (l *axisLabels) writeLegendLine(b *bytes.Buffer, leftPad string) {
+	if l == nil || l.legend == "" {
+		return
+	}
+	b.WriteString(leftPad)
+	b.WriteString(l.legendPad)
+	b.WriteString(l.legend)
+}
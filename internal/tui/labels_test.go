@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+func TestAxisLabels_Layout(t *testing.T) {
+	t.Parallel()
+
+	p := newASCIIPalette(GlyphsOff)
+	g := benchGrid()
+	s := game.NewState(g, 118, 36, 1)
+
+	l := newAxisLabels(p, g, &s, 120)
+	if l.width() != weekdayLabelW {
+		t.Fatalf("expected the weekday column in a wide terminal")
+	}
+	top, _ := s.BrickBand()
+	if got := l.leftCell(top + 1); got != "Mon " {
+		t.Fatalf("row 1 should be labeled Mon, got %q", got)
+	}
+	if got := l.leftCell(top); got != "    " {
+		t.Fatalf("Sunday should be blank, got %q", got)
+	}
+
+	months := strings.Join(l.monthRow(l.monthRowY(), 0, s.ViewW), "")
+	if !strings.HasPrefix(months, "Jan") || !strings.Contains(months, "Dec") {
+		t.Fatalf("expected Jan..Dec month labels, got %q", months)
+	}
+	if l.legend != "Less 1234 More" {
+		t.Fatalf("unexpected legend %q", l.legend)
+	}
+}
+
+func TestAxisLabels_HideWhenNarrow(t *testing.T) {
+	t.Parallel()
+
+	p := newASCIIPalette(GlyphsOff)
+	g := benchGrid()
+	s := game.NewState(g, 118, 36, 1)
+
+	// The board fills the terminal: no room for weekday names.
+	if l := newAxisLabels(p, g, &s, s.ViewW+2); l.width() != 0 || l.leftCell(0) != "" {
+		t.Fatalf("weekday labels should hide when they do not fit")
+	}
+
+	// One-column weeks leave no room for any month name.
+	g.ColMonths[1] = "2025-02"
+	for c := 2; c < len(g.ColMonths); c++ {
+		g.ColMonths[c] = "2025-03"
+	}
+	g.ColMonths[len(g.ColMonths)-1] = "2025-04"
+	narrow := game.NewStateWithOptions(g, 60, 36, 1, game.Options{BrickW: 1})
+	l := newAxisLabels(p, g, &narrow, 200)
+	months := strings.Join(l.monthRow(l.monthRowY(), 0, narrow.ViewW), "")
+	if strings.Contains(months, "Jan") || strings.Contains(months, "Feb") || strings.Contains(months, "Apr") || !strings.Contains(months, "Mar") {
+		t.Fatalf("only March has room for a label, got %q", months)
+	}
+
+	// No rows above the ceiling: the month row hides.
+	short := s
+	short.TopWallY = 0
+	if l := newAxisLabels(p, g, &short, 200); l.monthRowY() != -1 {
+		t.Fatalf("month labels should hide without a free row")
+	}
+}
+
+func TestMouse_PointsPastTheLabels(t *testing.T) {
+	t.Parallel()
+
+	// A wide terminal centers the board and shows the weekday labels.
+	m := NewModel("octocat", versusCalendar(), 1, Options{ASCII: true, Mouse: true})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m.introActive = false
+	v := m.View()
+	if m.labels.width() == 0 {
+		t.Fatalf("the weekday labels should show")
+	}
+	if !strings.Contains(v, "Mon ") {
+		t.Fatalf("expected weekday labels:\n%s", v)
+	}
+
+	// Point at the left end of the paddle as drawn on screen.
+	col := -1
+	for _, line := range strings.Split(v, "\n") {
+		if i := strings.Index(line, "==="); i >= 0 {
+			col = i
+		}
+	}
+	if col < weekdayLabelW {
+		t.Fatalf("paddle not found past the labels:\n%s", v)
+	}
+	m.Update(tea.MouseMsg{X: col, Y: 0, Action: tea.MouseActionMotion})
+	if want := float64(int(m.state.PaddleX)) + 0.5; !m.mouseActive || m.mouseX != want {
+		t.Fatalf("screen column %d should point at field column %.1f, got %.1f", col, want, m.mouseX)
+	}
+}
@@ -11,24 +11,108 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
 	"github.com/fchimpan/gh-kusa-breaker/internal/game"
 	"github.com/fchimpan/gh-kusa-breaker/internal/github"
 	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
 )
 
+// Options configures a game session. Zero values fall back to defaults.
+type Options struct {
+	Speed float64 // user-facing speed multiplier (1.0 is normal)
+	Lives int     // balls per game; 0 means the difficulty's default
+	Keys  KeyMap
+
+	// Difficulty is the preset to play at. The zero value means
+	// game.DefaultDifficulty.
+	Difficulty game.Difficulty
+	// SpeedRamp speeds the ball up during rallies, on top of Speed.
+	SpeedRamp game.SpeedRamp
+
+	// HPScale maps contribution counts to brick HP. nil means linear.
+	HPScale mapping.HPScale
+	// Compress selects how weeks are merged on narrow terminals.
+	Compress mapping.Compression
+	// Layout arranges the calendar on the board.
+	Layout mapping.Layout
+
+	// BrickWidth is the brick width in columns (1..3); 0 picks 3 when the board
+	// fits without compression and 2 otherwise.
+	BrickWidth int
+	// BrickHeight is the brick height in rows; 0 means 1.
+	BrickHeight int
+	// MonthGutters adds an empty column between months.
+	MonthGutters bool
+
+	// Themes are the palettes the theme key cycles through. nil means BuiltinThemes.
+	Themes []Theme
+	// Theme names the starting theme; unknown or empty names pick the first.
+	Theme string
+	// Glyphs also draws brick HP as a character, for every theme.
+	Glyphs Glyphs
+	// ASCII renders plain ASCII without colors or escape sequences; themes are ignored.
+	ASCII bool
+
+	// SubCell draws the ball and paddle at finer than cell resolution (not in ASCII mode).
+	SubCell SubCell
+	// NoEffects turns off brick fragments, the ball trail and paddle flashes.
+	NoEffects bool
+
+	// Mouse steers the paddle with the pointer: click launches, the wheel changes
+	// speed. Balls then wait on the paddle until launched. The program must enable
+	// mouse motion events (tea.WithMouseAllMotion).
+	Mouse bool
+
+	// Sound plays audio feedback for game events; nil is silent. The caller
+	// closes it after the program exits.
+	Sound sound.Player
+
+	// Broadcast streams the game to watchers (see broadcast.Server); nil
+	// streams nothing. The caller closes it after the program exits.
+	Broadcast *broadcast.Server
+
+	// TimeLimit is the length of a versus match (see NewVersusModel); 0 means
+	// 3 minutes.
+	TimeLimit time.Duration
+}
+
 type Model struct {
-	login string
-	cal   github.Calendar
-	seed  uint64
-	speed float64
+	login    string
+	cal      github.Calendar
+	seed     uint64
+	speed    float64
+	lives    int
+	keys     map[string]keyAction
+	help     keyHelp
+	gridOpts mapping.GridOptions
+	gameOpts game.Options // brick geometry and lives; BrickW resolved per resize
+	brickW   int          // requested brick width (0 = auto)
+
+	themeSet
+
+	// notice is a short message shown on the info line until noticeTTL runs out.
+	notice    string
+	noticeTTL float64
 
 	lastTick time.Time
 	acc      float64
+	// playTime is the wall time with a ball in play, for the stats screen.
+	playTime float64
 
 	rng           *rand.Rand
 	confetti      []confettiParticle
 	confettiSpawn float64
 
+	effectsOn bool
+	fx        effects
+	fxLayer   fxLayer
+
+	sound sound.Player
+	// bell draws a BEL with every frame until the next tick (see ringing).
+	bell      bool
+	broadcast *broadcast.Server
+
 	ready bool
 	w     int
 	h     int
@@ -36,7 +120,27 @@ type Model struct {
 	grid  mapping.BrickGrid
 	state game.State
 
-	move int
+	hold   keyHold
+	launch bool
+
+	inspecting bool
+	cursor     inspectCursor
+
+	// The boss stage fights the busiest week after a clear; bossEntry is the
+	// cleared board it continues from.
+	busiest   mapping.WeekTotal
+	hasBoss   bool
+	bossStage bool
+	bossEntry game.State
+
+	// Mouse steering: mouseX is the pointer column in field coordinates, used
+	// while mouseActive (until a movement key is pressed).
+	mouse       bool
+	mouseActive bool
+	mouseX      float64
+	// fieldX0 is the screen column of the field's left edge, as laid out by
+	// the last View.
+	fieldX0 int
 
 	viewBuf bytes.Buffer
 
@@ -46,6 +150,9 @@ type Model struct {
 
 	overlayCanvas canvasBuf
 
+	// labels are laid out on the first View after a rebuild or theme change.
+	labels *axisLabels
+
 	// Startup intro animation: reveal brick columns from left to right,
 	// then start the simulation.
 	introActive      bool
@@ -59,20 +166,121 @@ type Model struct {
 	noBricks bool
 }
 
+// ringing reports whether p rang the terminal bell since the last call. The
+// models draw the bell with their frames until the next tick: a View that
+// does not reach the screen must not swallow it, and the renderer writes an
+// unchanged line only once.
+func ringing(p sound.Player) bool {
+	r, ok := p.(sound.Ringer)
+	return ok && r.Ringing()
+}
+
 // baseSpeedMultiplier defines what "1.0x" means in this game.
 // Historically, 1.25x felt better, so we bake that in as the baseline.
 const baseSpeedMultiplier = 1.25
 
-func NewModel(login string, cal github.Calendar, seed uint64, speed float64) *Model {
+// settings are the Options with their defaults filled in. Every model starts
+// from them.
+type settings struct {
+	speed     float64
+	keys      map[string]keyAction
+	help      keyHelp
+	gridOpts  mapping.GridOptions
+	gameOpts  game.Options
+	brickW    int
+	theme     themeSet
+	effectsOn bool
+	sound     sound.Player
+	rng       *rand.Rand
+}
+
+// resolveOptions fills in the defaults of opts. ManualServe is left off; only
+// the single-player game serves by hand.
+func resolveOptions(seed uint64, opts Options) settings {
+	speed := opts.Speed
 	if speed <= 0 {
 		speed = 1
 	}
-	return &Model{
-		login: login,
-		cal:   cal,
-		seed:  seed,
+	difficulty := opts.Difficulty
+	if difficulty.Name == "" {
+		difficulty = game.DefaultDifficulty
+	}
+	lives := opts.Lives
+	if lives <= 0 {
+		lives = max(difficulty.Lives, 1)
+	}
+	themes := opts.Themes
+	if len(themes) == 0 {
+		themes = BuiltinThemes()
+	}
+	if opts.ASCII {
+		themes = []Theme{{Name: "ascii"}}
+	}
+	themeIdx, _ := FindTheme(themes, opts.Theme)
+	return settings{
 		speed: speed,
-		rng:   rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		keys:  opts.Keys.bindings(),
+		help:  opts.Keys.help(),
+		gridOpts: mapping.GridOptions{
+			Scale:    opts.HPScale,
+			Compress: opts.Compress,
+			Layout:   opts.Layout,
+		},
+		gameOpts: game.Options{
+			BrickH:       opts.BrickHeight,
+			MonthGutters: opts.MonthGutters,
+			Lives:        lives,
+			Difficulty:   difficulty,
+			SpeedRamp:    opts.SpeedRamp,
+		},
+		brickW:    opts.BrickWidth,
+		theme:     newThemeSet(themes, themeIdx, opts.Glyphs, opts.ASCII, opts.SubCell),
+		effectsOn: !opts.NoEffects,
+		sound:     opts.Sound,
+		rng:       rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+	}
+}
+
+func NewModel(login string, cal github.Calendar, seed uint64, opts Options) *Model {
+	set := resolveOptions(seed, opts)
+	set.gameOpts.ManualServe = opts.Mouse
+	m := &Model{
+		login:    login,
+		cal:      cal,
+		seed:     seed,
+		speed:    set.speed,
+		lives:    set.gameOpts.Lives,
+		keys:     set.keys,
+		help:     set.help,
+		gridOpts: set.gridOpts,
+		gameOpts: set.gameOpts,
+		mouse:    opts.Mouse,
+		brickW:   set.brickW,
+		themeSet: set.theme,
+
+		effectsOn: set.effectsOn,
+		sound:     set.sound,
+		broadcast: opts.Broadcast,
+		rng:       set.rng,
+	}
+	m.busiest, m.hasBoss = mapping.BusiestWeek(cal)
+	return m
+}
+
+// flash shows msg on the info line for a couple of seconds.
+func (m *Model) flash(msg string) {
+	m.notice = msg
+	m.noticeTTL = 2
+}
+
+func (m *Model) updateNotice(dt float64) {
+	if m.noticeTTL <= 0 {
+		return
+	}
+	m.noticeTTL -= dt
+	if m.noticeTTL <= 0 {
+		m.notice = ""
+		m.noticeTTL = 0
 	}
 }
 
@@ -115,6 +323,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.updateParty(dt)
 		m.updateIntro(dt)
+		m.updateNotice(dt)
+		move := m.hold.advance(dt)
 
 		// Fixed timestep simulation (more stable collisions than variable-dt).
 		// speed is a user-facing multiplier; baseSpeedMultiplier defines what "1.0x" means.
@@ -122,54 +332,172 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		const fixed = 1.0 / 120.0
 		const maxStepsPerTick = 10
 
-		if m.ready && !m.introActive && !m.noBricks && !m.state.Cleared && !m.state.GameOver {
+		if m.ready && !m.introActive && !m.inspecting && !m.noBricks && !m.state.Cleared && !m.state.GameOver {
+			if !m.state.Serving {
+				m.playTime += dt
+			}
 			steps := 0
 			for m.acc >= fixed && steps < maxStepsPerTick {
-				m.state.Step(fixed, game.Input{Move: m.move})
+				m.state.Step(fixed, game.Input{
+					Move:    move,
+					Target:  m.mouseActive,
+					TargetX: m.mouseX,
+					Launch:  m.launch,
+				})
+				m.handleEvents(m.state.Events())
 				m.acc -= fixed
 				steps++
 			}
+			if steps > 0 {
+				m.launch = false
+			}
+			if m.effectsOn {
+				m.fx.update(dt, m.state.BallX, m.state.BallY)
+			}
 			// If we are too far behind, drop the remainder to keep the app responsive.
 			if steps >= maxStepsPerTick {
 				m.acc = math.Mod(m.acc, fixed)
 			}
-			m.move = 0
+		}
+		if m.sound != nil {
+			// Keep the clock running while paused so blips finish and a WAV
+			// recording stays in step with the game.
+			m.sound.Advance(dt)
+			m.bell = ringing(m.sound)
+		}
+		if m.broadcast != nil && m.ready && !m.noBricks {
+			m.broadcast.Publish(m.login, &m.state, m.speed, m.lives)
 		}
 		return m, tickCmd(m.frameDuration())
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		action := m.keys[msg.String()]
+		if m.inspecting && m.inspectKey(msg.String(), action) {
+			return m, nil
+		}
+		switch action {
+		case actionQuit:
 			return m, tea.Quit
-		case "r", "R":
+		case actionRetry:
 			if m.ready && !m.introActive {
+				m.inspecting = false
 				m.resetGame()
 			}
 			return m, nil
-		case "+", "=":
-			m.speed += 0.1
-			if m.speed > 5 {
-				m.speed = 5
+		case actionInspect:
+			m.enterInspect()
+		case actionBoss:
+			if m.bossAvailable() {
+				m.startBoss()
 			}
-		case "-", "_":
-			m.speed -= 0.1
-			if m.speed < 0.25 {
-				m.speed = 0.25
+		case actionSpeedUp:
+			m.adjustSpeed(0.1)
+		case actionSpeedDown:
+			m.adjustSpeed(-0.1)
+		case actionLaunch:
+			if !m.introActive {
+				m.launch = true
 			}
-		case "left", "h", "a", "H", "A":
+		case actionTheme:
+			m.cycleTheme()
+			m.labels = nil
+			m.flash("theme: " + m.pal.name)
+		case actionLeft:
 			if !m.introActive {
-				m.move = -1
+				m.hold.press(-1)
+				m.mouseActive = false
 			}
-		case "right", "l", "d", "L", "D":
+		case actionRight:
 			if !m.introActive {
-				m.move = 1
+				m.hold.press(1)
+				m.mouseActive = false
 			}
 		}
 		return m, nil
+	case keyReleaseMsg:
+		switch m.keys[msg.String()] {
+		case actionLeft:
+			m.hold.release(-1)
+		case actionRight:
+			m.hold.release(1)
+		}
+		return m, nil
+	case tea.MouseMsg:
+		m.handleMouse(msg)
+		return m, nil
 	default:
 		return m, nil
 	}
 }
 
+// handleEvents reacts to the events of one engine step.
+func (m *Model) handleEvents(events []game.Event) {
+	if m.effectsOn {
+		m.fx.handle(events, &m.state, m.rng)
+	}
+	if m.sound != nil {
+		m.sound.Handle(events)
+	}
+	for _, ev := range events {
+		switch ev.Kind {
+		case game.EventBrickDestroyed:
+			m.flash(brokeNotice(ev))
+			m.cursor = inspectCursor{row: ev.Row, col: ev.Col, set: true}
+		case game.EventPowerUp:
+			m.flash(powerUpNotice(ev))
+		case game.EventHazardHit:
+			m.flash("Hit by the boss!")
+		case game.EventSpeedUp:
+			m.flash(fmt.Sprintf("Speed up! ball %.2fx", m.speed*ev.Pace))
+		}
+	}
+}
+
+// inspectKey handles a key in inspect mode and reports whether it was used.
+func (m *Model) inspectKey(key string, action keyAction) bool {
+	switch {
+	case action == actionInspect || key == "esc":
+		m.inspecting = false
+	case action == actionLeft:
+		m.moveCursor(0, -1)
+	case action == actionRight:
+		m.moveCursor(0, 1)
+	case action == actionUp:
+		m.moveCursor(-1, 0)
+	case action == actionDown:
+		m.moveCursor(1, 0)
+	case action == actionLaunch:
+		// Launching would be lost while paused.
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Model) adjustSpeed(delta float64) {
+	m.speed = min(max(m.speed+delta, 0.25), 5)
+}
+
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if !m.mouse || !m.ready || m.introActive || m.inspecting {
+		return
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.adjustSpeed(0.1)
+		return
+	case tea.MouseButtonWheelDown:
+		m.adjustSpeed(-0.1)
+		return
+	case tea.MouseButtonLeft:
+		if msg.Action == tea.MouseActionPress {
+			m.launch = true
+		}
+	}
+	// Aim the paddle center at the middle of the pointed cell.
+	m.mouseX = float64(msg.X-m.fieldX0+m.state.ViewLeft()) + 0.5
+	m.mouseActive = true
+}
+
 func (m *Model) frameDuration() time.Duration {
 	// Bubble Tea drives View() on every message; avoid rendering 60fps when not needed.
 	if !m.ready {
@@ -190,32 +518,41 @@ func (m *Model) frameDuration() time.Duration {
 	return time.Second / 60
 }
 
-func (m *Model) rebuild() {
+// fieldSize returns the game field size for the current terminal size.
+func (m *Model) fieldSize() (gameW, gameH int) {
 	// Keep a couple columns for padding.
-	cellW := 2
-	maxCols := max((m.w-2)/cellW, 1)
-
-	m.grid = mapping.BuildBrickGrid(m.cal, maxCols)
+	gameW = max(m.w-2, 10)
+	gameH = max(m.h-4, 10)
+	return gameW, gameH
+}
 
-	gameH := m.h - 4
-	if gameH < 10 {
-		gameH = 10
-	}
+func (m *Model) rebuild() {
+	gameW, gameH := m.fieldSize()
+	fight := m.state
 
-	gameW := m.w - 2
-	if gameW < 10 {
-		gameW = 10
-	}
+	m.grid, m.gameOpts = fitGrid(m.cal, gameW, gameH, m.brickW, m.gridOpts, m.gameOpts)
 
-	m.state = game.NewState(m.grid, gameW, gameH, m.seed)
+	m.state = game.NewStateWithOptions(m.grid, gameW, gameH, m.seed, m.gameOpts)
 	m.noBricks = m.state.BricksRemaining <= 0
 	m.lastTick = time.Time{}
 	m.acc = 0
 	m.confetti = nil
 	m.confettiSpawn = 0
+	m.fx.reset()
+	if m.bossStage {
+		// A resize carries the fight over to the new size.
+		m.newBossState()
+		m.state.Resume(&fight)
+	} else {
+		m.playTime = 0
+	}
+	if m.inspecting {
+		m.moveCursor(0, 0)
+	}
 	m.spaceLine = ""
 	m.spaceLineW = 0
 	m.overlayCanvas.Reset()
+	m.labels = nil
 	if m.rng == nil {
 		m.rng = rand.New(rand.NewPCG(m.seed, m.seed^0x9e3779b97f4a7c15))
 	}
@@ -228,10 +565,7 @@ func (m *Model) rebuild() {
 	m.introAcc = 0
 	m.introStep = 0
 	if !m.introDone && !m.noBricks && !m.state.Cleared && m.state.BricksRemaining > 0 {
-		cols := 0
-		if len(m.state.Bricks) > 0 {
-			cols = len(m.state.Bricks[0])
-		}
+		cols := m.state.BrickCols
 		if cols > 0 {
 			// Target ~1.1s total, clamped per-column.
 			step := 1.1 / float64(cols)
@@ -252,27 +586,63 @@ func (m *Model) rebuild() {
 	}
 }
 
-func (m *Model) resetGame() {
-	// Change seed so retries feel fresh even with a fixed --seed.
-	m.seed++
-	m.rng = rand.New(rand.NewPCG(m.seed, m.seed^0x9e3779b97f4a7c15))
+// fitGrid builds the brick grid of cal for a gameW x gameH field and resolves
+// the brick width into gameOpts: brickW, or 0 for 3 when the board fits without
+// compression and 2 otherwise.
+func fitGrid(cal github.Calendar, gameW, gameH, brickW int, opts mapping.GridOptions, gameOpts game.Options) (mapping.BrickGrid, game.Options) {
+	opts.MaxRows = game.MaxBrickRows(gameH) / max(gameOpts.BrickH, 1)
 
-	gameH := m.h - 4
-	if gameH < 10 {
-		gameH = 10
+	gameOpts.BrickW = brickW
+	if brickW <= 0 {
+		gameOpts.BrickW = 2
 	}
+	grid := buildGrid(cal, gameW, opts, gameOpts)
 
-	gameW := m.w - 2
-	if gameW < 10 {
-		gameW = 10
+	// Auto width: use wider bricks when the board fits without compression.
+	if brickW <= 0 && opts.Compress != mapping.CompressScroll {
+		wide := gameOpts
+		wide.BrickW = 3
+		if grid.Cols > 0 && game.BoardWidth(grid, wide) <= gameW {
+			gameOpts = wide
+		}
 	}
+	return grid, gameOpts
+}
 
-	m.state = game.NewState(m.grid, gameW, gameH, m.seed)
+// buildGrid builds the brick grid so that the board fits gameW columns with the
+// brick width of gameOpts (gutters included), unless the board scrolls.
+func buildGrid(cal github.Calendar, gameW int, opts mapping.GridOptions, gameOpts game.Options) mapping.BrickGrid {
+	bw := max(gameOpts.BrickW, 1)
+	maxCols := max(gameW/bw, 1)
+	for {
+		grid := mapping.BuildBrickGrid(cal, maxCols, opts)
+		over := game.BoardWidth(grid, gameOpts) - gameW
+		if over <= 0 || opts.Compress == mapping.CompressScroll || maxCols <= 1 {
+			return grid
+		}
+		maxCols = max(maxCols-(over+bw-1)/bw, 1)
+	}
+}
+
+func (m *Model) resetGame() {
+	// Change seed so retries feel fresh even with a fixed --seed.
+	m.seed++
+	m.rng = rand.New(rand.NewPCG(m.seed, m.seed^0x9e3779b97f4a7c15))
+
+	gameW, gameH := m.fieldSize()
+	m.state = game.NewStateWithOptions(m.grid, gameW, gameH, m.seed, m.gameOpts)
 	m.noBricks = m.state.BricksRemaining <= 0
+	if m.bossStage {
+		m.bossStage = false
+		m.labels = nil
+	}
 	m.lastTick = time.Time{}
 	m.acc = 0
+	m.playTime = 0
+	m.hold.reset()
 	m.confetti = nil
 	m.confettiSpawn = 0
+	m.fx.reset()
 	// Keep caches; dimensions unchanged.
 }
 
@@ -283,29 +653,58 @@ func (m *Model) View() string {
 
 	m.viewBuf.Reset()
 	b := &m.viewBuf
+	if m.bell {
+		b.WriteByte('\a')
+	}
 	// NOTE: Avoid embedding cursor-control sequences (ESC[K / ESC[J) in Bubble Tea views.
 	// They can interfere with the renderer on some terminals and hide lines unexpectedly.
 	clearEOL := ""
 
-	hud := renderHUD(m.login, m.state.Score, m.state.BricksRemaining, m.state.BricksTotal, m.speed)
+	lives := hudLives(m.lives, m.state.Lives)
+	h := &m.help
+	keys := fmt.Sprintf("%s, %s retry, %s/%s speed, %s theme, %s inspect, %s quit",
+		h.move(m.pal.arrows), h.key(actionRetry), h.key(actionSpeedUp), h.key(actionSpeedDown),
+		h.key(actionTheme), h.key(actionInspect), h.key(actionQuit))
+	hud := renderHUD(m.pal, m.login, m.state.Score, m.state.BricksRemaining, m.state.BricksTotal, lives, m.speed, m.state.Pace(), m.state.Difficulty.Name, m.state.Boss, keys)
+	retryQuit := fmt.Sprintf("%s retry, %s quit", h.key(actionRetry), h.key(actionQuit))
 	infoLine := ""
 	if m.introActive {
 		infoLine = "starting..."
 	} else if m.noBricks {
-		infoLine = "no contributions found (q quit)"
+		infoLine = "no contributions found (" + h.key(actionQuit) + " quit)"
+	} else if m.state.Cleared && m.bossStage {
+		infoLine = "BOSS DEFEATED! (" + retryQuit + ")"
+	} else if m.bossAvailable() {
+		infoLine = "CLEAR! all blocks removed. (" + h.key(actionBoss) + " boss stage, " + retryQuit + ")"
 	} else if m.state.Cleared {
-		infoLine = "CLEAR! all blocks removed. (r retry, q quit)"
+		infoLine = "CLEAR! all blocks removed. (" + retryQuit + ")"
 	} else if m.state.GameOver {
 		infoLine = ""
+	} else if m.state.Serving {
+		infoLine = "click or press " + h.key(actionLaunch) + " to launch"
 	} else {
-		infoLine = "press r to retry (restart), q to quit"
+		infoLine = fmt.Sprintf("press %s to retry (restart), %s to quit", h.key(actionRetry), h.key(actionQuit))
+	}
+	if m.notice != "" && !m.state.GameOver {
+		infoLine = m.notice
+	}
+	if m.inspecting {
+		infoLine = m.inspectLine()
 	}
 
-	fieldW := m.state.Width
+	if m.labels == nil {
+		if m.bossStage {
+			// The boss is not on the calendar grid.
+			m.labels = &axisLabels{monthY: -1}
+		} else {
+			m.labels = newAxisLabels(m.pal, m.grid, &m.state, m.w)
+		}
+	}
+	fieldW := m.state.ViewW
 	if fieldW < 0 {
 		fieldW = 0
 	}
-	contentW := fieldW
+	contentW := fieldW + m.labels.width()
 	if w := lipgloss.Width(hud); w > contentW {
 		contentW = w
 	}
@@ -331,6 +730,8 @@ func (m *Model) View() string {
 	for i := 0; i < topPad; i++ {
 		b.WriteString("\n")
 	}
+	// The field starts right of the weekday labels.
+	m.fieldX0 = leftPad + m.labels.width()
 
 	b.WriteString(leftPadStr)
 	b.WriteString(hud)
@@ -340,7 +741,7 @@ func (m *Model) View() string {
 		b.WriteString(leftPadStr)
 		b.WriteString(infoLine)
 		b.WriteString("\n")
-	} else if m.state.GameOver {
+	} else if m.state.GameOver && !m.inspecting {
 		// For GameOver, we show an overlay, so keep this line minimal.
 		b.WriteString("\n")
 	} else {
@@ -350,6 +751,7 @@ func (m *Model) View() string {
 	}
 
 	var overlay *fieldOverlay
+	retryFooter := fmt.Sprintf("press %s to retry, %s to quit", h.key(actionRetry), h.key(actionQuit))
 	if m.noBricks {
 		overlay = &fieldOverlay{
 			Title: "NO CONTRIBUTIONS",
@@ -358,38 +760,71 @@ func (m *Model) View() string {
 				fmt.Sprintf("user: %s", m.login),
 				"try a different user/range, or contribute!",
 			},
-			Footer: "press q to quit",
+			Footer: "press " + h.key(actionQuit) + " to quit",
+		}
+	} else if m.state.GameOver && m.bossStage {
+		b := m.state.Boss
+		overlay = &fieldOverlay{
+			Title: "GAME OVER...",
+			Lines: append([]string{
+				scoreLine(&m.state),
+				fmt.Sprintf("user: %s", m.login),
+				fmt.Sprintf("boss HP left: %d/%d", b.HP, b.MaxHP),
+			}, bossLines(b)...),
+			Footer: retryFooter,
 		}
 	} else if m.state.GameOver {
 		overlay = &fieldOverlay{
 			Title: "GAME OVER...",
-			Lines: []string{
-				fmt.Sprintf("score: %8d", m.state.Score),
+			Lines: withStats([]string{
+				scoreLine(&m.state),
 				fmt.Sprintf("user: %s", m.login),
-			},
-			Footer: "press r to retry, q to quit",
+			}, &m.state, m.playTime, m.state.Height),
+			Footer: retryFooter,
 		}
-	} else if m.state.Cleared {
+	} else if m.state.Cleared && m.bossStage {
 		overlay = &fieldOverlay{
-			Title: "CLEAR!  WAIWAI FESTIVAL",
-			Lines: []string{
-				"nice break!",
-				fmt.Sprintf("score: %8d", m.state.Score),
+			Title: "BOSS DEFEATED!",
+			Lines: append(append(append([]string{
+				scoreLine(&m.state),
+			}, breakdownLines(&m.state)...),
+				bossLines(m.state.Boss)...),
 				fmt.Sprintf("user: %s", m.login),
 				"thank you for playing!",
-			},
-			Footer: "press r to retry, q to quit",
+			),
+			Footer: retryFooter,
+		}
+	} else if m.state.Cleared {
+		footer := retryFooter
+		if m.bossAvailable() {
+			footer = fmt.Sprintf("press %s for the boss stage, %s to retry, %s to quit", h.key(actionBoss), h.key(actionRetry), h.key(actionQuit))
+		}
+		lines := []string{"nice break!", scoreLine(&m.state)}
+		lines = append(lines, breakdownLines(&m.state)...)
+		lines = append(lines, fmt.Sprintf("user: %s", m.login))
+		// Stats fill what room is left, less the closing line.
+		lines = withStats(lines, &m.state, m.playTime, m.state.Height-1)
+		lines = append(lines, "thank you for playing!")
+		overlay = &fieldOverlay{
+			Title:  "CLEAR!  WAIWAI FESTIVAL",
+			Lines:  lines,
+			Footer: footer,
 		}
 	}
 
+	// Inspecting shows the board the overlay would cover.
+	if m.inspecting && !m.noBricks {
+		overlay = nil
+	}
+
 	// Cache space line for the current field width to avoid per-frame Repeat.
-	if m.state.Width != m.spaceLineW {
-		if m.state.Width > 0 {
-			m.spaceLine = strings.Repeat(" ", m.state.Width)
+	if m.state.ViewW != m.spaceLineW {
+		if m.state.ViewW > 0 {
+			m.spaceLine = strings.Repeat(" ", m.state.ViewW)
 		} else {
 			m.spaceLine = ""
 		}
-		m.spaceLineW = m.state.Width
+		m.spaceLineW = m.state.ViewW
 	}
 
 	if overlay == nil {
@@ -397,7 +832,28 @@ func (m *Model) View() string {
 		if m.introActive {
 			visibleCols = m.introVisibleCols
 		}
-		renderFieldFastTo(b, m.state, clearEOL, leftPadStr, m.spaceLine, visibleCols)
+		var fx *fxLayer
+		if m.effectsOn && m.fx.active() {
+			m.fx.draw(&m.fxLayer, m.pal, &m.state)
+			fx = &m.fxLayer
+		}
+		if len(m.state.Drops) > 0 || m.state.Boss != nil || m.inspecting {
+			if fx == nil {
+				m.fxLayer.resize(m.state.ViewW, m.state.Height)
+				fx = &m.fxLayer
+			}
+			drawDrops(fx, m.pal, &m.state)
+			drawBoss(fx, m.pal, &m.state)
+			if m.inspecting {
+				m.drawCursor(fx)
+			}
+		}
+		if g := m.pal.sub[m.subcell]; g != nil {
+			renderFieldSubCellTo(b, m.pal, g, m.state, fx, m.labels, clearEOL, leftPadStr, m.spaceLine, visibleCols)
+		} else {
+			renderFieldFastTo(b, m.pal, m.state, fx, m.labels, clearEOL, leftPadStr, m.spaceLine, visibleCols)
+		}
+		m.labels.writeLegendLine(b, leftPadStr)
 		b.WriteString("\n")
 	} else {
 		// Overlay path is only active on GameOver, where performance is less critical.
@@ -405,7 +861,8 @@ func (m *Model) View() string {
 		if m.state.Cleared {
 			confetti = m.confetti
 		}
-		renderFieldCanvasTo(b, m.state, overlay, clearEOL, leftPadStr, confetti, &m.overlayCanvas)
+		renderFieldCanvasTo(b, m.pal, m.state, overlay, m.labels, clearEOL, leftPadStr, confetti, &m.overlayCanvas)
+		m.labels.writeLegendLine(b, leftPadStr)
 		b.WriteString("\n")
 	}
 
@@ -438,8 +895,19 @@ func (m *Model) updateIntro(dt float64) {
 	}
 }
 
-func renderHUD(login string, score, remaining, total int, speed float64) string {
-	sep := styleHudDim.Render("  |  ")
+// hudLives is the lives counter of a game that started with start balls and
+// has left of them, or 0 for none: single-ball games (the default) keep the
+// original HUD without one.
+func hudLives(start, left int) int {
+	if start > 1 {
+		return left
+	}
+	return 0
+}
+
+// renderHUD draws the status line; keys, when not empty, is the key help at its end.
+func renderHUD(p *palette, login string, score, remaining, total, lives int, speed, pace float64, difficulty string, boss *game.Boss, keys string) string {
+	sep := p.hudDim.Render("  |  ")
 
 	if total <= 0 {
 		total = remaining
@@ -463,21 +931,59 @@ func renderHUD(login string, score, remaining, total int, speed float64) string
 	if fill > barW {
 		fill = barW
 	}
-	bar := styleHudLabel.Render("[") +
-		styleHudOk.Render(strings.Repeat("█", fill)) +
-		styleHudDim.Render(strings.Repeat("░", barW-fill)) +
-		styleHudLabel.Render("]")
-
-	return strings.Join([]string{
-		styleHudLabel.Render("user ") + styleHudValue.Render(login),
+	bar := p.hudLabel.Render("[") +
+		p.hudOk.Render(strings.Repeat(p.barFull, fill)) +
+		p.hudDim.Render(strings.Repeat(p.barEmpty, barW-fill)) +
+		p.hudLabel.Render("]")
+
+	progress := p.hudLabel.Render("blocks ") + p.hudValue.Render(fmt.Sprintf("%4d/%4d", remaining, total)) + " " + bar
+	if boss != nil {
+		// The boss bar empties as the boss takes damage.
+		fill := barW * max(boss.HP, 0) / max(boss.MaxHP, 1)
+		if boss.HP > 0 {
+			fill = max(fill, 1)
+		}
+		alert := lipgloss.NewStyle().Bold(true).Foreground(p.alert)
+		if p.plain {
+			alert = lipgloss.NewStyle()
+		}
+		progress = p.hudLabel.Render("boss ") + p.hudValue.Render(fmt.Sprintf("%4d/%4d", max(boss.HP, 0), boss.MaxHP)) + " " +
+			p.hudLabel.Render("[") +
+			alert.Render(strings.Repeat(p.barFull, fill)) +
+			p.hudDim.Render(strings.Repeat(p.barEmpty, barW-fill)) +
+			p.hudLabel.Render("]")
+	}
+	parts := []string{
+		p.hudLabel.Render("user ") + p.hudValue.Render(login),
 		sep,
-		styleHudLabel.Render("score ") + styleHudScore.Render(fmt.Sprintf("%8d", score)),
+		p.hudLabel.Render("score ") + p.hudScore.Render(fmt.Sprintf("%8d", score)),
 		sep,
-		styleHudLabel.Render("blocks ") + styleHudValue.Render(fmt.Sprintf("%4d/%4d", remaining, total)) + " " + bar,
+		progress,
+	}
+	if p.legend != "" {
+		parts = append(parts, sep, p.legend)
+	}
+	if lives > 0 {
+		parts = append(parts, sep, p.hudLabel.Render("lives ")+p.hudValue.Render(fmt.Sprintf("%d", lives)))
+	}
+	parts = append(parts,
 		sep,
-		styleHudLabel.Render("speed ") + styleHudValue.Render(fmt.Sprintf("%.2fx", speed)),
-		styleHudDim.Render("  (←/→ a/d h/l, r retry, +/- speed, q quit)"),
-	}, "")
+		p.hudLabel.Render("level ")+p.hudValue.Render(difficulty),
+		sep,
+		p.hudLabel.Render("speed ")+p.hudValue.Render(fmt.Sprintf("%.2fx", speed)),
+		// The ball's pace runs on game time, so the user speed scales it.
+		p.hudLabel.Render(" ball ")+p.hudValue.Render(fmt.Sprintf("%.2fx", speed*pace)),
+	)
+	if keys != "" {
+		parts = append(parts, p.hudDim.Render("  ("+keys+")"))
+	}
+	return strings.Join(parts, "")
+}
+
+// scoreLine is the score of s on the end-of-game overlays, with the difficulty
+// it was played at.
+func scoreLine(s *game.State) string {
+	return fmt.Sprintf("score: %8d (%s)", s.Score, s.Difficulty.Name)
 }
 
 type fieldOverlay struct {
@@ -509,7 +1015,7 @@ func (m *Model) updateParty(dt float64) {
 		m.rng = rand.New(rand.NewPCG(m.seed, m.seed^0x9e3779b97f4a7c15))
 	}
 
-	w := m.state.Width
+	w := m.state.ViewW
 	h := m.state.Height
 	if w <= 0 || h <= 0 {
 		return
@@ -542,6 +1048,9 @@ func (m *Model) updateParty(dt float64) {
 		ci := m.rng.IntN(len(confettiChars))
 		co := m.rng.IntN(len(confettiColors))
 		cell := confettiCells[ci][co]
+		if m.pal.plain {
+			cell = string(confettiChars[ci])
+		}
 
 		vy := 10.0 + m.rng.Float64()*25.0
 		m.confetti = append(m.confetti, confettiParticle{
@@ -553,38 +1062,7 @@ func (m *Model) updateParty(dt float64) {
 	}
 }
 
-// ===== Render helpers (cached styles) =====
-
-var (
-	stylePaddle = lipgloss.NewStyle().Foreground(lipgloss.Color("#d0d7de"))
-	styleBall   = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffd33d"))
-
-	paddleCell = stylePaddle.Render("=")
-	ballCell   = styleBall.Render("*")
-
-	styleHudLabel = lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
-	styleHudValue = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#d0d7de"))
-	styleHudScore = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffd33d"))
-	styleHudOk    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7ee787"))
-	styleHudDim   = lipgloss.NewStyle().Foreground(lipgloss.Color("#6e7681"))
-
-	// GitHub-like greens (light -> dark):
-	// 1: #9be9a8, 2: #40c463, 3: #30a14e, 4: #216e39
-	brickCell1 = [5]string{
-		"", // 0 unused
-		lipgloss.NewStyle().Background(lipgloss.Color("#9be9a8")).Render(" "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#40c463")).Render(" "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#30a14e")).Render(" "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#216e39")).Render(" "),
-	}
-	brickSpan2 = [5]string{
-		"", // 0 unused
-		lipgloss.NewStyle().Background(lipgloss.Color("#9be9a8")).Render("  "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#40c463")).Render("  "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#30a14e")).Render("  "),
-		lipgloss.NewStyle().Background(lipgloss.Color("#216e39")).Render("  "),
-	}
-)
+// ===== Render helpers =====
 
 var (
 	confettiChars  = []rune{'*', '+', 'x', 'o', '~', '^'}
@@ -608,78 +1086,116 @@ var (
 	}()
 )
 
-func hpSpan2(hp int) string {
-	if hp <= 0 {
-		return "  "
+// shownBrick returns the brick shown at field cell (x, y), or nil for empty
+// cells, destroyed bricks, and columns not yet revealed by the intro.
+func shownBrick(s *game.State, x, y, visibleBrickCols int) *game.Brick {
+	i := s.BrickAt(x, y)
+	if i < 0 {
+		return nil
 	}
-	if hp > 4 {
-		hp = 4
+	br := &s.Bricks[i]
+	if br.HP <= 0 || (visibleBrickCols >= 0 && br.Col >= visibleBrickCols) {
+		return nil
 	}
-	return brickSpan2[hp]
+	return br
+}
+
+// brickHP returns the HP shown at field cell (x, y) as a plain run (see
+// shownBrick); streak bricks, drawn cell by cell, report -1.
+func brickHP(s *game.State, x, y, visibleBrickCols int) int {
+	br := shownBrick(s, x, y, visibleBrickCols)
+	switch {
+	case br == nil:
+		return 0
+	case br.Streak:
+		return -1
+	}
+	return br.HP
 }
 
-func hpCell1(hp int) string {
-	if hp <= 0 {
-		return " "
+// writeBrickRow writes field row y of the brick band in view coordinates, as runs
+// of equal HP to keep output small. Runs are split around view column ballX
+// (-1 when the ball is not on this row), where ballCell is written instead, and
+// around effect cells in fx (the row of an fxLayer, or nil).
+func writeBrickRow(b *bytes.Buffer, p *palette, s *game.State, y, w, vx, ballX int, ballCell string, fx []string, visibleBrickCols int) {
+	for x := 0; x < w; {
+		if x == ballX {
+			b.WriteString(ballCell)
+			x++
+			continue
+		}
+		if fx != nil && fx[x] != "" {
+			b.WriteString(fx[x])
+			x++
+			continue
+		}
+		hp := brickHP(s, x+vx, y, visibleBrickCols)
+		if hp < 0 {
+			b.WriteString(p.streakCell(shownBrick(s, x+vx, y, visibleBrickCols), x+vx))
+			x++
+			continue
+		}
+		n := 1
+		for x+n < w && x+n != ballX && (fx == nil || fx[x+n] == "") && brickHP(s, x+n+vx, y, visibleBrickCols) == hp {
+			n++
+		}
+		b.WriteString(p.hpRun(hp, n))
+		x += n
 	}
-	if hp > 4 {
-		hp = 4
+}
+
+// writeOpenRow writes a row without bricks or paddle: the ball at view column
+// ballX (-1 if absent) over the effect cells fx (non-nil).
+func writeOpenRow(b *bytes.Buffer, w, ballX int, ballCell string, fx []string) {
+	for x := 0; x < w; x++ {
+		switch {
+		case x == ballX:
+			b.WriteString(ballCell)
+		case fx[x] != "":
+			b.WriteString(fx[x])
+		default:
+			b.WriteByte(' ')
+		}
 	}
-	return brickCell1[hp]
 }
 
-func renderFieldFastTo(b *bytes.Buffer, s game.State, clearEOL string, leftPad string, spaceLine string, visibleBrickCols int) {
-	w := s.Width
+// renderFieldFastTo writes the field without overlays. fx holds effects drawn over
+// the field and labels the axis labels around it (nil for none).
+func renderFieldFastTo(b *bytes.Buffer, p *palette, s game.State, fx *fxLayer, labels *axisLabels, clearEOL string, leftPad string, spaceLine string, visibleBrickCols int) {
+	// Everything below is in view coordinates; vx is the first visible field column.
+	w := s.ViewW
 	h := s.Height
+	vx := s.ViewLeft()
 
 	if w <= 0 || h <= 0 {
 		return
 	}
 
-	brickRows := len(s.Bricks)
-	cols := 0
-	if brickRows > 0 {
-		cols = len(s.Bricks[0])
-	}
-	if visibleBrickCols >= cols {
+	if visibleBrickCols >= s.BrickCols {
 		visibleBrickCols = -1 // treat as "all"
 	}
+	bandTop, bandBottom := s.BrickBand()
 
 	py := int(s.PaddleY)
-	bx := int(math.Floor(s.BallX))
+	bx := int(math.Floor(s.BallX)) - vx
 	by := int(math.Floor(s.BallY))
+	paddleCell := p.paddleCell
+	if fx != nil && fx.paddleFlash {
+		paddleCell = p.paddleFlash
+	}
 
 	for y := 0; y < h; y++ {
 		if leftPad != "" {
 			b.WriteString(leftPad)
 		}
-		// Brick rows: output per brick-column (2 spaces each) to keep output small.
-		r := y - s.TopOffset
-		if r >= 0 && r < brickRows {
-			row := s.Bricks[r]
-			// If the ball is on this brick row, render per-char so we can overlay it.
-			if y == by && bx >= 0 && bx < w {
-				for x := range w {
-					if x == bx {
-						b.WriteString(ballCell)
-						continue
-					}
-					c := x / s.BrickW
-					if c >= 0 && c < cols && (visibleBrickCols < 0 || c < visibleBrickCols) {
-						b.WriteString(hpCell1(row[c]))
-					} else {
-						b.WriteByte(' ')
-					}
-				}
-			} else {
-				for c := 0; c < cols; c++ {
-					if visibleBrickCols >= 0 && c >= visibleBrickCols {
-						b.WriteString("  ")
-					} else {
-						b.WriteString(hpSpan2(row[c]))
-					}
-				}
-			}
+		b.WriteString(labels.leftCell(y))
+		fxRow := fx.row(y)
+		ballX := -1
+		if y == by {
+			ballX = bx
+		}
+		if y >= bandTop && y < bandBottom {
+			writeBrickRow(b, p, &s, y, w, vx, ballX, p.ballCell, fxRow, visibleBrickCols)
 			b.WriteString(clearEOL)
 			b.WriteByte('\n')
 			continue
@@ -687,8 +1203,8 @@ func renderFieldFastTo(b *bytes.Buffer, s game.State, clearEOL string, leftPad s
 
 		// Paddle row: small per-char handling for ball overlap.
 		if y == py {
-			x0 := int(s.PaddleX)
-			x1 := int(s.PaddleX + s.PaddleW)
+			x0 := int(s.PaddleX) - vx
+			x1 := int(s.PaddleX+s.PaddleW) - vx
 			if x0 < 0 {
 				x0 = 0
 			}
@@ -697,13 +1213,14 @@ func renderFieldFastTo(b *bytes.Buffer, s game.State, clearEOL string, leftPad s
 			}
 
 			for x := 0; x < w; x++ {
-				if x == bx && y == by {
-					b.WriteString(ballCell)
-					continue
-				}
-				if x >= x0 && x < x1 {
+				switch {
+				case x == ballX:
+					b.WriteString(p.ballCell)
+				case fxRow != nil && fxRow[x] != "":
+					b.WriteString(fxRow[x])
+				case x >= x0 && x < x1:
 					b.WriteString(paddleCell)
-				} else {
+				default:
 					b.WriteByte(' ')
 				}
 			}
@@ -712,12 +1229,26 @@ func renderFieldFastTo(b *bytes.Buffer, s game.State, clearEOL string, leftPad s
 			continue
 		}
 
+		if months := labels.monthRow(y, vx, w); months != nil {
+			writeMonthRow(b, months, ballX, p.ballCell, fxRow)
+			b.WriteString(clearEOL)
+			b.WriteByte('\n')
+			continue
+		}
+
+		if fxRow != nil {
+			writeOpenRow(b, w, ballX, p.ballCell, fxRow)
+			b.WriteString(clearEOL)
+			b.WriteByte('\n')
+			continue
+		}
+
 		// Ball row: write spaces + a single styled ball.
 		if y == by && bx >= 0 && bx < w {
 			if bx > 0 {
 				b.WriteString(spaceLine[:bx])
 			}
-			b.WriteString(ballCell)
+			b.WriteString(p.ballCell)
 			if bx+1 < w {
 				b.WriteString(spaceLine[bx+1:])
 			}
@@ -773,9 +1304,10 @@ func (c *canvasBuf) Set(x, y int, cell string) {
 	c.cells[y*c.w+x] = cell
 }
 
-func renderFieldCanvasTo(out *bytes.Buffer, s game.State, overlay *fieldOverlay, clearEOL string, leftPad string, confetti []confettiParticle, canvas *canvasBuf) {
-	w := s.Width
+func renderFieldCanvasTo(out *bytes.Buffer, p *palette, s game.State, overlay *fieldOverlay, labels *axisLabels, clearEOL string, leftPad string, confetti []confettiParticle, canvas *canvasBuf) {
+	w := s.ViewW
 	h := s.Height
+	vx := s.ViewLeft()
 	if w <= 0 || h <= 0 {
 		return
 	}
@@ -783,34 +1315,50 @@ func renderFieldCanvasTo(out *bytes.Buffer, s game.State, overlay *fieldOverlay,
 	canvas.Resize(w, h)
 	canvas.Fill(" ")
 
+	if my := labels.monthRowY(); my >= 0 {
+		for x, cell := range labels.monthRow(my, vx, w) {
+			if cell != "" {
+				canvas.Set(x, my, cell)
+			}
+		}
+	}
+
 	// Bricks.
-	for r := 0; r < len(s.Bricks); r++ {
-		y := s.TopOffset + r
-		if y < 0 || y >= h {
+	for i := range s.Bricks {
+		br := &s.Bricks[i]
+		if br.HP <= 0 {
 			continue
 		}
-		for c := 0; c < len(s.Bricks[r]); c++ {
-			hp := s.Bricks[r][c]
-			if hp <= 0 {
-				continue
-			}
-			if hp > 4 {
-				hp = 4
+		cell := p.hpCell1(br.HP)
+		for y := br.Y; y < br.Y+br.H; y++ {
+			for x := br.X; x < br.X+br.W; x++ {
+				if br.Streak {
+					cell = p.streakCell(br, x)
+				}
+				canvas.Set(x-vx, y, cell)
 			}
-			cell := brickCell1[hp]
-			x0 := c * s.BrickW
-			for dx := 0; dx < s.BrickW; dx++ {
-				x := x0 + dx
-				canvas.Set(x, y, cell)
+		}
+	}
+
+	// Boss stage.
+	if b := s.Boss; b != nil {
+		for y := int(b.Y); y < int(b.Y)+b.H; y++ {
+			for x := int(b.X); x < int(b.X)+b.W; x++ {
+				if cell := bossCell(p, b, x, y); cell != "" {
+					canvas.Set(x-vx, y, cell)
+				}
 			}
 		}
 	}
+	for _, pr := range s.Projectiles {
+		canvas.Set(int(pr.X)-vx, int(pr.Y), p.hazardCell)
+	}
 
 	// Paddle.
 	py := int(s.PaddleY)
 	if py >= 0 && py < h {
-		x0 := int(s.PaddleX)
-		x1 := int(s.PaddleX + s.PaddleW)
+		x0 := int(s.PaddleX) - vx
+		x1 := int(s.PaddleX+s.PaddleW) - vx
 		if x0 < 0 {
 			x0 = 0
 		}
@@ -818,15 +1366,15 @@ func renderFieldCanvasTo(out *bytes.Buffer, s game.State, overlay *fieldOverlay,
 			x1 = w
 		}
 		for x := x0; x < x1; x++ {
-			canvas.Set(x, py, paddleCell)
+			canvas.Set(x, py, p.paddleCell)
 		}
 	}
 
 	// Ball.
-	bx := int(s.BallX)
+	bx := int(s.BallX) - vx
 	by := int(s.BallY)
 	if by >= 0 && by < h && bx >= 0 && bx < w {
-		canvas.Set(bx, by, ballCell)
+		canvas.Set(bx, by, p.ballCell)
 	}
 
 	// Confetti for party vibes (only used when overlay is active).
@@ -837,13 +1385,14 @@ func renderFieldCanvasTo(out *bytes.Buffer, s game.State, overlay *fieldOverlay,
 	}
 
 	if overlay != nil {
-		applyOverlay(canvas, overlay)
+		applyOverlay(canvas, overlay, p)
 	}
 
 	for y := 0; y < h; y++ {
 		if leftPad != "" {
 			out.WriteString(leftPad)
 		}
+		out.WriteString(labels.leftCell(y))
 		rowOff := y * w
 		for x := 0; x < w; x++ {
 			out.WriteString(canvas.cells[rowOff+x])
@@ -853,7 +1402,7 @@ func renderFieldCanvasTo(out *bytes.Buffer, s game.State, overlay *fieldOverlay,
 	}
 }
 
-func applyOverlay(canvas *canvasBuf, ov *fieldOverlay) {
+func applyOverlay(canvas *canvasBuf, ov *fieldOverlay, p *palette) {
 	h := canvas.h
 	if h == 0 {
 		return
@@ -890,6 +1439,8 @@ func applyOverlay(canvas *canvasBuf, ov *fieldOverlay) {
 
 	if boxW > w {
 		boxW = w
+		// Center and clip text within the narrower box.
+		innerW = max(boxW-4, 0)
 	}
 	if boxH > h {
 		boxH = h
@@ -898,27 +1449,31 @@ func applyOverlay(canvas *canvasBuf, ov *fieldOverlay) {
 	x0 := (w - boxW) / 2
 	y0 := (h - boxH) / 2
 
-	isClear := strings.HasPrefix(ov.Title, "CLEAR")
-	borderColor := "#30363d"
-	titleColor := "#ff7b72"
+	isClear := strings.HasPrefix(ov.Title, "CLEAR") || strings.HasPrefix(ov.Title, "WINNER")
+	borderColor := p.border
+	titleColor := p.alert
 	if isClear {
-		borderColor = "#7ee787"
-		titleColor = "#7ee787"
+		borderColor = p.celebrate
+		titleColor = p.celebrate
 	}
 
-	borderStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(borderColor))
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(titleColor))
-	scoreStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffd33d"))
-	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d0d7de"))
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
-	panelStyle := lipgloss.NewStyle().Background(lipgloss.Color("#161b22"))
+	borderStyle := lipgloss.NewStyle().Bold(true).Foreground(borderColor)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(titleColor)
+	scoreStyle := lipgloss.NewStyle().Bold(true).Foreground(p.scoreColor)
+	textStyle := lipgloss.NewStyle().Foreground(p.overlayText)
+	helpStyle := lipgloss.NewStyle().Foreground(p.helpColor)
+	panelStyle := lipgloss.NewStyle().Background(p.panel)
+	if p.plain {
+		borderStyle, titleStyle, scoreStyle = lipgloss.Style{}, lipgloss.Style{}, lipgloss.Style{}
+		textStyle, helpStyle, panelStyle = lipgloss.Style{}, lipgloss.Style{}, lipgloss.Style{}
+	}
 
 	put := func(x, y int, cell string) {
 		canvas.Set(x, y, cell)
 	}
 
 	// Fill panel background.
-	bgCell := panelStyle.Render(" ")
+	bgCell := panelStyle.Render(p.overlay.fill)
 	for y := y0; y < y0+boxH; y++ {
 		for x := x0; x < x0+boxW; x++ {
 			put(x, y, bgCell)
@@ -926,12 +1481,16 @@ func applyOverlay(canvas *canvasBuf, ov *fieldOverlay) {
 	}
 
 	// Borders (box drawing for a cuter look).
-	hLine := borderStyle.Render("─")
-	vLine := borderStyle.Render("│")
-	cTL := borderStyle.Render("╭")
-	cTR := borderStyle.Render("╮")
-	cBL := borderStyle.Render("╰")
-	cBR := borderStyle.Render("╯")
+	box := p.overlay.box
+	if isClear {
+		box = p.overlay.celebrateBox
+	}
+	hLine := borderStyle.Render(box.h)
+	vLine := borderStyle.Render(box.v)
+	cTL := borderStyle.Render(box.tl)
+	cTR := borderStyle.Render(box.tr)
+	cBL := borderStyle.Render(box.bl)
+	cBR := borderStyle.Render(box.br)
 
 	for x := x0 + 1; x < x0+boxW-1; x++ {
 		put(x, y0, hLine)
@@ -0,0 +1,362 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
+)
+
+// NetModel is the Bubble Tea model of a networked match (see netplay): this
+// end's board at full size, with the opponent's board beside it in a
+// half-size mini view. Speed and retry do not apply; both ends share the
+// host's settings.
+type NetModel struct {
+	match *netplay.Match
+	keys  map[string]keyAction
+	help  keyHelp
+
+	themeSet
+	effectsOn bool
+	rng       *rand.Rand
+	sound     sound.Player
+	bell      bool // see Model.bell
+
+	hold      keyHold
+	fx        effects
+	fxLayer   fxLayer
+	canvas    canvasBuf
+	buf       bytes.Buffer
+	spaceLine string
+
+	countdown float64
+	// err ended the match early, e.g. netplay.ErrPeerLeft.
+	err error
+
+	lastTick time.Time
+	acc      float64
+
+	ready bool
+	w     int
+	h     int
+
+	viewBuf bytes.Buffer
+}
+
+// NewNetModel returns the model of match. The caller closes match after the
+// program exits.
+func NewNetModel(match *netplay.Match, opts Options) *NetModel {
+	set := resolveOptions(match.Settings.Seed, opts)
+	m := &NetModel{
+		match:     match,
+		keys:      set.keys,
+		help:      set.help,
+		themeSet:  set.theme,
+		effectsOn: set.effectsOn,
+		rng:       set.rng,
+		sound:     set.sound,
+		countdown: versusCountdown,
+		spaceLine: strings.Repeat(" ", max(match.States[match.Local].ViewW, 0)),
+	}
+	return m
+}
+
+// NetSteps returns the Settings.Steps of a networked match at game speed
+// speed that lasts limit of wall time, as a versus match does.
+func NetSteps(limit time.Duration, speed float64) int {
+	return int(math.Round(limit.Seconds() * speed * baseSpeedMultiplier / netplay.StepDT))
+}
+
+func (m *NetModel) Init() tea.Cmd {
+	return tickCmd(time.Second / 60)
+}
+
+func (m *NetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The boards have a fixed size; only the centering changes.
+		m.w = msg.Width
+		m.h = msg.Height
+		m.ready = true
+		return m, nil
+	case tickMsg:
+		now := time.Time(msg)
+		if m.lastTick.IsZero() {
+			m.lastTick = now
+			return m, tickCmd(time.Second / 60)
+		}
+		dt := min(max(now.Sub(m.lastTick).Seconds(), 0), 0.05)
+		m.lastTick = now
+		m.update(dt)
+		if m.sound != nil {
+			m.sound.Advance(dt)
+			m.bell = ringing(m.sound)
+		}
+		return m, tickCmd(time.Second / 60)
+	case tea.KeyMsg:
+		switch m.keys[msg.String()] {
+		case actionQuit:
+			return m, tea.Quit
+		case actionTheme:
+			m.cycleTheme()
+		case actionLeft:
+			m.hold.press(-1)
+		case actionRight:
+			m.hold.press(1)
+		}
+		return m, nil
+	case keyReleaseMsg:
+		switch m.keys[msg.String()] {
+		case actionLeft:
+			m.hold.release(-1)
+		case actionRight:
+			m.hold.release(1)
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// update advances the match by dt seconds of wall time. Steps run only as fast
+// as the peer's inputs arrive.
+func (m *NetModel) update(dt float64) {
+	if _, over := m.match.Result(); !m.ready || over || m.err != nil {
+		return
+	}
+	if m.countdown > 0 {
+		m.countdown -= dt
+		return
+	}
+
+	move := m.hold.advance(dt)
+	m.acc += dt * (m.match.Settings.Speed * baseSpeedMultiplier)
+	const maxStepsPerTick = 10
+	steps := int(m.acc / netplay.StepDT)
+	if steps > maxStepsPerTick {
+		steps = maxStepsPerTick
+		m.acc = math.Mod(m.acc, netplay.StepDT)
+	} else {
+		m.acc -= float64(steps) * netplay.StepDT
+	}
+	if _, err := m.match.Advance(steps, move, m.handleEvents); err != nil {
+		m.err = err
+	}
+	if m.effectsOn {
+		s := &m.match.States[m.match.Local]
+		m.fx.update(dt, s.BallX, s.BallY)
+	}
+}
+
+// handleEvents plays effects and sounds for this end's board only.
+func (m *NetModel) handleEvents(board int, events []game.Event) {
+	if board != m.match.Local {
+		return
+	}
+	if m.effectsOn {
+		m.fx.handle(events, &m.match.States[board], m.rng)
+	}
+	if m.sound != nil {
+		m.sound.Handle(events)
+	}
+}
+
+// overlay returns the overlay of this end's board, or nil while it is in play.
+func (m *NetModel) overlay() *fieldOverlay {
+	local := m.match.Local
+	s := &m.match.States[local]
+	lines := []string{scoreLine(s), "user: " + m.match.Players[local].Login}
+	quit := "press " + m.help.key(actionQuit) + " to quit"
+	winner, over := m.match.Result()
+	switch {
+	case errors.Is(m.err, netplay.ErrDesync) && !over:
+		return &fieldOverlay{Title: "OUT OF SYNC", Lines: append(lines, "the games differ"), Footer: quit}
+	case m.err != nil && !over:
+		return &fieldOverlay{Title: "DISCONNECTED", Lines: append(lines, m.err.Error()), Footer: quit}
+	case !over && s.GameOver:
+		return &fieldOverlay{
+			Title:  "OUT OF BALLS",
+			Lines:  lines,
+			Footer: "waiting for " + m.match.Players[1-local].Login + "...",
+		}
+	case !over:
+		return nil
+	case winner == local:
+		title := "WINNER!"
+		if s.Cleared {
+			title = "WINNER!  CLEARED"
+		}
+		return &fieldOverlay{Title: title, Lines: lines, Footer: quit}
+	case winner < 0:
+		return &fieldOverlay{Title: "DRAW", Lines: lines, Footer: quit}
+	default:
+		return &fieldOverlay{Title: "NICE TRY", Lines: lines, Footer: quit}
+	}
+}
+
+func (m *NetModel) View() string {
+	if !m.ready {
+		return "loading...\n"
+	}
+	m.viewBuf.Reset()
+	b := &m.viewBuf
+	if m.bell {
+		b.WriteByte('\a')
+	}
+	p := m.pal
+	local, other := m.match.Local, 1-m.match.Local
+	s := m.match.States[local]
+
+	miniW, miniH := (netplay.FieldW+1)/2, (netplay.FieldH+1)/2
+	contentW := netplay.FieldW + 3 + miniW
+	leftPad := ""
+	if m.w > contentW {
+		leftPad = strings.Repeat(" ", (m.w-contentW)/2)
+	}
+	// Lines: HUD(1) + info(1) + field(height) + trailing blank(1)
+	if contentH := 1 + 1 + netplay.FieldH + 1; m.h > contentH {
+		b.WriteString(strings.Repeat("\n", (m.h-contentH)/2))
+	}
+
+	b.WriteString(leftPad)
+	b.WriteString(m.hud())
+	b.WriteString("\n")
+	b.WriteString(leftPad)
+	b.WriteString(m.infoLine())
+	b.WriteString("\n")
+
+	// Center the board in its fixed-size slot.
+	pad := strings.Repeat(" ", max(netplay.FieldW-s.ViewW, 0)/2)
+	m.buf.Reset()
+	if ov := m.overlay(); ov != nil {
+		renderFieldCanvasTo(&m.buf, p, s, ov, nil, "", pad, nil, &m.canvas)
+	} else {
+		var fx *fxLayer
+		if m.effectsOn && m.fx.active() {
+			m.fx.draw(&m.fxLayer, p, &s)
+			fx = &m.fxLayer
+		}
+		if len(s.Drops) > 0 {
+			if fx == nil {
+				m.fxLayer.resize(s.ViewW, s.Height)
+				fx = &m.fxLayer
+			}
+			drawDrops(fx, p, &s)
+		}
+		if g := p.sub[m.subcell]; g != nil {
+			renderFieldSubCellTo(&m.buf, p, g, s, fx, nil, "", pad, m.spaceLine, -1)
+		} else {
+			renderFieldFastTo(&m.buf, p, s, fx, nil, "", pad, m.spaceLine, -1)
+		}
+	}
+
+	// The opponent's mini view sits beside the top of the board, with their
+	// name and score below it.
+	opp := &m.match.States[other]
+	sep := p.hudDim.Render(" " + p.overlay.box.v + " ")
+	field := m.buf.Bytes()
+	for y := range netplay.FieldH {
+		var line []byte
+		line, field = cutLine(field)
+		b.WriteString(leftPad)
+		b.Write(line)
+		b.WriteString(strings.Repeat(" ", max(netplay.FieldW-len(pad)-s.ViewW, 0)))
+		b.WriteString(sep)
+		switch {
+		case y < miniH:
+			writeMiniRow(b, p, opp, y)
+		case y == miniH+1:
+			b.WriteString(p.hudValue.Render(m.match.Players[other].Login))
+		case y == miniH+2:
+			b.WriteString(p.hudLabel.Render("score ") + p.hudScore.Render(fmt.Sprintf("%d", opp.Score)))
+		case y == miniH+3:
+			b.WriteString(p.hudLabel.Render("blocks ") + p.hudValue.Render(fmt.Sprintf("%d/%d", opp.BricksRemaining, opp.BricksTotal)))
+		case y == miniH+4 && opp.GameOver:
+			b.WriteString(p.hudDim.Render("out of balls"))
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// writeMiniRow writes row y of a half-size view of s, where each cell stands
+// for 2x2 field cells and shows the ball, the paddle or the strongest brick
+// among them.
+func writeMiniRow(b *bytes.Buffer, p *palette, s *game.State, y int) {
+	vx := s.ViewLeft()
+	bx := int(math.Floor(s.BallX)) - vx
+	by := int(math.Floor(s.BallY))
+	py := int(s.PaddleY)
+	px0, px1 := int(s.PaddleX)-vx, int(s.PaddleX+s.PaddleW)-vx
+	for x := range (s.ViewW + 1) / 2 {
+		fx, fy := 2*x, 2*y
+		switch {
+		case bx >= 0 && bx/2 == x && by >= 0 && by/2 == y:
+			b.WriteString(p.ballCell)
+		case py/2 == y && fx+1 >= px0 && fx < px1:
+			b.WriteString(p.paddleCell)
+		default:
+			hp := 0
+			for _, c := range [4][2]int{{fx, fy}, {fx + 1, fy}, {fx, fy + 1}, {fx + 1, fy + 1}} {
+				if br := shownBrick(s, c[0]+vx, c[1], -1); br != nil {
+					hp = max(hp, min(br.HP, 4))
+				}
+			}
+			if hp > 0 {
+				b.WriteString(p.brickCell1[hp])
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// hud shows this end's score and blocks left, the match clock and the
+// opponent's score.
+func (m *NetModel) hud() string {
+	p := m.pal
+	sep := p.hudDim.Render("  |  ")
+	player := func(i int) string {
+		s := &m.match.States[i]
+		out := p.hudValue.Render(m.match.Players[i].Login) + " " +
+			p.hudLabel.Render("score ") + p.hudScore.Render(fmt.Sprintf("%6d", s.Score))
+		if i == m.match.Local {
+			out += " " + p.hudLabel.Render("blocks ") + p.hudValue.Render(fmt.Sprintf("%d/%d", s.BricksRemaining, s.BricksTotal))
+			out += " " + p.hudLabel.Render("lives ") + p.hudValue.Render(fmt.Sprintf("%d", s.Lives))
+		}
+		return out
+	}
+	// The clock shows wall time (see NetSteps).
+	left := int(math.Ceil(float64(m.match.StepsLeft()) * netplay.StepDT / (m.match.Settings.Speed * baseSpeedMultiplier)))
+	clock := p.hudLabel.Render("time ") + p.hudValue.Render(fmt.Sprintf("%d:%02d", left/60, left%60))
+	return player(m.match.Local) + sep + clock + sep + player(1-m.match.Local)
+}
+
+// infoLine shows the countdown, the result, a stalled connection, or the keys.
+func (m *NetModel) infoLine() string {
+	quit := " (" + m.help.key(actionQuit) + " quit)"
+	winner, over := m.match.Result()
+	switch {
+	case over && winner >= 0:
+		return m.match.Players[winner].Login + " wins!" + quit
+	case over:
+		return "draw!" + quit
+	case m.err != nil:
+		return "connection lost" + quit
+	case m.countdown > 0:
+		return fmt.Sprintf("get ready... %d", int(math.Ceil(m.countdown)))
+	case m.match.Waiting():
+		return "waiting for " + m.match.Players[1-m.match.Local].Login + "..."
+	}
+	return fmt.Sprintf("%s: move   (%s theme, %s quit)   vs %s online",
+		m.help.move(m.pal.arrows), m.help.key(actionTheme), m.help.key(actionQuit), m.match.Players[1-m.match.Local].Login)
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/netplay"
+)
+
+// netModels connects alice (host) and bob (guest) over loopback and returns
+// both ends' models on an 80x30 terminal, past the countdown.
+func netModels(t *testing.T, set netplay.Settings) (host, guest *NetModel) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	errc := make(chan error, 1)
+	var hm *netplay.Match
+	go func() {
+		var err error
+		hm, err = netplay.Host(ctx, ln, netplay.Player{Login: "alice", Calendar: versusCalendar()}, set)
+		errc <- err
+	}()
+	gm, err := netplay.Join(ctx, ln.Addr().String(), netplay.Player{Login: "bob", Calendar: versusCalendar()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		hm.Close()
+		gm.Close()
+	})
+
+	models := [2]*NetModel{NewNetModel(hm, Options{ASCII: true}), NewNetModel(gm, Options{ASCII: true})}
+	for _, m := range models {
+		m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+		m.countdown = 0
+	}
+	return models[0], models[1]
+}
+
+// updateBoth ticks both models until each has simulated steps steps.
+func updateBoth(t *testing.T, a, b *NetModel, steps int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for a.match.StepsLeft() > a.match.Settings.Steps-steps || b.match.StepsLeft() > b.match.Settings.Steps-steps {
+		if _, over := a.match.Result(); over {
+			return
+		}
+		a.update(1.0 / 60)
+		b.update(1.0 / 60)
+		if time.Now().After(deadline) {
+			t.Fatalf("stuck with %d and %d steps left", a.match.StepsLeft(), b.match.StepsLeft())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNetModel_MiniViewAndLockstep(t *testing.T) {
+	t.Parallel()
+
+	// A one-minute match; the clock shows wall time.
+	set := netplay.Settings{Seed: 3, Lives: 3, Speed: 2, Steps: NetSteps(time.Minute, 2)}
+	host, guest := netModels(t, set)
+	host.Update(key("a"))
+	guest.Update(tea.KeyMsg{Type: tea.KeyRight})
+	updateBoth(t, host, guest, 120)
+
+	for i := range 2 {
+		a, b := &host.match.States[i], &guest.match.States[i]
+		if a.PaddleX != b.PaddleX || a.BallX != b.BallX || a.BallY != b.BallY {
+			t.Fatalf("board %d differs between the ends", i)
+		}
+	}
+	v := host.View()
+	for _, want := range []string{"alice score", "bob score", "time 1:00", "vs bob online"} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("view should contain %q:\n%s", want, v)
+		}
+	}
+	// The mini view is half the board's size, beside it.
+	lines := strings.Split(v, "\n")
+	var board string
+	for _, l := range lines {
+		if strings.Contains(l, "=") && strings.Contains(l, "|") {
+			board = l
+			break
+		}
+	}
+	if board == "" {
+		t.Fatalf("expected the mini view beside the board:\n%s", v)
+	}
+}
+
+func TestNetModel_ResultAndDisconnect(t *testing.T) {
+	t.Parallel()
+
+	host, guest := netModels(t, netplay.Settings{Seed: 3, Speed: 1, Steps: 60})
+	host.match.States[1].Award(game.ScoreBricks, 100)
+	guest.match.States[1].Award(game.ScoreBricks, 100)
+	updateBoth(t, host, guest, 60)
+	if v := guest.View(); !strings.Contains(v, "WINNER!") || !strings.Contains(v, "bob wins!") {
+		t.Fatalf("bob should win on points:\n%s", v)
+	}
+	if v := host.View(); !strings.Contains(v, "NICE TRY") {
+		t.Fatalf("alice should lose:\n%s", v)
+	}
+
+	host, guest = netModels(t, netplay.Settings{Seed: 3, Speed: 1, Steps: 60 * 120})
+	guest.match.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for host.err == nil && time.Now().Before(deadline) {
+		host.update(1.0 / 60)
+		time.Sleep(time.Millisecond)
+	}
+	if v := host.View(); !strings.Contains(v, "DISCONNECTED") || !strings.Contains(v, "connection lost") {
+		t.Fatalf("expected a disconnect overlay:\n%s", v)
+	}
+
+	host.err = fmt.Errorf("%w: at step 60", netplay.ErrDesync)
+	if v := host.View(); !strings.Contains(v, "OUT OF SYNC") {
+		t.Fatalf("expected a desync overlay:\n%s", v)
+	}
+}
@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
+)
+
+// benchGrid is a full 7x52 board of 2025 with every HP and a streak through
+// weeks 10..11.
+func benchGrid() mapping.BrickGrid {
+	g := mapping.BrickGrid{Rows: 7, Cols: 52, MonthStarts: make([]bool, 52), RowWeekdays: make([]int, 7)}
+	for r := 0; r < g.Rows; r++ {
+		row := make([]mapping.BrickCell, g.Cols)
+		for c := range row {
+			hp := (r+c)%4 + 1
+			row[c] = mapping.BrickCell{Count: hp, HP: hp, Streak: c == 10 || c == 11}
+		}
+		g.Cells = append(g.Cells, row)
+		g.RowWeekdays[r] = r
+	}
+	for c := range g.Cols {
+		g.ColMonths = append(g.ColMonths, fmt.Sprintf("2025-%02d", c*12/g.Cols+1))
+	}
+	return g
+}
+
+// benchState returns benchGrid in a 120x40 terminal with the ball inside the
+// brick band, so brick rows are split around it.
+func benchState() game.State {
+	s := game.NewState(benchGrid(), 118, 36, 1)
+	top, _ := s.BrickBand()
+	s.BallX, s.BallY = 40.3, float64(top)+2.6
+	return s
+}
+
+type frameRenderer func(b *bytes.Buffer, p *palette, s game.State, fx *fxLayer, labels *axisLabels, spaceLine string)
+
+func renderers() map[string]frameRenderer {
+	sub := func(mode SubCell) frameRenderer {
+		return func(b *bytes.Buffer, p *palette, s game.State, fx *fxLayer, labels *axisLabels, spaceLine string) {
+			renderFieldSubCellTo(b, p, p.sub[mode], s, fx, labels, "", "  ", spaceLine, -1)
+		}
+	}
+	return map[string]frameRenderer{
+		"fast": func(b *bytes.Buffer, p *palette, s game.State, fx *fxLayer, labels *axisLabels, spaceLine string) {
+			renderFieldFastTo(b, p, s, fx, labels, "", "  ", spaceLine, -1)
+		},
+		"half":    sub(SubCellHalf),
+		"braille": sub(SubCellBraille),
+	}
+}
+
+// benchEffects returns a layer with fragments over the bricks, a ball trail and
+// a flashing paddle.
+func benchEffects(s *game.State, p *palette) *fxLayer {
+	var e effects
+	rng := rand.New(rand.NewPCG(1, 2))
+	top, bottom := s.BrickBand()
+	e.burst(30, float64(top+bottom)/2, 3, 20, 14, rng)
+	e.burst(s.BallX, s.PaddleY-3, 1, 10, 8, rng)
+	e.flash = flashTTL
+	for i := range 4 {
+		e.update(0.01, s.BallX-float64(i), s.BallY+float64(i))
+	}
+	var l fxLayer
+	e.draw(&l, p, s)
+	return &l
+}
+
+func TestRenderers_DoNotAllocate(t *testing.T) {
+	s := benchState()
+	p := newPalette(BuiltinThemes()[0], GlyphsOff)
+	spaceLine := strings.Repeat(" ", s.ViewW)
+	layers := map[string]*fxLayer{"no effects": nil, "effects": benchEffects(&s, p)}
+	labels := newAxisLabels(p, benchGrid(), &s, 200)
+	if labels.width() == 0 || labels.monthRowY() < 0 {
+		t.Fatalf("expected weekday and month labels")
+	}
+	for name, render := range renderers() {
+		for fxName, fx := range layers {
+			for _, l := range []*axisLabels{nil, labels} {
+				var b bytes.Buffer
+				render(&b, p, s, fx, l, spaceLine) // warm the run cache and buffer
+				allocs := testing.AllocsPerRun(20, func() {
+					b.Reset()
+					render(&b, p, s, fx, l, spaceLine)
+				})
+				if allocs != 0 {
+					t.Errorf("%s, %s, labels %t: %.0f allocations per frame, want 0", name, fxName, l != nil, allocs)
+				}
+				if got := strings.Count(b.String(), "\n"); got != s.Height {
+					t.Errorf("%s, %s, labels %t: rendered %d rows, want %d", name, fxName, l != nil, got, s.Height)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkRenderField(b *testing.B) {
+	s := benchState()
+	p := newPalette(BuiltinThemes()[0], GlyphsOff)
+	spaceLine := strings.Repeat(" ", s.ViewW)
+	for _, name := range []string{"fast", "half", "braille"} {
+		render := renderers()[name]
+		b.Run(name, func(b *testing.B) {
+			var buf bytes.Buffer
+			render(&buf, p, s, nil, nil, spaceLine)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				buf.Reset()
+				render(&buf, p, s, nil, nil, spaceLine)
+			}
+		})
+	}
+}
+
+func TestRenderers_GlyphCodedHP(t *testing.T) {
+	t.Parallel()
+
+	theme := BuiltinThemes()[0]
+	tests := []struct {
+		name string
+		p    *palette
+		// want holds the cell of HP 1..4, or "" where color alone shows HP.
+		want [4]string
+	}{
+		{"off", newPalette(theme, GlyphsOff), [4]string{}},
+		{"shade", newPalette(theme, GlyphsShade), [4]string{"░", "▒", "▓", "█"}},
+		{"digits", newPalette(theme, GlyphsDigits), [4]string{"1", "2", "3", "4"}},
+		{"ascii", newASCIIPalette(GlyphsOff), [4]string{"1", "2", "3", "4"}},
+		{"ascii shade", newASCIIPalette(GlyphsShade), [4]string{".", ":", "%", "#"}},
+	}
+	s := benchState()
+	spaceLine := strings.Repeat(" ", s.ViewW)
+	for _, tt := range tests {
+		for name, render := range renderers() {
+			if tt.p.plain && name != "fast" {
+				continue // ASCII has no sub-cell modes
+			}
+			var b bytes.Buffer
+			render(&b, tt.p, s, nil, nil, spaceLine)
+			out := b.String()
+			for hp, cell := range tt.want {
+				if cell != "" && !strings.Contains(out, cell) {
+					t.Errorf("%s, %s: HP %d should show as %q", tt.name, name, hp+1, cell)
+				}
+			}
+			if tt.want[0] == "" && strings.ContainsAny(out, "░▒▓█1234") {
+				t.Errorf("%s, %s: bricks should be colored blanks", tt.name, name)
+			}
+			if tt.want[0] != "" && !strings.Contains(tt.p.legend, strings.Join(tt.want[:], "")) {
+				t.Errorf("%s: the legend %q should list %v", tt.name, tt.p.legend, tt.want)
+			}
+		}
+	}
+}
+
+func TestApplyOverlay_GlyphBorders(t *testing.T) {
+	t.Parallel()
+
+	theme := BuiltinThemes()[0]
+	tests := []struct {
+		name           string
+		p              *palette
+		alert, cleared string // top-left corners
+	}{
+		{"off", newPalette(theme, GlyphsOff), "╭", "╭"},
+		{"shade", newPalette(theme, GlyphsShade), "╭", "╔"},
+		{"digits", newPalette(theme, GlyphsDigits), "╭", "╔"},
+		{"ascii", newASCIIPalette(GlyphsOff), "+", "*"},
+	}
+	s := benchState()
+	for _, tt := range tests {
+		for _, ov := range []struct {
+			title, corner string
+		}{{"PAUSED", tt.alert}, {"GAME OVER", tt.alert}, {"CLEAR!", tt.cleared}} {
+			var b bytes.Buffer
+			var canvas canvasBuf
+			renderFieldCanvasTo(&b, tt.p, s, &fieldOverlay{Title: ov.title, Lines: []string{"score: 1"}}, nil, "", "", nil, &canvas)
+			out := b.String()
+			if !strings.Contains(out, ov.title) || !strings.Contains(out, ov.corner) {
+				t.Errorf("%s, %s: expected a box with corner %q:\n%s", tt.name, ov.title, ov.corner, out)
+			}
+		}
+	}
+}
+
+func TestView_DrawsTheBell(t *testing.T) {
+	t.Parallel()
+
+	bell := sound.NewBell()
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true, Sound: bell})
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m.introActive = false
+	now := time.Now()
+	m.Update(tickMsg(now))
+	if strings.Contains(m.View(), "\a") {
+		t.Fatalf("nothing rang yet")
+	}
+
+	// The bell stays in every frame until the next tick, then goes.
+	bell.Handle([]game.Event{{Kind: game.EventBrickDestroyed}})
+	now = now.Add(time.Second / 60)
+	m.Update(tickMsg(now))
+	for range 2 {
+		if !strings.HasPrefix(m.View(), "\a") {
+			t.Fatalf("the frame should ring the bell")
+		}
+	}
+	now = now.Add(time.Second / 60)
+	m.Update(tickMsg(now))
+	if strings.Contains(m.View(), "\a") {
+		t.Fatalf("the bell should ring once")
+	}
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+)
+
+// statsLines formats the engine's game statistics for the end-of-game overlay,
+// most interesting first. playTime is the wall time with a ball in play, in
+// seconds: Stats.PlayTime runs on game time, which the speed setting scales.
+// Overlay text is ASCII only.
+func statsLines(s *game.State, playTime float64) []string {
+	st := &s.Stats
+	secs := int(playTime)
+
+	var tiers strings.Builder
+	for hp := 1; hp <= mapping.MaxHP; hp++ {
+		if hp > 1 {
+			tiers.WriteByte(' ')
+		}
+		fmt.Fprintf(&tiers, "%d:%d", hp, st.Destroyed[hp])
+	}
+
+	busiest := "-"
+	if st.Busiest >= 0 {
+		b := &s.Bricks[st.Busiest]
+		busiest = fmt.Sprintf("%s (%d)", dayRange(b.First, b.Last), b.Count)
+	}
+
+	return []string{
+		fmt.Sprintf("contributions destroyed: %d%%", int(st.DestroyedShare()*100)),
+		fmt.Sprintf("busiest day: %s", busiest),
+		fmt.Sprintf("time %d:%02d  paddle hits %d", secs/60, secs%60, st.PaddleHits),
+		fmt.Sprintf("longest rally %d  accuracy %d%%", st.LongestRally, int(st.Accuracy()*100+0.5)),
+		fmt.Sprintf("bricks by hp  %s", tiers.String()),
+	}
+}
+
+// withStats appends as many stats lines to lines as fit an overlay box (border,
+// padding, title and footer included) in a field h rows tall.
+func withStats(lines []string, s *game.State, playTime float64, h int) []string {
+	room := h - 6 - len(lines)
+	stats := statsLines(s, playTime)
+	if room <= 0 {
+		return lines
+	}
+	return append(lines, stats[:min(room, len(stats))]...)
+}
+
+// breakdownLines formats where the score came from, for the CLEAR overlay.
+func breakdownLines(s *game.State) []string {
+	b := &s.Breakdown
+	return []string{
+		fmt.Sprintf("bricks %d  combo %d  streak %d", b[game.ScoreBricks], b[game.ScoreCombo], b[game.ScoreStreak]),
+		fmt.Sprintf("time bonus %d  life bonus %d", b[game.ScoreTime], b[game.ScoreLives]),
+	}
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+func TestStatsLines(t *testing.T) {
+	t.Parallel()
+
+	s := benchState()
+	s.Stats.PlayTime = 104.25
+	s.Stats.PaddleHits, s.Stats.BallsLost, s.Stats.LongestRally = 7, 1, 5
+	s.Stats.Destroyed[3] = 2
+	s.Stats.ContributionsDestroyed, s.Stats.ContributionsTotal = 25, 100
+	s.Stats.Busiest = 3
+	s.Bricks[3].Count, s.Bricks[3].First, s.Bricks[3].Last = 23, "2025-03-14", "2025-03-14"
+
+	got := strings.Join(statsLines(&s, 83.4), "\n")
+	for _, want := range []string{
+		"contributions destroyed: 25%",
+		"busiest day: 2025-03-14 (23)",
+		"time 1:23  paddle hits 7",
+		"longest rally 5  accuracy 88%",
+		"bricks by hp  1:0 2:0 3:2 4:0",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWithStats_FitsField(t *testing.T) {
+	t.Parallel()
+
+	s := game.State{Stats: game.Stats{Busiest: -1}}
+	base := []string{"score", "user"}
+	if got := withStats(base, &s, 0, 30); len(got) != len(base)+len(statsLines(&s, 0)) {
+		t.Fatalf("a tall field should show every stat, got %d lines", len(got))
+	}
+	if got := withStats(base, &s, 0, 10); len(got) != 4 {
+		t.Fatalf("a 10-row field has room for 2 stats, got %v", got)
+	}
+	if got := withStats(base, &s, 0, 6); len(got) != len(base) {
+		t.Fatalf("a tiny field should show no stats, got %v", got)
+	}
+}
+
+func TestBreakdownLines(t *testing.T) {
+	t.Parallel()
+
+	s := game.State{}
+	s.Award(game.ScoreBricks, 1230)
+	s.Award(game.ScoreCombo, 340)
+	s.Award(game.ScoreLives, 1000)
+
+	got := strings.Join(breakdownLines(&s), "\n")
+	for _, want := range []string{"bricks 1230  combo 340  streak 0", "time bonus 0  life bonus 1000"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestHUD_DifficultyAndBallSpeed(t *testing.T) {
+	t.Parallel()
+
+	s := benchState()
+	s.Score = 1234
+	s.Difficulty, _ = game.ParseDifficulty("insane")
+	if got := scoreLine(&s); got != "score:     1234 (insane)" {
+		t.Fatalf("unexpected score line %q", got)
+	}
+	hud := renderHUD(newASCIIPalette(GlyphsOff), "me", s.Score, 3, 10, 0, 1.5, 1.2, s.Difficulty.Name, nil, "")
+	if !strings.Contains(hud, "level insane") {
+		t.Fatalf("HUD should show the difficulty, got %q", hud)
+	}
+	// The ball speed is the ramp's pace on top of the user speed.
+	if !strings.Contains(hud, "speed 1.50x ball 1.80x") {
+		t.Fatalf("HUD should show the ball speed, got %q", hud)
+	}
+}
+
+func TestModel_PlayTimeIsWallTime(t *testing.T) {
+	t.Parallel()
+
+	m := NewModel("octocat", inspectCalendar(), 1, Options{ASCII: true, Speed: 2, Lives: 9})
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m.introActive = false
+	now := time.Unix(0, 0)
+	m.Update(tickMsg(now)) // starts the clock
+	for range 60 {
+		now = now.Add(time.Second / 60)
+		m.Update(tickMsg(now))
+	}
+	// Two seconds of game time at 2x (and the 1.25 baseline) took one second.
+	if math.Abs(m.playTime-1) > 0.01 || m.state.Stats.PlayTime < 2 {
+		t.Fatalf("expected 1s of wall time and 2.5s of game time, got %.3f and %.3f", m.playTime, m.state.Stats.PlayTime)
+	}
+	m.Update(key("r"))
+	if m.playTime != 0 {
+		t.Fatalf("retry should reset the play time, got %.3f", m.playTime)
+	}
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"math"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// dropCells renders the falling power-ups (see palette.dropCells).
+func dropCells(st lipgloss.Style) [4]string {
+	return [4]string{
+		game.PowerWide: st.Render("W"),
+		game.PowerSlow: st.Render("S"),
+		game.PowerLife: st.Render("+"),
+	}
+}
+
+// streakCell returns the cell of streak brick br at field column x.
+func (p *palette) streakCell(br *game.Brick, x int) string {
+	cells := &p.streakCells[min(max(br.HP, 1), 4)]
+	switch {
+	case br.W == 1:
+		return cells[3]
+	case x == br.X:
+		return cells[0]
+	case x == br.X+br.W-1:
+		return cells[2]
+	default:
+		return cells[1]
+	}
+}
+
+// drawDrops adds the falling power-ups of s to l (already sized for its view).
+func drawDrops(l *fxLayer, p *palette, s *game.State) {
+	vx := s.ViewLeft()
+	for _, d := range s.Drops {
+		if d.Power > 0 && int(d.Power) < len(p.dropCells) {
+			l.put(int(math.Floor(d.X))-vx, int(math.Floor(d.Y)), p.dropCells[d.Power])
+		}
+	}
+}
+
+// powerUpNotice is the info line shown when the paddle catches a power-up.
+func powerUpNotice(ev game.Event) string {
+	return "Power-up: " + ev.Power.String() + "!"
+}
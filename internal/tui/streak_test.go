@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+func TestStreakBricks_Outline(t *testing.T) {
+	t.Parallel()
+
+	p := newASCIIPalette(GlyphsOff)
+	s := benchState()
+	s.BallY = 0
+	top, _ := s.BrickBand()
+	// Columns 10 and 11 are streak bricks: x 20..23.
+	want := s.Bricks[s.BrickAt(18, top)]
+	prefix := strings.Repeat(p.hpCell1(want.HP), 2)
+
+	var b bytes.Buffer
+	renderFieldFastTo(&b, p, s, nil, nil, "", "", strings.Repeat(" ", s.ViewW), -1)
+	if got := strings.Split(b.String(), "\n")[top][18:24]; got != prefix+"[][]" {
+		t.Fatalf("fast: streak bricks should be outlined, got %q", got)
+	}
+
+	b.Reset()
+	renderFieldCanvasTo(&b, p, s, nil, nil, "", "", nil, &canvasBuf{})
+	if got := strings.Split(b.String(), "\n")[top][18:24]; got != prefix+"[][]" {
+		t.Fatalf("canvas: streak bricks should be outlined, got %q", got)
+	}
+}
+
+func TestDrops_Drawn(t *testing.T) {
+	t.Parallel()
+
+	p := newASCIIPalette(GlyphsOff)
+	s := benchState()
+	s.Drops = []game.Drop{{X: 5.5, Y: 30.2, Power: game.PowerWide}, {X: 9, Y: 31, Power: game.PowerLife}}
+	var l fxLayer
+	l.resize(s.ViewW, s.Height)
+	drawDrops(&l, p, &s)
+	if got := l.row(30)[5] + l.row(31)[9]; got != "W+" {
+		t.Fatalf("expected the drops drawn at their cells, got %q", got)
+	}
+	if got := powerUpNotice(game.Event{Kind: game.EventPowerUp, Power: game.PowerSlow}); got != "Power-up: slow ball!" {
+		t.Fatalf("unexpected notice %q", got)
+	}
+}
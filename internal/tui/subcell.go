@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// SubCell selects a renderer that draws the ball and paddle at finer than cell
+// resolution. Bricks look the same in every mode.
+type SubCell int
+
+const (
+	// SubCellOff draws one game unit per cell (default).
+	SubCellOff SubCell = iota
+	// SubCellHalf uses half blocks: ▀▄ for the ball (2x vertical) and ▘▝ paddle
+	// edges (2x horizontal).
+	SubCellHalf
+	// SubCellBraille uses braille dots: 2x horizontal and 4x vertical.
+	SubCellBraille
+)
+
+// SubCellNames lists the values accepted by ParseSubCell, in SubCell order.
+var SubCellNames = []string{"off", "half", "braille"}
+
+func (m SubCell) String() string {
+	if m < 0 || int(m) >= len(SubCellNames) {
+		return fmt.Sprintf("SubCell(%d)", int(m))
+	}
+	return SubCellNames[m]
+}
+
+// ParseSubCell resolves a sub-cell mode by name. An empty name selects SubCellOff.
+func ParseSubCell(name string) (SubCell, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return SubCellOff, nil
+	}
+	for i, n := range SubCellNames {
+		if n == name {
+			return SubCell(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown sub-cell mode %q (expected one of: %s)", name, strings.Join(SubCellNames, ", "))
+}
+
+// subGlyphs are the pre-rendered cells of one sub-cell mode.
+type subGlyphs struct {
+	cols, rows int      // sub-positions per cell
+	ball       []string // [row*cols+col] ball at that sub-position
+	// paddle cells, normal and flashing: both halves covered, left half only,
+	// right half only.
+	paddle [2][3]string
+}
+
+// Braille dot bits by [col][row] (Unicode braille pattern layout).
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+func newSubGlyphs(mode SubCell, ball, paddle, flash lipgloss.Style) *subGlyphs {
+	var g *subGlyphs
+	var pieces [3]string
+	switch mode {
+	case SubCellHalf:
+		g = &subGlyphs{cols: 1, rows: 2, ball: []string{ball.Render("▀"), ball.Render("▄")}}
+		pieces = [3]string{"▀", "▘", "▝"}
+	case SubCellBraille:
+		g = &subGlyphs{cols: 2, rows: 4, ball: make([]string, 8)}
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 2; c++ {
+				g.ball[r*2+c] = ball.Render(string(0x2800 + brailleDots[c][r]))
+			}
+		}
+		// The paddle is the top two dot rows, so the ball can sit just above it.
+		left := brailleDots[0][0] | brailleDots[0][1]
+		right := brailleDots[1][0] | brailleDots[1][1]
+		pieces = [3]string{string(0x2800 + (left | right)), string(0x2800 + left), string(0x2800 + right)}
+	default:
+		return nil
+	}
+	for i, piece := range pieces {
+		g.paddle[0][i] = paddle.Render(piece)
+		g.paddle[1][i] = flash.Render(piece)
+	}
+	return g
+}
+
+// ballGlyph returns the cell drawing the ball at field position (x, y).
+func (g *subGlyphs) ballGlyph(x, y float64) string {
+	sx := min(int((x-math.Floor(x))*float64(g.cols)), g.cols-1)
+	sy := min(int((y-math.Floor(y))*float64(g.rows)), g.rows-1)
+	return g.ball[sy*g.cols+sx]
+}
+
+// paddleGlyph returns the paddle cell at view column x for a paddle spanning
+// [x0, x1) in view coordinates. A half cell counts as covered when the paddle
+// covers its center.
+func (g *subGlyphs) paddleGlyph(x int, x0, x1 float64, flash bool) string {
+	cells := &g.paddle[0]
+	if flash {
+		cells = &g.paddle[1]
+	}
+	lc := float64(x) + 0.25
+	rc := float64(x) + 0.75
+	left := x0 <= lc && lc < x1
+	right := x0 <= rc && rc < x1
+	switch {
+	case left && right:
+		return cells[0]
+	case left:
+		return cells[1]
+	case right:
+		return cells[2]
+	default:
+		return " "
+	}
+}
+
+// renderFieldSubCellTo is renderFieldFastTo with the ball and paddle drawn by g.
+// Geometry (view offset, brick band, rows) is the same, and all cells come from
+// the palette, so frames do not allocate either.
+func renderFieldSubCellTo(b *bytes.Buffer, p *palette, g *subGlyphs, s game.State, fx *fxLayer, labels *axisLabels, clearEOL string, leftPad string, spaceLine string, visibleBrickCols int) {
+	w := s.ViewW
+	h := s.Height
+	vx := s.ViewLeft()
+
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	if visibleBrickCols >= s.BrickCols {
+		visibleBrickCols = -1 // treat as "all"
+	}
+	bandTop, bandBottom := s.BrickBand()
+
+	py := int(s.PaddleY)
+	bx := int(math.Floor(s.BallX)) - vx
+	by := int(math.Floor(s.BallY))
+	ball := g.ballGlyph(s.BallX, s.BallY)
+	px0 := s.PaddleX - float64(vx)
+	px1 := px0 + s.PaddleW
+	flash := fx != nil && fx.paddleFlash
+
+	for y := 0; y < h; y++ {
+		if leftPad != "" {
+			b.WriteString(leftPad)
+		}
+		b.WriteString(labels.leftCell(y))
+		fxRow := fx.row(y)
+		ballX := -1
+		if y == by {
+			ballX = bx
+		}
+		switch {
+		case y >= bandTop && y < bandBottom:
+			writeBrickRow(b, p, &s, y, w, vx, ballX, ball, fxRow, visibleBrickCols)
+		case y == py:
+			for x := 0; x < w; x++ {
+				switch {
+				case x == ballX:
+					b.WriteString(ball)
+				case fxRow != nil && fxRow[x] != "":
+					b.WriteString(fxRow[x])
+				default:
+					b.WriteString(g.paddleGlyph(x, px0, px1, flash))
+				}
+			}
+		case y == labels.monthRowY():
+			writeMonthRow(b, labels.monthRow(y, vx, w), ballX, ball, fxRow)
+		case fxRow != nil:
+			writeOpenRow(b, w, ballX, ball, fxRow)
+		case y == by && bx >= 0 && bx < w:
+			if bx > 0 {
+				b.WriteString(spaceLine[:bx])
+			}
+			b.WriteString(ball)
+			if bx+1 < w {
+				b.WriteString(spaceLine[bx+1:])
+			}
+		default:
+			b.WriteString(spaceLine)
+		}
+		b.WriteString(clearEOL)
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taggedSubGlyphs builds the glyphs of mode with styles that prefix their
+// cells, so a test can tell the ball, paddle and flash apart without colors.
+func taggedSubGlyphs(mode SubCell) *subGlyphs {
+	tag := func(prefix string) lipgloss.Style {
+		return lipgloss.NewStyle().Transform(func(s string) string { return prefix + s })
+	}
+	return newSubGlyphs(mode, tag("ball:"), tag("paddle:"), tag("flash:"))
+}
+
+func TestSubGlyphs_Ball(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode SubCell
+		x, y float64
+		want string
+	}{
+		{SubCellHalf, 3.0, 5.0, "ball:▀"},
+		{SubCellHalf, 3.9, 5.49, "ball:▀"},
+		{SubCellHalf, 3.1, 5.5, "ball:▄"},
+		{SubCellHalf, 3.5, 5.99, "ball:▄"},
+		// Braille: the left or right dot column by x, one of four rows by y.
+		{SubCellBraille, 3.2, 5.1, "ball:⠁"},
+		{SubCellBraille, 3.7, 5.1, "ball:⠈"},
+		{SubCellBraille, 3.2, 5.3, "ball:⠂"},
+		{SubCellBraille, 3.7, 5.6, "ball:⠠"},
+		{SubCellBraille, 3.2, 5.8, "ball:⡀"},
+		{SubCellBraille, 3.99, 5.99, "ball:⢀"},
+	}
+	for _, tt := range tests {
+		g := taggedSubGlyphs(tt.mode)
+		if got := g.ballGlyph(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: ballGlyph(%v, %v) = %q, want %q", tt.mode, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestSubGlyphs_Paddle(t *testing.T) {
+	t.Parallel()
+
+	// Cells drawn for view columns 0..5.
+	tests := []struct {
+		mode   SubCell
+		x0, x1 float64
+		flash  bool
+		want   [6]string
+	}{
+		{SubCellHalf, 1, 4, false, [6]string{" ", "paddle:▀", "paddle:▀", "paddle:▀", " ", " "}},
+		// Fractional ends cover only the inner half of the edge cells.
+		{SubCellHalf, 1.5, 4.5, false, [6]string{" ", "paddle:▝", "paddle:▀", "paddle:▀", "paddle:▘", " "}},
+		{SubCellHalf, 0.6, 2.2, false, [6]string{"paddle:▝", "paddle:▀", " ", " ", " ", " "}},
+		{SubCellHalf, 1.5, 4.5, true, [6]string{" ", "flash:▝", "flash:▀", "flash:▀", "flash:▘", " "}},
+		{SubCellBraille, 1, 3, false, [6]string{" ", "paddle:⠛", "paddle:⠛", " ", " ", " "}},
+		{SubCellBraille, 1.5, 4.5, false, [6]string{" ", "paddle:⠘", "paddle:⠛", "paddle:⠛", "paddle:⠃", " "}},
+		{SubCellBraille, 1.5, 4.5, true, [6]string{" ", "flash:⠘", "flash:⠛", "flash:⠛", "flash:⠃", " "}},
+	}
+	for _, tt := range tests {
+		g := taggedSubGlyphs(tt.mode)
+		for x, want := range tt.want {
+			if got := g.paddleGlyph(x, tt.x0, tt.x1, tt.flash); got != want {
+				t.Errorf("%s: paddleGlyph(%d, %v, %v, %t) = %q, want %q", tt.mode, x, tt.x0, tt.x1, tt.flash, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is a named color palette. Colors are hex strings ("#rrggbb").
+// Empty fields fall back to the classic theme, so user themes only need to set
+// what they change (typically Bricks).
+type Theme struct {
+	Name string `yaml:"name"`
+
+	// Bricks holds the brick colors for HP 1..4 (fewest -> most contributions).
+	Bricks [4]string `yaml:"bricks,flow"`
+
+	Paddle string `yaml:"paddle,omitempty"`
+	Ball   string `yaml:"ball,omitempty"`
+
+	HUDLabel string `yaml:"hud_label,omitempty"`
+	HUDValue string `yaml:"hud_value,omitempty"`
+	HUDScore string `yaml:"hud_score,omitempty"`
+	HUDOk    string `yaml:"hud_ok,omitempty"`
+	HUDDim   string `yaml:"hud_dim,omitempty"`
+
+	Panel       string `yaml:"panel,omitempty"`        // overlay background
+	Border      string `yaml:"border,omitempty"`       // overlay border
+	Alert       string `yaml:"alert,omitempty"`        // GAME OVER title
+	Celebrate   string `yaml:"celebrate,omitempty"`    // CLEAR border/title
+	OverlayText string `yaml:"overlay_text,omitempty"` // overlay body text
+	Streak      string `yaml:"streak,omitempty"`       // streak brick outline and power-ups
+}
+
+// classicTheme is GitHub's light-mode greens with the original HUD colors.
+var classicTheme = Theme{
+	Name:   "classic",
+	Bricks: [4]string{"#9be9a8", "#40c463", "#30a14e", "#216e39"},
+
+	Paddle: "#d0d7de",
+	Ball:   "#ffd33d",
+
+	HUDLabel: "#8b949e",
+	HUDValue: "#d0d7de",
+	HUDScore: "#ffd33d",
+	HUDOk:    "#7ee787",
+	HUDDim:   "#6e7681",
+
+	Panel:       "#161b22",
+	Border:      "#30363d",
+	Alert:       "#ff7b72",
+	Celebrate:   "#7ee787",
+	OverlayText: "#d0d7de",
+	Streak:      "#f0883e",
+}
+
+// BuiltinThemes returns the built-in themes; the first is the default.
+func BuiltinThemes() []Theme {
+	return []Theme{
+		classicTheme,
+		{
+			Name:   "dark-dimmed",
+			Bricks: [4]string{"#0e4429", "#006d32", "#26a641", "#39d353"},
+			Paddle: "#adbac7",
+			Panel:  "#22272e",
+			Border: "#444c56",
+		},
+		{
+			Name:      "halloween",
+			Bricks:    [4]string{"#ffee4a", "#ffc501", "#fe9600", "#03001c"},
+			Ball:      "#fe9600",
+			HUDOk:     "#ffc501",
+			Celebrate: "#fe9600",
+			Streak:    "#a371f7",
+		},
+		{
+			Name:      "winter",
+			Bricks:    [4]string{"#b6e3ff", "#54aeff", "#0969da", "#0a3069"},
+			Ball:      "#ffffff",
+			HUDOk:     "#54aeff",
+			Celebrate: "#54aeff",
+		},
+		{
+			Name:        "high-contrast",
+			Bricks:      [4]string{"#ffffff", "#ffd700", "#ff8700", "#d70000"},
+			Paddle:      "#ffffff",
+			Ball:        "#00ffff",
+			HUDLabel:    "#ffffff",
+			HUDValue:    "#ffffff",
+			HUDDim:      "#bcbcbc",
+			Panel:       "#000000",
+			Border:      "#ffffff",
+			OverlayText: "#ffffff",
+		},
+		// Colorblind-safe ramps: each one varies in lightness as well as hue and
+		// avoids the color pair the deficiency confuses.
+		{
+			Name:      "deuteranopia",
+			Bricks:    [4]string{"#c6dbef", "#6baed6", "#2171b5", "#08306b"},
+			HUDOk:     "#6baed6",
+			Celebrate: "#6baed6",
+		},
+		{
+			Name:      "protanopia",
+			Bricks:    [4]string{"#fde737", "#a59c74", "#575d6d", "#00224e"},
+			HUDOk:     "#fde737",
+			Celebrate: "#fde737",
+		},
+		{
+			Name:      "tritanopia",
+			Bricks:    [4]string{"#fcbba1", "#fb6a4a", "#cb181d", "#67000d"},
+			Ball:      "#ffffff",
+			HUDOk:     "#fb6a4a",
+			Celebrate: "#fb6a4a",
+			Streak:    "#1f6feb",
+		},
+	}
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// withDefaults fills empty colors from the classic theme.
+func (t Theme) withDefaults() Theme {
+	def := classicTheme
+	fill := func(v *string, fallback string) {
+		if *v == "" {
+			*v = fallback
+		}
+	}
+	for i := range t.Bricks {
+		fill(&t.Bricks[i], def.Bricks[i])
+	}
+	fill(&t.Paddle, def.Paddle)
+	fill(&t.Ball, def.Ball)
+	fill(&t.HUDLabel, def.HUDLabel)
+	fill(&t.HUDValue, def.HUDValue)
+	fill(&t.HUDScore, def.HUDScore)
+	fill(&t.HUDOk, def.HUDOk)
+	fill(&t.HUDDim, def.HUDDim)
+	fill(&t.Panel, def.Panel)
+	fill(&t.Border, def.Border)
+	fill(&t.Alert, def.Alert)
+	fill(&t.Celebrate, def.Celebrate)
+	fill(&t.OverlayText, def.OverlayText)
+	fill(&t.Streak, def.Streak)
+	return t
+}
+
+func (t Theme) validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("theme name must not be empty")
+	}
+	colors := append(t.Bricks[:], t.Paddle, t.Ball, t.HUDLabel, t.HUDValue, t.HUDScore,
+		t.HUDOk, t.HUDDim, t.Panel, t.Border, t.Alert, t.Celebrate, t.OverlayText, t.Streak)
+	for _, c := range colors {
+		if c != "" && !hexColor.MatchString(c) {
+			return fmt.Errorf("theme %q: invalid color %q (expected #rrggbb)", t.Name, c)
+		}
+	}
+	return nil
+}
+
+// LoadThemes reads user-defined themes from a YAML file:
+//
+//	themes:
+//	  - name: sakura
+//	    bricks: ["#ffd6e7", "#ff9ec4", "#f06595", "#a61e4d"]
+//
+// A missing or empty file is not an error and yields no themes.
+func LoadThemes(path string) ([]Theme, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read themes: %w", err)
+	}
+	var file struct {
+		Themes []Theme `yaml:"themes"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		// An empty (or comment-only) file decodes as EOF.
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid themes file %s: %w", path, err)
+	}
+	for _, t := range file.Themes {
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("invalid themes file %s: %w", path, err)
+		}
+	}
+	return file.Themes, nil
+}
+
+// ThemeNames returns the names of themes, in order.
+func ThemeNames(themes []Theme) []string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// FindTheme returns the index of the theme with the given name (case-insensitive).
+func FindTheme(themes []Theme, name string) (int, bool) {
+	for i, t := range themes {
+		if strings.EqualFold(t.Name, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// themeSet is how a model draws: the themes the theme key cycles through and
+// the rendering options every palette shares. Themes are turned into palettes
+// lazily, so cycling never re-renders styles for a theme that was already shown.
+type themeSet struct {
+	themes   []Theme
+	palettes []*palette
+	themeIdx int
+	pal      *palette
+	glyphs   Glyphs
+	ascii    bool
+	subcell  SubCell
+}
+
+// newThemeSet starts on themes[i].
+func newThemeSet(themes []Theme, i int, glyphs Glyphs, ascii bool, subcell SubCell) themeSet {
+	t := themeSet{
+		themes:   themes,
+		palettes: make([]*palette, len(themes)),
+		glyphs:   glyphs,
+		ascii:    ascii,
+		subcell:  subcell,
+	}
+	t.setTheme(i)
+	return t
+}
+
+// setTheme switches to themes[i], building its palette on first use.
+func (t *themeSet) setTheme(i int) {
+	if t.palettes[i] == nil {
+		if t.ascii {
+			t.palettes[i] = newASCIIPalette(t.glyphs)
+		} else {
+			t.palettes[i] = newPalette(t.themes[i], t.glyphs)
+		}
+	}
+	t.themeIdx = i
+	t.pal = t.palettes[i]
+}
+
+// cycleTheme switches to the next theme.
+func (t *themeSet) cycleTheme() {
+	t.setTheme((t.themeIdx + 1) % len(t.themes))
+}
+
+// palette holds a theme's styles and pre-rendered cells. Everything the per-frame
+// fast path writes is rendered once here (or on first use for brick runs), so
+// drawing a frame does not allocate.
+type palette struct {
+	name string
+	// plain palettes write no escape sequences and only ASCII (see newASCIIPalette).
+	plain bool
+
+	paddleCell string
+	ballCell   string
+	// Effect cells: the paddle while flashing after a hit, the ball trail from
+	// newest to oldest, and shatter fragments per brick shade.
+	paddleFlash string
+	trailCells  [3]string
+	fragCells   [5][len(fragGlyphs)]string
+	// sub holds the ball and paddle cells per SubCell mode; nil entries (and
+	// SubCellOff) use the full-cell renderer.
+	sub [3]*subGlyphs
+	// cursorCells draw the inspect cursor over a brick: left edge, middle, right
+	// edge, and a brick one column wide.
+	cursorCells [4]string
+	// streakCells draw streak bricks per HP, in the same order: an outline in the
+	// theme's streak color around the brick. dropCells draw falling power-ups.
+	streakCells [5][4]string
+	dropCells   [4]string // [game.PowerUp]
+	// hazardCell draws a boss projectile.
+	hazardCell string
+	brickCell1 [5]string // [hp] one styled cell; [0] unused
+	brickStyle [5]lipgloss.Style
+	brickGlyph [5]string   // [hp] character drawn in a brick cell
+	brickRuns  [5][]string // [hp][n] styled run of n cells; [0] holds plain spaces
+
+	hudLabel lipgloss.Style
+	hudValue lipgloss.Style
+	hudScore lipgloss.Style
+	hudOk    lipgloss.Style
+	hudDim   lipgloss.Style
+	// legend shows which glyph means which HP; empty when glyphs are off.
+	legend string
+
+	barFull  string // progress bar cells
+	barEmpty string
+	arrows   string // how the HUD names the arrow keys
+	overlay  overlayGlyph
+
+	panel       lipgloss.Color
+	border      lipgloss.Color
+	alert       lipgloss.Color
+	celebrate   lipgloss.Color
+	overlayText lipgloss.Color
+	scoreColor  lipgloss.Color
+	helpColor   lipgloss.Color
+}
+
+func newPalette(t Theme, g Glyphs) *palette {
+	t = t.withDefaults()
+	paddle := lipgloss.NewStyle().Foreground(lipgloss.Color(t.Paddle))
+	flash := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.Ball))
+	ball := lipgloss.NewStyle().Foreground(lipgloss.Color(t.Ball))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(t.HUDDim))
+	p := &palette{
+		name:        t.Name,
+		paddleCell:  paddle.Render("="),
+		ballCell:    ball.Render("*"),
+		paddleFlash: flash.Render("="),
+		trailCells:  [3]string{ball.Render("•"), ball.Render("·"), dim.Render("·")},
+		sub: [3]*subGlyphs{
+			SubCellHalf:    newSubGlyphs(SubCellHalf, ball, paddle, flash),
+			SubCellBraille: newSubGlyphs(SubCellBraille, ball, paddle, flash),
+		},
+
+		cursorCells: cursorCells(lipgloss.NewStyle().Bold(true).
+			Background(lipgloss.Color(t.Alert)).Foreground(lipgloss.Color(t.OverlayText))),
+		hazardCell: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.Alert)).Render("v"),
+		dropCells: dropCells(lipgloss.NewStyle().Bold(true).
+			Background(lipgloss.Color(t.Streak)).Foreground(lipgloss.Color(contrastText(t.Streak)))),
+
+		hudLabel: lipgloss.NewStyle().Foreground(lipgloss.Color(t.HUDLabel)),
+		hudValue: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.HUDValue)),
+		hudScore: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.HUDScore)),
+		hudOk:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.HUDOk)),
+		hudDim:   lipgloss.NewStyle().Foreground(lipgloss.Color(t.HUDDim)),
+
+		panel:       lipgloss.Color(t.Panel),
+		border:      lipgloss.Color(t.Border),
+		alert:       lipgloss.Color(t.Alert),
+		celebrate:   lipgloss.Color(t.Celebrate),
+		overlayText: lipgloss.Color(t.OverlayText),
+		scoreColor:  lipgloss.Color(t.HUDScore),
+		helpColor:   lipgloss.Color(t.HUDLabel),
+
+		barFull:  "█",
+		barEmpty: "░",
+		arrows:   "←/→",
+		overlay:  overlayGlyphs[g],
+	}
+	for hp := 1; hp <= 4; hp++ {
+		c := t.Bricks[hp-1]
+		switch g {
+		case GlyphsShade:
+			// Shade glyphs carry the color themselves; a background would hide them.
+			p.brickStyle[hp] = lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+		case GlyphsDigits:
+			p.brickStyle[hp] = lipgloss.NewStyle().Background(lipgloss.Color(c)).Foreground(lipgloss.Color(contrastText(c)))
+		default:
+			p.brickStyle[hp] = lipgloss.NewStyle().Background(lipgloss.Color(c))
+		}
+		p.brickGlyph[hp] = g.glyph(hp)
+		p.brickCell1[hp] = p.brickStyle[hp].Render(p.brickGlyph[hp])
+		outline := p.brickStyle[hp].Foreground(lipgloss.Color(t.Streak))
+		if g == GlyphsOff {
+			p.streakCells[hp] = [4]string{outline.Render("▏"), p.brickCell1[hp], outline.Render("▕"), outline.Render("▪")}
+		} else {
+			// The glyph shows the HP, so it takes the streak color instead.
+			c := outline.Bold(true).Render(p.brickGlyph[hp])
+			p.streakCells[hp] = [4]string{c, c, c, c}
+		}
+		frag := lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+		for i, ch := range fragGlyphs {
+			p.fragCells[hp][i] = frag.Render(ch)
+		}
+	}
+	if g != GlyphsOff {
+		p.legend = p.hudLabel.Render("hp ") + p.brickCell1[1] + p.brickCell1[2] + p.brickCell1[3] + p.brickCell1[4]
+	}
+	return p
+}
+
+// hpRun returns n adjacent brick cells with the given HP (0 = empty) as one string,
+// so adjacent cells of the same shade are written as a single escape sequence.
+// Runs are rendered on first use and reused for every later frame.
+func (p *palette) hpRun(hp, n int) string {
+	hp = min(max(hp, 0), 4)
+	if n <= 0 {
+		return ""
+	}
+	runs := p.brickRuns[hp]
+	if n < len(runs) && runs[n] != "" {
+		return runs[n]
+	}
+	if n >= len(runs) {
+		grown := make([]string, n+1)
+		copy(grown, runs)
+		runs = grown
+		p.brickRuns[hp] = runs
+	}
+	if hp == 0 {
+		runs[n] = strings.Repeat(" ", n)
+	} else {
+		runs[n] = p.brickStyle[hp].Render(strings.Repeat(p.brickGlyph[hp], n))
+	}
+	return runs[n]
+}
+
+func (p *palette) hpCell1(hp int) string {
+	if hp <= 0 {
+		return " "
+	}
+	if hp > 4 {
+		hp = 4
+	}
+	return p.brickCell1[hp]
+}
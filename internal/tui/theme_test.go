@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadThemes_MissingOrEmptyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"empty.yaml":    "",
+		"comments.yaml": "# no themes yet\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if themes, err := LoadThemes(path); err != nil || themes != nil {
+			t.Fatalf("%s: expected no themes and no error, got %v, %v", name, themes, err)
+		}
+	}
+	if themes, err := LoadThemes(filepath.Join(dir, "missing.yaml")); err != nil || themes != nil {
+		t.Fatalf("missing file: expected no themes and no error, got %v, %v", themes, err)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("themes: [{name: x, colour: red}]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadThemes(bad); err == nil {
+		t.Fatalf("expected an error for an unknown field")
+	}
+}
+
+func TestThemeSet_CycleReusesPalettes(t *testing.T) {
+	t.Parallel()
+
+	themes := BuiltinThemes()
+	ts := newThemeSet(themes, len(themes)-1, GlyphsOff, false, SubCellOff)
+	first := ts.pal
+	ts.cycleTheme()
+	if ts.themeIdx != 0 || ts.pal.name != themes[0].Name {
+		t.Fatalf("cycling should wrap to the first theme, got %d (%s)", ts.themeIdx, ts.pal.name)
+	}
+	for range themes {
+		ts.cycleTheme()
+	}
+	if ts.themeIdx != 0 {
+		t.Fatalf("a full cycle should come back to the first theme, got %d", ts.themeIdx)
+	}
+	for range len(themes) - 1 {
+		ts.cycleTheme()
+	}
+	if ts.pal != first {
+		t.Fatalf("a theme shown before should reuse its palette")
+	}
+}
@@ -0,0 +1,491 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+	"github.com/fchimpan/gh-kusa-breaker/internal/mapping"
+	"github.com/fchimpan/gh-kusa-breaker/internal/sound"
+)
+
+// Player is one side of a versus game: a login and the calendar their board is
+// built from.
+type Player struct {
+	Login    string
+	Calendar github.Calendar
+}
+
+const (
+	// defaultTimeLimit is the length of a versus match when Options.TimeLimit
+	// is 0.
+	defaultTimeLimit = 3 * time.Minute
+	// versusCountdown gives both players a moment before the balls move.
+	versusCountdown = 3.0
+)
+
+// versusKey is what a paddle key does in a versus game: set board player's
+// paddle moving in direction dir, or stop it when dir is 0.
+type versusKey struct{ player, dir int }
+
+// versusArrows are the right board's keys. They are fixed so the left board
+// can take the rest of the key map.
+var versusArrows = map[string]versusKey{
+	"left":  {1, -1},
+	"right": {1, 1},
+	"down":  {1, 0},
+}
+
+// versusKeys returns the paddle keys of both boards: the left board steers with
+// the Left and Right keys of k and stops with its Down keys, less the arrow
+// keys, which belong to the right board. They win over the rest of the key map.
+// label names the left board's keys for the info line.
+func versusKeys(k KeyMap) (keys map[string]versusKey, label string) {
+	def := DefaultKeyMap()
+	own := func(keys, fallback []string) []string {
+		var out []string
+		for _, list := range [][]string{keys, fallback} {
+			for _, key := range list {
+				if _, ok := versusArrows[key]; !ok {
+					out = append(out, key)
+				}
+			}
+			if len(out) > 0 {
+				break
+			}
+		}
+		return out
+	}
+	left, right, stop := own(k.Left, def.Left), own(k.Right, def.Right), own(k.Down, def.Down)
+
+	keys = make(map[string]versusKey)
+	for key, vk := range versusArrows {
+		keys[key] = vk
+	}
+	for _, b := range []struct {
+		keys []string
+		dir  int
+	}{{left, -1}, {right, 1}, {stop, 0}} {
+		for _, key := range b.keys {
+			if _, ok := keys[key]; !ok {
+				keys[key] = versusKey{0, b.dir}
+			}
+		}
+	}
+	return keys, left[0] + "/" + right[0] + " (" + stop[0] + " stop)"
+}
+
+// versusBoard is one player's side of a VersusModel.
+type versusBoard struct {
+	login string
+	cal   github.Calendar
+	grid  mapping.BrickGrid
+	state game.State
+	// move is the latched paddle direction. Terminals auto-repeat only the
+	// last key pressed, so a held key can't be told from a tapped one once the
+	// other player presses theirs: a press keeps the paddle moving until the
+	// opposite or the stop key.
+	move int
+
+	fx        effects
+	fxLayer   fxLayer
+	canvas    canvasBuf
+	buf       bytes.Buffer
+	spaceLine string
+}
+
+// VersusModel is the Bubble Tea model of a local two-player game: two boards
+// side by side, one per player, each with its own paddle and scoring rules.
+// The first to clear wins; otherwise the higher score when time runs out or
+// both are out of balls.
+type VersusModel struct {
+	boards [2]versusBoard
+	seed   uint64
+	speed  float64
+	keys   map[string]keyAction
+	help   keyHelp
+	// paddleKeys steer the boards; leftKeys names the left board's.
+	paddleKeys map[string]versusKey
+	leftKeys   string
+	gridOpts   mapping.GridOptions
+	gameOpts   game.Options
+	brickW     int
+
+	themeSet
+	effectsOn bool
+	rng       *rand.Rand
+	sound     sound.Player
+	bell      bool // see Model.bell
+
+	timeLimit float64
+	timeLeft  float64
+	countdown float64
+	// winner is the winning board once over is set, or -1 for a draw.
+	winner int
+	over   bool
+
+	lastTick time.Time
+	acc      float64
+
+	ready bool
+	w     int
+	h     int
+
+	viewBuf bytes.Buffer
+}
+
+// NewVersusModel returns a versus game of players[0] (left board, steered with
+// the key map's Left and Right keys) against players[1] (right board, arrow
+// keys). Mouse and ManualServe do not apply.
+func NewVersusModel(players [2]Player, seed uint64, opts Options) *VersusModel {
+	set := resolveOptions(seed, opts)
+	limit := opts.TimeLimit
+	if limit <= 0 {
+		limit = defaultTimeLimit
+	}
+	m := &VersusModel{
+		seed:      seed,
+		speed:     set.speed,
+		keys:      set.keys,
+		help:      set.help,
+		gridOpts:  set.gridOpts,
+		gameOpts:  set.gameOpts,
+		brickW:    set.brickW,
+		themeSet:  set.theme,
+		effectsOn: set.effectsOn,
+		rng:       set.rng,
+		sound:     set.sound,
+		timeLimit: limit.Seconds(),
+	}
+	m.paddleKeys, m.leftKeys = versusKeys(opts.Keys)
+	for i, p := range players {
+		m.boards[i].login = p.Login
+		m.boards[i].cal = p.Calendar
+	}
+	return m
+}
+
+func (m *VersusModel) Init() tea.Cmd {
+	return tickCmd(time.Second / 60)
+}
+
+func (m *VersusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+		m.h = msg.Height
+		m.resize()
+		return m, nil
+	case tickMsg:
+		now := time.Time(msg)
+		if m.lastTick.IsZero() {
+			m.lastTick = now
+			return m, tickCmd(time.Second / 60)
+		}
+		dt := min(max(now.Sub(m.lastTick).Seconds(), 0), 0.05)
+		m.lastTick = now
+		m.update(dt)
+		if m.sound != nil {
+			m.sound.Advance(dt)
+			m.bell = ringing(m.sound)
+		}
+		return m, tickCmd(time.Second / 60)
+	case tea.KeyMsg:
+		if k, ok := m.paddleKeys[msg.String()]; ok {
+			if m.playing() {
+				m.boards[k.player].move = k.dir
+			}
+			return m, nil
+		}
+		switch m.keys[msg.String()] {
+		case actionQuit:
+			return m, tea.Quit
+		case actionRetry:
+			if m.ready {
+				// Change seed so rematches feel fresh; both boards share it.
+				m.seed++
+				m.restart()
+			}
+		case actionSpeedUp:
+			m.speed = min(m.speed+0.1, 5)
+		case actionSpeedDown:
+			m.speed = max(m.speed-0.1, 0.25)
+		case actionTheme:
+			m.cycleTheme()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// playing reports whether the balls are moving.
+func (m *VersusModel) playing() bool {
+	return m.ready && !m.over && m.countdown <= 0
+}
+
+// slotSize returns the size of each player's slot: half the terminal, less the
+// separator.
+func (m *VersusModel) slotSize() (slotW, gameH int) {
+	return max((m.w-3)/2, 10), max(m.h-4, 10)
+}
+
+// layout builds the grid and a fresh board of each player for the current
+// terminal size.
+func (m *VersusModel) layout() (grids [2]mapping.BrickGrid, states [2]game.State) {
+	slotW, gameH := m.slotSize()
+	for i := range m.boards {
+		var opts game.Options
+		grids[i], opts = fitGrid(m.boards[i].cal, slotW, gameH, m.brickW, m.gridOpts, m.gameOpts)
+		states[i] = game.NewStateWithOptions(grids[i], slotW, gameH, m.seed, opts)
+	}
+	return grids, states
+}
+
+// setBoards replaces the boards' layout and state.
+func (m *VersusModel) setBoards(grids [2]mapping.BrickGrid, states [2]game.State) {
+	for i := range m.boards {
+		bd := &m.boards[i]
+		bd.grid, bd.state = grids[i], states[i]
+		bd.fx.reset()
+		bd.canvas.Reset()
+		bd.spaceLine = strings.Repeat(" ", max(bd.state.ViewW, 0))
+	}
+}
+
+// resize refits a match in progress to the current terminal size. Only when
+// either board has to be laid out differently does a new match start.
+func (m *VersusModel) resize() {
+	if !m.ready {
+		m.restart()
+		return
+	}
+	grids, states := m.layout()
+	for i := range states {
+		if !states[i].Refit(&m.boards[i].state) {
+			m.restart()
+			return
+		}
+	}
+	m.setBoards(grids, states)
+	m.lastTick = time.Time{}
+}
+
+// restart lays out both boards for the current terminal size and starts a new
+// match.
+func (m *VersusModel) restart() {
+	m.setBoards(m.layout())
+	for i := range m.boards {
+		m.boards[i].move = 0
+	}
+	m.rng = rand.New(rand.NewPCG(m.seed, m.seed^0x9e3779b97f4a7c15))
+	m.timeLeft = m.timeLimit
+	m.countdown = versusCountdown
+	m.over, m.winner = false, -1
+	m.lastTick = time.Time{}
+	m.acc = 0
+	m.ready = true
+}
+
+// update advances the match by dt seconds of wall time.
+func (m *VersusModel) update(dt float64) {
+	if !m.ready || m.over {
+		return
+	}
+	if m.countdown > 0 {
+		m.countdown -= dt
+		return
+	}
+	m.timeLeft = math.Max(m.timeLeft-dt, 0)
+
+	// Fixed timestep, as in Model.Update; both boards step together so neither
+	// gets ahead.
+	m.acc += dt * (m.speed * baseSpeedMultiplier)
+	const fixed = 1.0 / 120.0
+	const maxStepsPerTick = 10
+	steps := 0
+	for m.acc >= fixed && steps < maxStepsPerTick && !m.over {
+		for i := range m.boards {
+			bd := &m.boards[i]
+			bd.state.Step(fixed, game.Input{Move: bd.move})
+			m.handleEvents(bd, bd.state.Events())
+		}
+		m.winner, m.over = game.VersusResult(&m.boards[0].state, &m.boards[1].state, false)
+		m.acc -= fixed
+		steps++
+	}
+	if steps >= maxStepsPerTick {
+		m.acc = math.Mod(m.acc, fixed)
+	}
+	if !m.over && m.timeLeft <= 0 {
+		m.winner, m.over = game.VersusResult(&m.boards[0].state, &m.boards[1].state, true)
+	}
+	if m.effectsOn {
+		for i := range m.boards {
+			bd := &m.boards[i]
+			bd.fx.update(dt, bd.state.BallX, bd.state.BallY)
+		}
+	}
+}
+
+func (m *VersusModel) handleEvents(bd *versusBoard, events []game.Event) {
+	if m.effectsOn {
+		bd.fx.handle(events, &bd.state, m.rng)
+	}
+	if m.sound != nil {
+		m.sound.Handle(events)
+	}
+}
+
+// versusOverlay returns the overlay of board i, or nil while it is in play.
+func (m *VersusModel) versusOverlay(i int) *fieldOverlay {
+	s := &m.boards[i].state
+	lines := []string{scoreLine(s), "user: " + m.boards[i].login}
+	rematch := fmt.Sprintf("press %s for a rematch, %s to quit", m.help.key(actionRetry), m.help.key(actionQuit))
+	switch {
+	case !m.over && s.GameOver:
+		return &fieldOverlay{
+			Title:  "OUT OF BALLS",
+			Lines:  lines,
+			Footer: "waiting for " + m.boards[1-i].login + "...",
+		}
+	case !m.over:
+		return nil
+	case m.winner == i:
+		title := "WINNER!"
+		if s.Cleared {
+			title = "WINNER!  CLEARED"
+		}
+		return &fieldOverlay{Title: title, Lines: lines, Footer: rematch}
+	case m.winner < 0:
+		return &fieldOverlay{Title: "DRAW", Lines: lines, Footer: rematch}
+	default:
+		return &fieldOverlay{Title: "NICE TRY", Lines: lines, Footer: rematch}
+	}
+}
+
+func (m *VersusModel) View() string {
+	if !m.ready {
+		return "loading...\n"
+	}
+	m.viewBuf.Reset()
+	b := &m.viewBuf
+	if m.bell {
+		b.WriteByte('\a')
+	}
+	p := m.pal
+
+	slotW, gameH := m.slotSize()
+	contentW := 2*slotW + 3
+	leftPad := ""
+	if m.w > contentW {
+		leftPad = strings.Repeat(" ", (m.w-contentW)/2)
+	}
+	// Lines: HUD(1) + info(1) + field(height) + trailing blank(1)
+	if contentH := 1 + 1 + gameH + 1; m.h > contentH {
+		b.WriteString(strings.Repeat("\n", (m.h-contentH)/2))
+	}
+
+	b.WriteString(leftPad)
+	b.WriteString(m.hud())
+	b.WriteString("\n")
+	b.WriteString(leftPad)
+	b.WriteString(m.infoLine())
+	b.WriteString("\n")
+
+	// Render each board centered in its slot, then interleave their lines.
+	for i := range m.boards {
+		bd := &m.boards[i]
+		bd.buf.Reset()
+		pad := strings.Repeat(" ", max(slotW-bd.state.ViewW, 0)/2)
+		if ov := m.versusOverlay(i); ov != nil {
+			renderFieldCanvasTo(&bd.buf, p, bd.state, ov, nil, "", pad, nil, &bd.canvas)
+			continue
+		}
+		var fx *fxLayer
+		if m.effectsOn && bd.fx.active() {
+			bd.fx.draw(&bd.fxLayer, p, &bd.state)
+			fx = &bd.fxLayer
+		}
+		if len(bd.state.Drops) > 0 {
+			if fx == nil {
+				bd.fxLayer.resize(bd.state.ViewW, bd.state.Height)
+				fx = &bd.fxLayer
+			}
+			drawDrops(fx, p, &bd.state)
+		}
+		if g := p.sub[m.subcell]; g != nil {
+			renderFieldSubCellTo(&bd.buf, p, g, bd.state, fx, nil, "", pad, bd.spaceLine, -1)
+		} else {
+			renderFieldFastTo(&bd.buf, p, bd.state, fx, nil, "", pad, bd.spaceLine, -1)
+		}
+	}
+	sep := p.hudDim.Render(" " + p.overlay.box.v + " ")
+	left, right := m.boards[0].buf.Bytes(), m.boards[1].buf.Bytes()
+	leftW := max(slotW-m.boards[0].state.ViewW, 0)/2 + m.boards[0].state.ViewW
+	for range gameH {
+		var l, r []byte
+		l, left = cutLine(left)
+		r, right = cutLine(right)
+		b.WriteString(leftPad)
+		b.Write(l)
+		b.WriteString(strings.Repeat(" ", max(slotW-leftW, 0)))
+		b.WriteString(sep)
+		b.Write(r)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// cutLine splits the first line off b.
+func cutLine(b []byte) (line, rest []byte) {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
+
+// hud is the combined HUD: each player's score and blocks left around the
+// match clock.
+func (m *VersusModel) hud() string {
+	p := m.pal
+	sep := p.hudDim.Render("  |  ")
+	player := func(bd *versusBoard) string {
+		s := &bd.state
+		out := p.hudValue.Render(bd.login) + " " +
+			p.hudLabel.Render("score ") + p.hudScore.Render(fmt.Sprintf("%6d", s.Score)) + " " +
+			p.hudLabel.Render("blocks ") + p.hudValue.Render(fmt.Sprintf("%d/%d", s.BricksRemaining, s.BricksTotal))
+		if m.gameOpts.Lives > 1 {
+			out += " " + p.hudLabel.Render("lives ") + p.hudValue.Render(fmt.Sprintf("%d", s.Lives))
+		}
+		return out
+	}
+	left := int(math.Ceil(m.timeLeft))
+	clock := p.hudLabel.Render("time ") + p.hudValue.Render(fmt.Sprintf("%d:%02d", left/60, left%60)) + " " +
+		p.hudLabel.Render("speed ") + p.hudValue.Render(fmt.Sprintf("%.2fx", m.speed))
+	return player(&m.boards[0]) + sep + clock + sep + player(&m.boards[1])
+}
+
+// infoLine shows the countdown, the result, or who steers with which keys.
+func (m *VersusModel) infoLine() string {
+	a, b := m.boards[0].login, m.boards[1].login
+	rematch := fmt.Sprintf("(%s rematch, %s quit)", m.help.key(actionRetry), m.help.key(actionQuit))
+	switch {
+	case m.countdown > 0:
+		return fmt.Sprintf("get ready... %d", int(math.Ceil(m.countdown)))
+	case m.over && m.winner >= 0:
+		return m.boards[m.winner].login + " wins! " + rematch
+	case m.over:
+		return "draw! " + rematch
+	}
+	h := &m.help
+	return fmt.Sprintf("%s: %s   %s (down stop): %s   (%s restart, %s/%s speed, %s theme, %s quit)", m.leftKeys, a, m.pal.arrows, b,
+		h.key(actionRetry), h.key(actionSpeedUp), h.key(actionSpeedDown), h.key(actionTheme), h.key(actionQuit))
+}
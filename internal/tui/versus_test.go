@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+	"github.com/fchimpan/gh-kusa-breaker/internal/github"
+)
+
+// versusCalendar returns 12 weeks of contributions.
+func versusCalendar() github.Calendar {
+	var cal github.Calendar
+	start := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	for w := range 12 {
+		var week github.Week
+		for d := range 7 {
+			week.ContributionDays = append(week.ContributionDays, github.Day{
+				Date:              start.AddDate(0, 0, w*7+d).Format("2006-01-02"),
+				Weekday:           d,
+				ContributionCount: (w + d) % 5,
+			})
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+	return cal
+}
+
+// versusModel returns a started versus game of alice and bob on an 80x30
+// terminal, past the countdown.
+func versusModel(opts Options) *VersusModel {
+	opts.ASCII = true
+	m := NewVersusModel([2]Player{{"alice", versusCalendar()}, {"bob", versusCalendar()}}, 1, opts)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m.countdown = 0
+	return m
+}
+
+func TestVersus_SplitBoardsAndKeys(t *testing.T) {
+	t.Parallel()
+
+	m := versusModel(Options{})
+	for i := range m.boards {
+		s := &m.boards[i].state
+		if s.Width > (80-3)/2 || s.BricksTotal == 0 {
+			t.Fatalf("board %d should fit half the terminal, got %d columns, %d bricks", i, s.Width, s.BricksTotal)
+		}
+	}
+	v := m.View()
+	for _, want := range []string{"alice score", "bob score", "time 3:00", "h/l (j stop): alice   arrows (down stop): bob"} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("view should contain %q:\n%s", want, v)
+		}
+	}
+
+	// a/d steer the left paddle only, the arrow keys the right one.
+	m.Update(key("a"))
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.boards[0].move != -1 || m.boards[1].move != 1 {
+		t.Fatalf("unexpected moves: %d, %d", m.boards[0].move, m.boards[1].move)
+	}
+	x0, x1 := m.boards[0].state.PaddleX, m.boards[1].state.PaddleX
+	m.update(0.05)
+	if m.boards[0].state.PaddleX >= x0 || m.boards[1].state.PaddleX <= x1 {
+		t.Fatalf("paddles should move apart: %.2f -> %.2f, %.2f -> %.2f", x0, m.boards[0].state.PaddleX, x1, m.boards[1].state.PaddleX)
+	}
+}
+
+func TestVersus_MovesLatch(t *testing.T) {
+	t.Parallel()
+
+	// Only the last key auto-repeats, so bob's press must not stop alice.
+	m := versusModel(Options{Lives: 9})
+	m.Update(key("d"))
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	for range 10 {
+		m.update(0.05)
+	}
+	if m.boards[0].move != 1 || m.boards[1].move != -1 {
+		t.Fatalf("moves should hold without repeats: %d, %d", m.boards[0].move, m.boards[1].move)
+	}
+
+	m.Update(key("a"))
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.boards[0].move != -1 || m.boards[1].move != 0 {
+		t.Fatalf("the opposite key should reverse and down should stop: %d, %d", m.boards[0].move, m.boards[1].move)
+	}
+	m.Update(key("s"))
+	x := m.boards[0].state.PaddleX
+	m.update(0.05)
+	if m.boards[0].move != 0 || m.boards[0].state.PaddleX != x {
+		t.Fatalf("s should stop the left paddle: %d, %.2f -> %.2f", m.boards[0].move, x, m.boards[0].state.PaddleX)
+	}
+}
+
+func TestVersus_KeysFollowTheKeyMap(t *testing.T) {
+	t.Parallel()
+
+	// The left board takes the key map less the arrow keys; empty lists fall
+	// back to the defaults.
+	m := versusModel(Options{Keys: KeyMap{Left: []string{"left", "z"}, Right: []string{"right", "x"}, Down: []string{"down"}}})
+	if !strings.Contains(m.View(), "z/x (j stop): alice") {
+		t.Fatalf("the info line should name the configured keys:\n%s", m.View())
+	}
+	m.Update(key("x"))
+	m.Update(key("a"))
+	if m.boards[0].move != 1 || m.boards[1].move != 0 {
+		t.Fatalf("x should steer the left paddle and a nothing: %d, %d", m.boards[0].move, m.boards[1].move)
+	}
+	m.Update(key("j"))
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.boards[0].move != 0 || m.boards[1].move != -1 {
+		t.Fatalf("j should stop the left paddle, left steer the right one: %d, %d", m.boards[0].move, m.boards[1].move)
+	}
+}
+
+func TestVersus_TimeOutAndRematch(t *testing.T) {
+	t.Parallel()
+
+	m := versusModel(Options{Lives: 9, TimeLimit: time.Second})
+	m.boards[1].state.Award(game.ScoreBricks, 100)
+	for range 30 {
+		m.update(0.05)
+	}
+	if !m.over || m.winner != 1 {
+		t.Fatalf("bob should win on points at time-out, got over %v, winner %d", m.over, m.winner)
+	}
+	v := m.View()
+	for _, want := range []string{"bob wins!", "WINNER!", "NICE TRY"} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("view should contain %q:\n%s", want, v)
+		}
+	}
+
+	m.Update(key("r"))
+	if m.over || m.timeLeft != 1 || m.boards[1].state.Score != 0 || m.countdown <= 0 {
+		t.Fatalf("r should start a rematch")
+	}
+}
+
+func TestVersus_ResizeKeepsTheMatch(t *testing.T) {
+	t.Parallel()
+
+	m := versusModel(Options{})
+	for range 20 {
+		m.update(0.05)
+	}
+	m.boards[0].state.Bricks[0].HP = 0
+	m.boards[1].state.Award(game.ScoreBricks, 100)
+	timeLeft, score := m.timeLeft, m.boards[1].state.Score
+
+	// A shorter terminal fits the same boards: the match goes on.
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if s := &m.boards[0].state; s.Height != 24-4 || s.Bricks[0].HP != 0 {
+		t.Fatalf("the left board should refit to the new height and keep its bricks: height %d, HP %d", s.Height, s.Bricks[0].HP)
+	}
+	if m.timeLeft != timeLeft || m.countdown > 0 || m.boards[1].state.Score != score {
+		t.Fatalf("the match should go on: %.1fs left (was %.1fs), score %d", m.timeLeft, timeLeft, m.boards[1].state.Score)
+	}
+
+	// A terminal too narrow for the same layout starts a new match.
+	m.Update(tea.WindowSizeMsg{Width: 50, Height: 24})
+	if m.timeLeft != m.timeLimit || m.countdown <= 0 || m.boards[1].state.Score != 0 {
+		t.Fatalf("a new layout should start a new match: %.1fs left, score %d", m.timeLeft, m.boards[1].state.Score)
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
+	"github.com/fchimpan/gh-kusa-breaker/internal/game"
+)
+
+// WatchModel is the Bubble Tea model of a read-only view of someone else's
+// game, streamed with broadcast. It draws each frame as it arrives; only the
+// theme and quit keys apply.
+type WatchModel struct {
+	stream *broadcast.Stream
+	keys   map[string]keyAction
+	help   keyHelp
+
+	themeSet
+
+	// state is the watched game; it is only valid once a keyframe arrived.
+	state       game.State
+	login       string
+	lives       int // the balls the game started with
+	speed, pace float64
+	started     bool
+	// err ended the stream; io.EOF means the player quit.
+	err error
+
+	fxLayer   fxLayer
+	canvas    canvasBuf
+	spaceLine string
+
+	ready bool
+	w     int
+	h     int
+
+	viewBuf bytes.Buffer
+}
+
+// frameMsg carries the next frame of the stream, or the error that ended it.
+type frameMsg struct {
+	frame *broadcast.Frame
+	err   error
+}
+
+// NewWatchModel returns the model of stream. The caller closes stream after
+// the program exits.
+func NewWatchModel(stream *broadcast.Stream, opts Options) *WatchModel {
+	set := resolveOptions(0, opts)
+	m := &WatchModel{
+		stream:   stream,
+		keys:     set.keys,
+		help:     set.help,
+		themeSet: set.theme,
+	}
+	return m
+}
+
+func (m *WatchModel) Init() tea.Cmd {
+	return m.next()
+}
+
+// next waits for the next frame off the UI goroutine.
+func (m *WatchModel) next() tea.Cmd {
+	return func() tea.Msg {
+		f, err := m.stream.Next()
+		return frameMsg{frame: f, err: err}
+	}
+}
+
+func (m *WatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+		m.h = msg.Height
+		m.ready = true
+		return m, nil
+	case frameMsg:
+		if msg.err == nil {
+			msg.err = msg.frame.Apply(&m.state)
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if msg.frame.Key {
+			m.login, m.lives = msg.frame.Login, msg.frame.Lives
+			m.started = true
+		}
+		m.speed, m.pace = msg.frame.Speed, msg.frame.Pace
+		return m, m.next()
+	case tea.KeyMsg:
+		switch m.keys[msg.String()] {
+		case actionQuit:
+			return m, tea.Quit
+		case actionTheme:
+			m.cycleTheme()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// overlay returns the overlay over the board, or nil while it is in play.
+func (m *WatchModel) overlay() *fieldOverlay {
+	s := &m.state
+	lines := []string{scoreLine(s), "user: " + m.login}
+	switch {
+	case m.err != nil:
+		reason := "the player has quit"
+		if !errors.Is(m.err, io.EOF) {
+			reason = m.err.Error()
+		}
+		return &fieldOverlay{Title: "STREAM ENDED", Lines: append(lines, reason), Footer: "press " + m.help.key(actionQuit) + " to quit"}
+	case s.Cleared:
+		return &fieldOverlay{Title: "CLEAR!", Lines: lines, Footer: "watching..."}
+	case s.GameOver:
+		return &fieldOverlay{Title: "GAME OVER", Lines: lines, Footer: "watching..."}
+	}
+	return nil
+}
+
+func (m *WatchModel) View() string {
+	if !m.ready {
+		return "loading...\n"
+	}
+	if !m.started {
+		if m.err != nil {
+			return "broadcast ended before the first frame: " + m.err.Error() + " (" + m.help.key(actionQuit) + " quit)\n"
+		}
+		return "waiting for the first frame...\n"
+	}
+	m.viewBuf.Reset()
+	b := &m.viewBuf
+	p := m.pal
+	s := m.state
+
+	hud := renderHUD(p, m.login, s.Score, s.BricksRemaining, s.BricksTotal, hudLives(m.lives, s.Lives), m.speed, m.pace, s.Difficulty.Name, s.Boss, "")
+	infoLine := "watching " + m.login + " (read-only; " + m.help.key(actionTheme) + " theme, " + m.help.key(actionQuit) + " quit)"
+
+	contentW := max(s.ViewW, 0)
+	leftPad := ""
+	if m.w > contentW {
+		leftPad = strings.Repeat(" ", (m.w-contentW)/2)
+	}
+	// Lines: HUD(1) + info(1) + field(height) + trailing blank(1)
+	if contentH := 1 + 1 + s.Height + 1; m.h > contentH {
+		b.WriteString(strings.Repeat("\n", (m.h-contentH)/2))
+	}
+	b.WriteString(leftPad)
+	b.WriteString(hud)
+	b.WriteString("\n")
+	b.WriteString(leftPad)
+	b.WriteString(infoLine)
+	b.WriteString("\n")
+
+	if len(m.spaceLine) != contentW {
+		m.spaceLine = strings.Repeat(" ", contentW)
+	}
+	if ov := m.overlay(); ov != nil {
+		renderFieldCanvasTo(b, p, s, ov, nil, "", leftPad, nil, &m.canvas)
+	} else {
+		var fx *fxLayer
+		if len(s.Drops) > 0 || s.Boss != nil {
+			m.fxLayer.resize(s.ViewW, s.Height)
+			fx = &m.fxLayer
+			drawDrops(fx, p, &s)
+			drawBoss(fx, p, &s)
+		}
+		if g := p.sub[m.subcell]; g != nil {
+			renderFieldSubCellTo(b, p, g, s, fx, nil, "", leftPad, m.spaceLine, -1)
+		} else {
+			renderFieldFastTo(b, p, s, fx, nil, "", leftPad, m.spaceLine, -1)
+		}
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fchimpan/gh-kusa-breaker/internal/broadcast"
+)
+
+// receive runs the watch model's pending command, which waits for a frame,
+// and feeds the result back to it.
+func receive(t *testing.T, w *WatchModel, cmd tea.Cmd) tea.Cmd {
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("the watch model stopped reading the stream")
+	}
+	_, next := w.Update(cmd())
+	return next
+}
+
+func TestWatchModel_MirrorsThePlayer(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := broadcast.NewServer(ln)
+	defer srv.Close()
+
+	player := NewModel("alice", versusCalendar(), 1, Options{ASCII: true, Speed: 1.5, Lives: 3, Broadcast: srv})
+	player.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	player.introActive = false
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := broadcast.Dial(ctx, ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	watch := NewWatchModel(stream, Options{ASCII: true})
+	watch.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	if v := watch.View(); !strings.Contains(v, "waiting for the first frame") {
+		t.Fatalf("expected a waiting message before the first frame:\n%s", v)
+	}
+
+	// Play a little; the watcher follows frame by frame. Ticks that change
+	// nothing send nothing.
+	now := time.Now()
+	player.Update(tickMsg(now)) // starts the clock
+	cmd := watch.Init()
+	for range 60 {
+		now = now.Add(time.Second / 60)
+		player.Update(tickMsg(now))
+		// Positions travel as float32.
+		for !watch.started || watch.state.BallX != float64(float32(player.state.BallX)) || watch.state.BallY != float64(float32(player.state.BallY)) {
+			cmd = receive(t, watch, cmd)
+		}
+	}
+	if watch.login != "alice" || watch.speed != 1.5 || watch.state.BricksRemaining != player.state.BricksRemaining {
+		t.Fatalf("the watcher should follow the player: %q, %v, %d blocks", watch.login, watch.speed, watch.state.BricksRemaining)
+	}
+	v := watch.View()
+	if !strings.Contains(v, "watching alice") || !strings.Contains(v, "1.50x") || !strings.Contains(v, fmt.Sprintf("lives %d", player.state.Lives)) {
+		t.Fatalf("expected the player's HUD and a read-only notice:\n%s", v)
+	}
+
+	// Keys other than theme and quit do nothing.
+	ballX := watch.state.BallX
+	watch.Update(key("a"))
+	if watch.state.BallX != ballX {
+		t.Fatalf("a watcher must not steer")
+	}
+	if _, cmd := watch.Update(key("q")); cmd == nil {
+		t.Fatalf("q should quit")
+	}
+
+	// The player quitting ends the stream.
+	srv.Close()
+	for range 100 {
+		if cmd = receive(t, watch, cmd); watch.err != nil {
+			break
+		}
+	}
+	if v := watch.View(); !strings.Contains(v, "STREAM ENDED") {
+		t.Fatalf("expected the end of the stream to show:\n%s", v)
+	}
+}